@@ -0,0 +1,416 @@
+// Package config loads the server and store configuration.
+//
+// Values are resolved in order of increasing precedence: defaults, a YAML
+// file, environment variables and command line flags. Every setting has a
+// flag, e.g. -mysql-host, and an environment variable named after it, e.g.
+// MYSQL_HOST.
+package config
+
+import (
+	"account-transactions/model"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "********"
+
+type Config struct {
+	LogLevel string       `yaml:"log_level"`
+	Server   ServerConfig `yaml:"server"`
+	Store    StoreConfig  `yaml:"store"`
+}
+
+type ServerConfig struct {
+	// Addr is the address to listen on, e.g. ":8080".
+	Addr string `yaml:"addr"`
+	// SwaggerURL points the Swagger UI at the API definition.
+	SwaggerURL string `yaml:"swagger_url"`
+	// ReadTimeout, WriteTimeout and IdleTimeout are set on the
+	// http.Server. WriteTimeout should exceed RequestTimeout so timed out
+	// requests can still write their error.
+	ReadTimeout  time.Duration `yaml:"read_timeout"`
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout"`
+	// ShutdownTimeout is how long in-flight requests get to finish once
+	// the server is asked to stop.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// RequestTimeout is the deadline for handling one request, 0 for none.
+	RequestTimeout time.Duration `yaml:"request_timeout"`
+	// IdempotencyTTL is how long Idempotency-Key responses are kept.
+	IdempotencyTTL time.Duration `yaml:"idempotency_ttl"`
+	// SettlementOrder is parsed by model.ParseSettlementPolicy.
+	SettlementOrder string `yaml:"settlement_order"`
+	// AuthorizationTTL is how long an authorization holds credit before
+	// it expires unless captured or voided.
+	AuthorizationTTL time.Duration `yaml:"authorization_ttl"`
+	// AuthorizationSweepInterval is how often expired authorizations are
+	// marked as such and expired Idempotency-Key records deleted.
+	AuthorizationSweepInterval time.Duration `yaml:"authorization_sweep_interval"`
+	// InstallmentRounding is parsed by model.ParseInstallmentRounding.
+	InstallmentRounding string `yaml:"installment_rounding"`
+}
+
+type StoreConfig struct {
+	// Driver is the store backend: mysql, postgres, sqlite or memory.
+	// "sqlite://path" selects sqlite and sets SQLite.Path.
+	Driver string `yaml:"driver"`
+	// AutoMigrate applies pending schema migrations at startup.
+	AutoMigrate bool `yaml:"auto_migrate"`
+	// FXRatesFile is a CSV file of exchange rates loaded into the store
+	// at startup, see model.ParseFXRatesCSV. Empty loads none.
+	FXRatesFile string         `yaml:"fx_rates_file"`
+	MySQL       MySQLConfig    `yaml:"mysql"`
+	Postgres    PostgresConfig `yaml:"postgres"`
+	SQLite      SQLiteConfig   `yaml:"sqlite"`
+}
+
+// PoolConfig sizes a database/sql connection pool.
+type PoolConfig struct {
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+}
+
+type MySQLConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Database string `yaml:"database"`
+
+	PoolConfig `yaml:",inline"`
+
+	// Timeouts for dialing, and for reading and writing on a connection.
+	DialTimeout  time.Duration `yaml:"dial_timeout"`
+	ReadTimeout  time.Duration `yaml:"read_timeout"`
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+}
+
+type PostgresConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Database string `yaml:"database"`
+	// SSLMode is passed as sslmode, e.g. disable or require.
+	SSLMode string `yaml:"ssl_mode"`
+
+	PoolConfig `yaml:",inline"`
+
+	ConnectTimeout time.Duration `yaml:"connect_timeout"`
+}
+
+type SQLiteConfig struct {
+	// Path is the database file, created if it does not exist.
+	Path string `yaml:"path"`
+	// BusyTimeout is how long a write waits for another to finish before
+	// failing with "database is locked".
+	BusyTimeout time.Duration `yaml:"busy_timeout"`
+}
+
+// sqliteScheme prefixes a SQLite database path given as the store, e.g.
+// sqlite://data/store.db.
+const sqliteScheme = "sqlite://"
+
+var defaultPool = PoolConfig{
+	MaxOpenConns:    25,
+	MaxIdleConns:    25,
+	ConnMaxLifetime: 5 * time.Minute,
+}
+
+// Default returns the configuration for running the server locally against
+// the MySQL container from docker-compose.yaml.
+func Default() Config {
+	return Config{
+		LogLevel: "info",
+		Server: ServerConfig{
+			Addr:            ":8080",
+			SwaggerURL:      "http://localhost:8080/swagger/doc.json",
+			ReadTimeout:     15 * time.Second,
+			WriteTimeout:    30 * time.Second,
+			IdleTimeout:     60 * time.Second,
+			ShutdownTimeout: 30 * time.Second,
+			RequestTimeout:  10 * time.Second,
+			IdempotencyTTL:  24 * time.Hour,
+			SettlementOrder: "oldest-first",
+
+			AuthorizationTTL:           7 * 24 * time.Hour,
+			AuthorizationSweepInterval: time.Minute,
+			InstallmentRounding:        "first",
+		},
+		Store: StoreConfig{
+			Driver: "mysql",
+			MySQL: MySQLConfig{
+				Host:         "0.0.0.0",
+				Port:         3306,
+				User:         "storeuser",
+				Password:     "example",
+				Database:     "store",
+				PoolConfig:   defaultPool,
+				DialTimeout:  5 * time.Second,
+				ReadTimeout:  30 * time.Second,
+				WriteTimeout: 30 * time.Second,
+			},
+			Postgres: PostgresConfig{
+				Host:           "0.0.0.0",
+				Port:           5432,
+				User:           "storeuser",
+				Password:       "example",
+				Database:       "store",
+				SSLMode:        "disable",
+				PoolConfig:     defaultPool,
+				ConnectTimeout: 5 * time.Second,
+			},
+			SQLite: SQLiteConfig{
+				Path:        "store.db",
+				BusyTimeout: 5 * time.Second,
+			},
+		},
+	}
+}
+
+// Load resolves the configuration from the command line arguments, the
+// environment and the YAML file named by -config or CONFIG_FILE.
+func Load(args []string) (Config, error) {
+	return load(args, os.LookupEnv)
+}
+
+func load(args []string, lookupEnv func(string) (string, bool)) (Config, error) {
+	cfg := Default()
+
+	fs := flag.NewFlagSet("account-transactions", flag.ContinueOnError)
+	configFile := fs.String("config", "", "path to a YAML config file")
+	cfg.bind(fs)
+	if err := fs.Parse(args); err != nil {
+		return cfg, err
+	}
+
+	// Flags were parsed into cfg, but must win over the file and the
+	// environment, so note them now and apply them again last.
+	setFlags := map[string]string{}
+	fs.Visit(func(f *flag.Flag) {
+		setFlags[f.Name] = f.Value.String()
+	})
+
+	if *configFile == "" {
+		*configFile, _ = lookupEnv("CONFIG_FILE")
+	}
+	cfg = Default()
+	if *configFile != "" {
+		if err := cfg.loadFile(*configFile); err != nil {
+			return cfg, err
+		}
+	}
+
+	var errs []error
+	fs.VisitAll(func(f *flag.Flag) {
+		if f.Name == "config" {
+			return
+		}
+		if v, ok := lookupEnv(envName(f.Name)); ok {
+			if err := f.Value.Set(v); err != nil {
+				errs = append(errs, fmt.Errorf("invalid %s %q: %v", envName(f.Name), v, err))
+			}
+		}
+	})
+	for name, v := range setFlags {
+		if err := fs.Set(name, v); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return cfg, err
+	}
+
+	if path, ok := strings.CutPrefix(cfg.Store.Driver, sqliteScheme); ok {
+		cfg.Store.Driver, cfg.Store.SQLite.Path = "sqlite", path
+	}
+	return cfg, cfg.Validate()
+}
+
+// bind registers a flag for every setting, writing into cfg.
+func (cfg *Config) bind(fs *flag.FlagSet) {
+	fs.StringVar(&cfg.LogLevel, "log-level", cfg.LogLevel, "log level: debug, info, warn or error")
+
+	fs.StringVar(&cfg.Server.Addr, "listen-addr", cfg.Server.Addr, "address to listen on")
+	fs.StringVar(&cfg.Server.SwaggerURL, "swagger-url", cfg.Server.SwaggerURL, "URL of the API definition used by the Swagger UI")
+	fs.DurationVar(&cfg.Server.ReadTimeout, "read-timeout", cfg.Server.ReadTimeout, "maximum time to read a request, 0 for none")
+	fs.DurationVar(&cfg.Server.WriteTimeout, "write-timeout", cfg.Server.WriteTimeout, "maximum time to write a response, 0 for none")
+	fs.DurationVar(&cfg.Server.IdleTimeout, "idle-timeout", cfg.Server.IdleTimeout, "how long idle keep-alive connections are kept, 0 for the read timeout")
+	fs.DurationVar(&cfg.Server.ShutdownTimeout, "shutdown-timeout", cfg.Server.ShutdownTimeout, "how long in-flight requests get to finish on shutdown")
+	fs.DurationVar(&cfg.Server.RequestTimeout, "request-timeout", cfg.Server.RequestTimeout, "deadline for handling one request, 0 for none")
+	fs.DurationVar(&cfg.Server.IdempotencyTTL, "idempotency-ttl", cfg.Server.IdempotencyTTL, "how long Idempotency-Key responses are kept")
+	fs.StringVar(&cfg.Server.SettlementOrder, "settlement-order", cfg.Server.SettlementOrder, "order payments settle debts in: oldest-first or operation type IDs, e.g. 3,1,2")
+	fs.DurationVar(&cfg.Server.AuthorizationTTL, "authorization-ttl", cfg.Server.AuthorizationTTL, "how long an authorization holds credit unless captured or voided")
+	fs.DurationVar(&cfg.Server.AuthorizationSweepInterval, "authorization-sweep-interval", cfg.Server.AuthorizationSweepInterval, "how often expired authorizations and idempotency keys are swept")
+	fs.StringVar(&cfg.Server.InstallmentRounding, "installment-rounding", cfg.Server.InstallmentRounding, "installment that takes the cents left over when a purchase does not split evenly: first or last")
+
+	fs.StringVar(&cfg.Store.Driver, "store", cfg.Store.Driver, "store backend to use: mysql, postgres, sqlite, sqlite://path or memory")
+	fs.BoolVar(&cfg.Store.AutoMigrate, "auto-migrate", cfg.Store.AutoMigrate, "apply pending schema migrations at startup")
+	fs.StringVar(&cfg.Store.FXRatesFile, "fx-rates-file", cfg.Store.FXRatesFile, "CSV file of exchange rates to load at startup")
+
+	my := &cfg.Store.MySQL
+	fs.StringVar(&my.Host, "mysql-host", my.Host, "MySQL host")
+	fs.IntVar(&my.Port, "mysql-port", my.Port, "MySQL port")
+	fs.StringVar(&my.User, "mysql-user", my.User, "MySQL user")
+	fs.StringVar(&my.Password, "mysql-password", my.Password, "MySQL password")
+	fs.StringVar(&my.Database, "mysql-database", my.Database, "MySQL database")
+	my.PoolConfig.bind(fs, "mysql")
+	fs.DurationVar(&my.DialTimeout, "mysql-dial-timeout", my.DialTimeout, "timeout for connecting to MySQL")
+	fs.DurationVar(&my.ReadTimeout, "mysql-read-timeout", my.ReadTimeout, "I/O read timeout on MySQL connections")
+	fs.DurationVar(&my.WriteTimeout, "mysql-write-timeout", my.WriteTimeout, "I/O write timeout on MySQL connections")
+
+	pg := &cfg.Store.Postgres
+	fs.StringVar(&pg.Host, "postgres-host", pg.Host, "Postgres host")
+	fs.IntVar(&pg.Port, "postgres-port", pg.Port, "Postgres port")
+	fs.StringVar(&pg.User, "postgres-user", pg.User, "Postgres user")
+	fs.StringVar(&pg.Password, "postgres-password", pg.Password, "Postgres password")
+	fs.StringVar(&pg.Database, "postgres-database", pg.Database, "Postgres database")
+	fs.StringVar(&pg.SSLMode, "postgres-ssl-mode", pg.SSLMode, "Postgres sslmode, e.g. disable or require")
+	pg.PoolConfig.bind(fs, "postgres")
+	fs.DurationVar(&pg.ConnectTimeout, "postgres-connect-timeout", pg.ConnectTimeout, "timeout for connecting to Postgres")
+
+	lite := &cfg.Store.SQLite
+	fs.StringVar(&lite.Path, "sqlite-path", lite.Path, "SQLite database file")
+	fs.DurationVar(&lite.BusyTimeout, "sqlite-busy-timeout", lite.BusyTimeout, "how long a SQLite write waits for a concurrent one")
+}
+
+// bind registers the pool flags with the given prefix, e.g.
+// -mysql-max-open-conns.
+func (p *PoolConfig) bind(fs *flag.FlagSet, prefix string) {
+	fs.IntVar(&p.MaxOpenConns, prefix+"-max-open-conns", p.MaxOpenConns, "maximum open connections, 0 for unlimited")
+	fs.IntVar(&p.MaxIdleConns, prefix+"-max-idle-conns", p.MaxIdleConns, "maximum idle connections")
+	fs.DurationVar(&p.ConnMaxLifetime, prefix+"-conn-max-lifetime", p.ConnMaxLifetime, "maximum time a connection is reused, 0 for forever")
+}
+
+func (cfg *Config) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+	defer f.Close()
+
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil {
+		return fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// envName returns the environment variable for a flag, e.g. MYSQL_HOST for
+// mysql-host.
+func envName(flagName string) string {
+	return strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// Validate reports every invalid setting.
+func (cfg Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	_, err := cfg.SlogLevel()
+	check(err == nil, "invalid log level %q", cfg.LogLevel)
+
+	check(cfg.Server.Addr != "", "listen address is required")
+	u, err := url.Parse(cfg.Server.SwaggerURL)
+	check(err == nil && u.Scheme != "" && u.Host != "", "invalid swagger URL %q", cfg.Server.SwaggerURL)
+	check(cfg.Server.ReadTimeout >= 0 && cfg.Server.WriteTimeout >= 0 && cfg.Server.IdleTimeout >= 0, "server timeouts must not be negative")
+	check(cfg.Server.ShutdownTimeout > 0, "shutdown timeout must be positive")
+	check(cfg.Server.RequestTimeout >= 0, "request timeout must not be negative")
+	check(cfg.Server.WriteTimeout == 0 || cfg.Server.RequestTimeout == 0 || cfg.Server.WriteTimeout > cfg.Server.RequestTimeout,
+		"write timeout %s must be longer than the request timeout %s", cfg.Server.WriteTimeout, cfg.Server.RequestTimeout)
+	check(cfg.Server.IdempotencyTTL > 0, "idempotency TTL must be positive")
+	_, err = model.ParseSettlementPolicy(cfg.Server.SettlementOrder)
+	check(err == nil, "invalid settlement order %q", cfg.Server.SettlementOrder)
+	check(cfg.Server.AuthorizationTTL > 0, "authorization TTL must be positive")
+	check(cfg.Server.AuthorizationSweepInterval > 0, "authorization sweep interval must be positive")
+	_, err = model.ParseInstallmentRounding(cfg.Server.InstallmentRounding)
+	check(err == nil, "invalid installment rounding %q", cfg.Server.InstallmentRounding)
+
+	switch cfg.Store.Driver {
+	case "memory":
+	case "mysql":
+		my := cfg.Store.MySQL
+		check(my.Host != "", "MySQL host is required")
+		check(my.Port > 0 && my.Port < 65536, "invalid MySQL port %d", my.Port)
+		check(my.User != "", "MySQL user is required")
+		check(my.Database != "", "MySQL database is required")
+		errs = append(errs, my.PoolConfig.validate("MySQL")...)
+		check(my.DialTimeout >= 0 && my.ReadTimeout >= 0 && my.WriteTimeout >= 0, "MySQL timeouts must not be negative")
+	case "postgres":
+		pg := cfg.Store.Postgres
+		check(pg.Host != "", "Postgres host is required")
+		check(pg.Port > 0 && pg.Port < 65536, "invalid Postgres port %d", pg.Port)
+		check(pg.User != "", "Postgres user is required")
+		check(pg.Database != "", "Postgres database is required")
+		check(slices.Contains(sslModes, pg.SSLMode), "invalid Postgres SSL mode %q", pg.SSLMode)
+		errs = append(errs, pg.PoolConfig.validate("Postgres")...)
+		check(pg.ConnectTimeout >= 0, "Postgres connect timeout must not be negative")
+	case "sqlite":
+		lite := cfg.Store.SQLite
+		check(lite.Path != "", "SQLite path is required")
+		check(!strings.Contains(lite.Path, "?"), "SQLite path %q must not contain '?'", lite.Path)
+		check(lite.BusyTimeout >= 0, "SQLite busy timeout must not be negative")
+	default:
+		check(false, "unknown store %q", cfg.Store.Driver)
+	}
+
+	return errors.Join(errs...)
+}
+
+// sslModes are the sslmode values libpq accepts.
+var sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+func (p PoolConfig) validate(name string) []error {
+	var errs []error
+	if p.MaxOpenConns < 0 {
+		errs = append(errs, fmt.Errorf("%s max open connections must not be negative", name))
+	}
+	if p.MaxIdleConns < 0 {
+		errs = append(errs, fmt.Errorf("%s max idle connections must not be negative", name))
+	}
+	if p.ConnMaxLifetime < 0 {
+		errs = append(errs, fmt.Errorf("%s connection max lifetime must not be negative", name))
+	}
+	return errs
+}
+
+// SlogLevel returns the log level.
+func (cfg Config) SlogLevel() (slog.Level, error) {
+	var level slog.Level
+	err := level.UnmarshalText([]byte(cfg.LogLevel))
+	return level, err
+}
+
+// Redacted returns a copy with secrets replaced, safe to log.
+func (cfg Config) Redacted() Config {
+	for _, password := range []*string{&cfg.Store.MySQL.Password, &cfg.Store.Postgres.Password} {
+		if *password != "" {
+			*password = redacted
+		}
+	}
+	return cfg
+}
+
+// String returns the configuration as YAML with secrets redacted.
+func (cfg Config) String() string {
+	out, err := yaml.Marshal(cfg.Redacted())
+	if err != nil {
+		return err.Error()
+	}
+	return string(out)
+}
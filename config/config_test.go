@@ -0,0 +1,170 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+}
+
+func writeFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	// When.
+	cfg, err := load(nil, env(nil))
+
+	// Then.
+	require.NoError(t, err)
+	assert.Equal(t, Default(), cfg)
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	// Given.
+	path := writeFile(t, `
+log_level: warn
+server:
+  addr: ":9000"
+  idempotency_ttl: 1h
+store:
+  fx_rates_file: rates.csv
+  mysql:
+    host: file-host
+    port: 3307
+    user: file-user
+`)
+
+	// When.
+	cfg, err := load(
+		[]string{"-config", path, "-mysql-host", "flag-host"},
+		env(map[string]string{"MYSQL_HOST": "env-host", "MYSQL_PORT": "3308"}),
+	)
+
+	// Then.
+	require.NoError(t, err)
+	assert.Equal(t, "warn", cfg.LogLevel)                 // File.
+	assert.Equal(t, ":9000", cfg.Server.Addr)             // File.
+	assert.Equal(t, time.Hour, cfg.Server.IdempotencyTTL) // File.
+	assert.Equal(t, "file-user", cfg.Store.MySQL.User)    // File.
+	assert.Equal(t, "rates.csv", cfg.Store.FXRatesFile)   // File.
+	assert.Equal(t, 3308, cfg.Store.MySQL.Port)           // Env over file.
+	assert.Equal(t, "flag-host", cfg.Store.MySQL.Host)    // Flag over env.
+	assert.Equal(t, "store", cfg.Store.MySQL.Database)    // Default.
+	assert.Equal(t, 25, cfg.Store.MySQL.MaxOpenConns)     // Default.
+}
+
+func TestLoad_ConfigFileFromEnv(t *testing.T) {
+	// Given.
+	path := writeFile(t, "store:\n  driver: memory\n")
+
+	// When.
+	cfg, err := load(nil, env(map[string]string{"CONFIG_FILE": path}))
+
+	// Then.
+	require.NoError(t, err)
+	assert.Equal(t, "memory", cfg.Store.Driver)
+}
+
+func TestLoad_SQLiteDSN(t *testing.T) {
+	// When.
+	cfg, err := load(nil, env(map[string]string{"STORE": "sqlite:///var/lib/store.db"}))
+
+	// Then.
+	require.NoError(t, err)
+	assert.Equal(t, "sqlite", cfg.Store.Driver)
+	assert.Equal(t, "/var/lib/store.db", cfg.Store.SQLite.Path)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+		file string
+	}{
+		{name: "unknown flag", args: []string{"-nope"}},
+		{name: "invalid env", env: map[string]string{"MYSQL_PORT": "abc"}},
+		{name: "unknown file field", file: "server:\n  port: 80\n"},
+		{name: "missing file", args: []string{"-config", "/does/not/exist.yaml"}},
+		{name: "invalid value", args: []string{"-store", "oracle"}},
+		{name: "empty sqlite path", args: []string{"-store", "sqlite://"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Given.
+			args := test.args
+			if test.file != "" {
+				args = append(args, "-config", writeFile(t, test.file))
+			}
+
+			// When.
+			_, err := load(args, env(test.env))
+
+			// Then.
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	// Given.
+	cfg := Default()
+	cfg.LogLevel = "loud"
+	cfg.Server.SwaggerURL = "doc.json"
+	cfg.Server.SettlementOrder = "newest-first"
+	cfg.Store.MySQL.Port = 0
+	cfg.Store.MySQL.MaxIdleConns = -1
+	cfg.Server.WriteTimeout = 5 * time.Second
+	cfg.Server.AuthorizationTTL = 0
+	cfg.Server.InstallmentRounding = "middle"
+
+	// When.
+	err := cfg.Validate()
+
+	// Then.
+	require.Error(t, err)
+	for _, msg := range []string{"log level", "swagger URL", "settlement order", "MySQL port", "max idle", "write timeout", "authorization TTL", "installment rounding"} {
+		assert.Contains(t, err.Error(), msg)
+	}
+
+	// Postgres settings are checked when it is the store.
+	cfg = Default()
+	cfg.Store.Driver = "postgres"
+	cfg.Store.Postgres.SSLMode = "sometimes"
+	require.ErrorContains(t, cfg.Validate(), "SSL mode")
+
+	// MySQL settings are not needed by the memory store.
+	cfg = Default()
+	cfg.Store.Driver = "memory"
+	cfg.Store.MySQL = MySQLConfig{}
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestString_RedactsSecrets(t *testing.T) {
+	// Given.
+	cfg := Default()
+	cfg.Store.MySQL.Password = "hunter2"
+	cfg.Store.Postgres.Password = "hunter3"
+
+	// When.
+	out := cfg.String()
+
+	// Then.
+	assert.NotContains(t, out, "hunter2")
+	assert.NotContains(t, out, "hunter3")
+	assert.Contains(t, out, redacted)
+	assert.Equal(t, "hunter2", cfg.Store.MySQL.Password)
+}
@@ -28,6 +28,14 @@ const docTemplate = `{
                     "account"
                 ],
                 "summary": "Create a new account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Replays the original response when the request is retried",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
                 "responses": {
                     "201": {
                         "description": "Created",
@@ -35,10 +43,22 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.AccountImpl"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/server.ErrorResponse"
                         }
                     }
                 }
@@ -67,21 +87,139 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/server.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/transactions": {
-            "post": {
-                "description": "Creates a transaction with the provided account ID, operation type ID, and amount.",
+        "/accounts/{accountId}/balance": {
+            "get": {
+                "description": "Sum up the account's outstanding debt per operation type, its available credit and the unapplied credit of overpayments.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "account"
+                ],
+                "summary": "Retrieves an account's balance",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Account ID",
+                        "name": "accountId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.AccountBalance"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{accountId}/statement": {
+            "get": {
+                "description": "List the account's transactions in a period, oldest first, with the balance before and after it. Balances are the sum of the amounts, so they are negative while the account owes money.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "account"
+                ],
+                "summary": "Retrieves an account's statement",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Account ID",
+                        "name": "accountId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the period as an RFC 3339 time, inclusive (default: the first transaction)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the period as an RFC 3339 time, exclusive (default: now)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Statement"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{accountId}/transactions": {
+            "get": {
+                "description": "List the transactions of an account, one page at a time. Pass the returned next_cursor as cursor to get the next page.",
                 "consumes": [
                     "application/json"
                 ],
@@ -91,56 +229,1740 @@ const docTemplate = `{
                 "tags": [
                     "transaction"
                 ],
-                "summary": "Create a new transaction",
+                "summary": "Lists an account's transactions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Account ID",
+                        "name": "accountId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only list this operation type",
+                        "name": "operation_type_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only list transactions at or after this RFC 3339 time",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only list transactions before this RFC 3339 time",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order by event date: asc (default) or desc",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, at most 200 (default 50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.TransactionImpl"
+                            "$ref": "#/definitions/model.TransactionPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/server.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "model.AccountImpl": {
-            "type": "object",
-            "properties": {
-                "account_id": {
-                    "type": "integer"
-                },
-                "document_number": {
-                    "type": "string"
+        },
+        "/admin/accounts/{accountId}/block": {
+            "post": {
+                "description": "Blocks an active account and records the change in its history. Blocked accounts accept payments and other credits but reject purchases and other debits with 422.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Block an account",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Account ID",
+                        "name": "accountId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason for the change",
+                        "name": "update",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AccountStatusUpdate"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the original response when the request is retried",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.AccountStatusChange"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "model.TransactionImpl": {
-            "type": "object",
-            "properties": {
-                "account_id": {
-                    "type": "integer"
-                },
-                "amount": {
-                    "type": "number"
-                },
-                "operation_type_id": {
-                    "type": "integer"
-                },
-                "transaction_id": {
-                    "type": "integer"
+        "/admin/accounts/{accountId}/close": {
+            "post": {
+                "description": "Closes an active or blocked account whose balance, the sum of its transactions, is zero and that has no pending authorizations, and records the change in its history. Closed accounts accept no transactions and cannot be reopened.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Close an account",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Account ID",
+                        "name": "accountId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason for the change",
+                        "name": "update",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AccountStatusUpdate"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the original response when the request is retried",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.AccountStatusChange"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/accounts/{accountId}/credit-limit": {
+            "put": {
+                "description": "Sets the account's credit limit and records the change in its history. Debits that would take the outstanding debt past the limit are rejected.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Change an account's credit limit",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Account ID",
+                        "name": "accountId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New credit limit and the reason for the change",
+                        "name": "update",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreditLimitUpdate"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the original response when the request is retried",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.CreditLimitChange"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/accounts/{accountId}/credit-limit/history": {
+            "get": {
+                "description": "List every change of the account's credit limit, oldest first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Lists an account's credit limit history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Account ID",
+                        "name": "accountId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.CreditLimitHistory"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/accounts/{accountId}/status/history": {
+            "get": {
+                "description": "List every block, unblock and close of the account, oldest first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Lists an account's status history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Account ID",
+                        "name": "accountId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.AccountStatusHistory"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/accounts/{accountId}/unblock": {
+            "post": {
+                "description": "Makes a blocked account active again and records the change in its history.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Unblock an account",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Account ID",
+                        "name": "accountId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason for the change",
+                        "name": "update",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AccountStatusUpdate"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the original response when the request is retried",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.AccountStatusChange"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/fx-rates": {
+            "get": {
+                "description": "List every stored exchange rate, by base and then quote currency.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List exchange rates",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.FXRateList"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Stores the given exchange rates, replacing any already stored for the same currencies. Rates only convert from their base to their quote currency.\nSend JSON, or CSV with the header base_currency,quote_currency,rate and Content-Type text/csv.",
+                "consumes": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Load exchange rates",
+                "parameters": [
+                    {
+                        "description": "Exchange rates to store",
+                        "name": "rates",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.FXRateList"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the original response when the request is retried",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.FXRateList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/authorizations": {
+            "post": {
+                "description": "Holds the amount of a debit against the account's available credit until it is captured, voided or expires after the configured TTL.\nThe hold counts against the credit limit like a posted debit; only debit operations can be authorized.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authorization"
+                ],
+                "summary": "Authorize a debit",
+                "parameters": [
+                    {
+                        "description": "Debit to authorize",
+                        "name": "authorization",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AuthorizationRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the original response when the request is retried",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.AuthorizationImpl"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/authorizations/{authorizationId}": {
+            "get": {
+                "description": "Retrieve an authorization with the provided authorization ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authorization"
+                ],
+                "summary": "Retrieves an authorization by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Authorization ID",
+                        "name": "authorizationId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.AuthorizationImpl"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/authorizations/{authorizationId}/capture": {
+            "post": {
+                "description": "Posts the authorized debit, or part of it, as a transaction and releases the hold. Leave out the amount to capture all of it; the rest of a partial capture is released.\nThe capture is paid from unapplied payment credit like any debit, but is not checked against the credit limit again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authorization"
+                ],
+                "summary": "Capture an authorization",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Authorization ID",
+                        "name": "authorizationId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Amount to capture",
+                        "name": "capture",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CaptureRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the original response when the request is retried",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.TransactionImpl"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/authorizations/{authorizationId}/void": {
+            "post": {
+                "description": "Releases the authorization's hold without posting anything.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authorization"
+                ],
+                "summary": "Void an authorization",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Authorization ID",
+                        "name": "authorizationId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the original response when the request is retried",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.AuthorizationImpl"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/installment-plans/{planId}/schedule": {
+            "get": {
+                "description": "Lists the plan's installments, soonest due first, with whether each is paid, unpaid or not due yet, and how much of the plan is paid and still owed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Retrieves an installment plan's schedule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Installment plan ID",
+                        "name": "planId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.InstallmentSchedule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions": {
+            "post": {
+                "description": "Creates a transaction with the provided account ID, operation type ID, and amount.\nDebit operations are stored with a negative amount and credits with a positive one.\nDebits are first paid from the unapplied balance of earlier overpayments, oldest first.\nDebits that exceed the account's available credit are rejected with 422.\nAn installment purchase (operation type 2) sent with installments is split into that many monthly debits, the first due at once, and the installment schedule is returned instead of a transaction.\nBlocked accounts reject debits and closed accounts reject every transaction with 422.\nA transaction sent in another currency than the account's is converted with the stored exchange rate and keeps its original amount, currency and rate; it is rejected with 422 if there is no rate.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Create a new transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Replays the original response when the request is retried",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.TransactionImpl"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/{transactionId}": {
+            "get": {
+                "description": "Retrieve a transaction with the provided transaction ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Retrieves a transaction by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "transactionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.TransactionImpl"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/{transactionId}/reversal": {
+            "post": {
+                "description": "Creates a refund for a debit or a payment reversal for a credit, linked to the original by original_transaction_id.\nLeave out the amount to reverse all that is left of the original; a transaction can be reversed in parts until nothing is left.\nThe reversal first cancels the original's open balance. A refund then settles other debts like a payment, and a payment reversal draws on other payments' credit like a debit.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Reverse a transaction",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "transactionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Amount to reverse",
+                        "name": "reversal",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ReversalRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the original response when the request is retried",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.TransactionImpl"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers": {
+            "post": {
+                "description": "Posts a TRANSFER OUT debit on the source account and a TRANSFER IN credit on the destination at once, both linked to the transfer.\nThe debit is paid from the source's unapplied payment credit first and is rejected with 422 if the rest exceeds its available credit.\nThe credit settles the destination's debts like a payment. Transfers to the same account are rejected.\nThe source must be active and the destination must not be closed, or the transfer is rejected with 422.\nThe amount is in the source account's currency. It is converted with the stored exchange rate when the destination's currency differs, and rejected with 422 if there is no rate.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "Transfer funds between accounts",
+                "parameters": [
+                    {
+                        "description": "Accounts and amount to transfer",
+                        "name": "transfer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TransferRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the original response when the request is retried",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Transfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers/{transferId}": {
+            "get": {
+                "description": "Retrieve a transfer with its debit and credit.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "Retrieves a transfer by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transfer ID",
+                        "name": "transferId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Transfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "model.AccountBalance": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "available_credit": {
+                    "description": "AvailableCredit is how much more the account may owe, scheduled\ninstallments and holds included. It is left out if the account has\nno credit limit.",
+                    "type": "number"
+                },
+                "credit_limit": {
+                    "type": "number"
+                },
+                "currency": {
+                    "description": "Currency is the account's, which all the amounts are in.",
+                    "type": "string"
+                },
+                "debts": {
+                    "description": "Debts breaks Outstanding down by operation type.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.OperationDebt"
+                    }
+                },
+                "held": {
+                    "description": "Held is what pending authorizations reserve for their captures.",
+                    "type": "number"
+                },
+                "outstanding": {
+                    "description": "Outstanding is the unpaid debt, as a positive amount.",
+                    "type": "number"
+                },
+                "payment_credit": {
+                    "description": "PaymentCredit is what overpayments left to pay future debits.",
+                    "type": "number"
+                },
+                "scheduled": {
+                    "description": "Scheduled is what installments that are not due yet will owe.",
+                    "type": "number"
+                }
+            }
+        },
+        "model.AccountImpl": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "credit_limit": {
+                    "description": "CreditLimit caps the account's outstanding debt, nil for no limit.",
+                    "type": "number"
+                },
+                "currency": {
+                    "description": "Currency is what the account's transactions are booked in,\nDefaultCurrency if left out when the account is opened.",
+                    "type": "string"
+                },
+                "document_number": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status is changed through the block, unblock and close endpoints.\nAccounts are opened active.",
+                    "type": "string"
+                }
+            }
+        },
+        "model.AccountStatusChange": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "change_id": {
+                    "type": "integer"
+                },
+                "changed_at": {
+                    "type": "string"
+                },
+                "new_status": {
+                    "type": "string"
+                },
+                "old_status": {
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "Reason says why the status was changed, e.g. a fraud report.",
+                    "type": "string"
+                }
+            }
+        },
+        "model.AccountStatusHistory": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AccountStatusChange"
+                    }
+                }
+            }
+        },
+        "model.AccountStatusUpdate": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AuthorizationImpl": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "amount": {
+                    "description": "Amount is the amount held, stored negative like the debit.",
+                    "type": "number"
+                },
+                "authorization_id": {
+                    "type": "integer"
+                },
+                "closed_at": {
+                    "description": "ClosedAt is when the authorization left the pending status.",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "ExpiresAt is when the hold lapses if it is neither captured nor\nvoided first.",
+                    "type": "string"
+                },
+                "operation_type_id": {
+                    "description": "OperationTypeID is the debit operation the capture is posted as.",
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.AuthorizationStatus"
+                },
+                "transaction_id": {
+                    "description": "TransactionID is the debit posted by the capture.",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.AuthorizationRequest": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "amount": {
+                    "type": "number"
+                },
+                "operation_type_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.AuthorizationStatus": {
+            "type": "string",
+            "enum": [
+                "PENDING",
+                "CAPTURED",
+                "VOIDED",
+                "EXPIRED"
+            ],
+            "x-enum-varnames": [
+                "AuthorizationPending",
+                "AuthorizationCaptured",
+                "AuthorizationVoided",
+                "AuthorizationExpired"
+            ]
+        },
+        "model.CaptureRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Amount to post, as a positive amount. When left out, the whole\nauthorized amount is posted. The rest of a partial capture is\nreleased.",
+                    "type": "number"
+                }
+            }
+        },
+        "model.CreditLimitChange": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "change_id": {
+                    "type": "integer"
+                },
+                "changed_at": {
+                    "type": "string"
+                },
+                "new_limit": {
+                    "type": "number"
+                },
+                "old_limit": {
+                    "type": "number"
+                },
+                "reason": {
+                    "description": "Reason says why the limit was changed, e.g. a credit review.",
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreditLimitHistory": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CreditLimitChange"
+                    }
+                }
+            }
+        },
+        "model.CreditLimitUpdate": {
+            "type": "object",
+            "properties": {
+                "credit_limit": {
+                    "type": "number"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.FXRate": {
+            "type": "object",
+            "properties": {
+                "base_currency": {
+                    "type": "string"
+                },
+                "quote_currency": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.FXRateList": {
+            "type": "object",
+            "properties": {
+                "rates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.FXRate"
+                    }
+                }
+            }
+        },
+        "model.Installment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "balance": {
+                    "type": "number"
+                },
+                "due_date": {
+                    "type": "string"
+                },
+                "number": {
+                    "description": "Number counts the installments from 1.",
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.InstallmentStatus"
+                },
+                "transaction_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.InstallmentSchedule": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "amount": {
+                    "description": "Amount is the purchase's total, stored negative like a debit.",
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "Currency, OriginalAmount, OriginalCurrency and FXRate are the\npurchase's. Each installment is in the same currency.",
+                    "type": "string"
+                },
+                "fx_rate": {
+                    "type": "number"
+                },
+                "installment_count": {
+                    "type": "integer"
+                },
+                "installments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Installment"
+                    }
+                },
+                "operation_type_id": {
+                    "type": "integer"
+                },
+                "original_amount": {
+                    "type": "number"
+                },
+                "original_currency": {
+                    "type": "string"
+                },
+                "paid": {
+                    "description": "Paid and Unpaid split the plan's amount, as positive amounts.",
+                    "type": "number"
+                },
+                "plan_id": {
+                    "type": "integer"
+                },
+                "unpaid": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.InstallmentStatus": {
+            "type": "string",
+            "enum": [
+                "PAID",
+                "UNPAID",
+                "SCHEDULED"
+            ],
+            "x-enum-varnames": [
+                "InstallmentPaid",
+                "InstallmentUnpaid",
+                "InstallmentScheduled"
+            ]
+        },
+        "model.OperationDebt": {
+            "type": "object",
+            "properties": {
+                "operation_type_id": {
+                    "type": "integer"
+                },
+                "outstanding": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.ReversalRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Amount to reverse, as a positive amount. When left out, whatever\nearlier reversals left of the transaction is reversed.",
+                    "type": "number"
+                }
+            }
+        },
+        "model.Statement": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "closing_balance": {
+                    "description": "ClosingBalance is OpeningBalance plus the period's transactions.",
+                    "type": "number"
+                },
+                "from": {
+                    "description": "From and To bound the period like TransactionFilter. Either may be\nleft out to leave the period open on that side.",
+                    "type": "string"
+                },
+                "opening_balance": {
+                    "description": "OpeningBalance is the balance before From.",
+                    "type": "number"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "transactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TransactionImpl"
+                    }
+                }
+            }
+        },
+        "model.TransactionImpl": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "amount": {
+                    "type": "number"
+                },
+                "balance": {
+                    "type": "number"
+                },
+                "currency": {
+                    "description": "Currency is what Amount and Balance are in, always the account's.\nRequests may send an amount in another currency, which is converted.",
+                    "type": "string"
+                },
+                "due_date": {
+                    "description": "DueDate is when an installment falls due. Until then it is not\noutstanding. Other transactions are due at once.",
+                    "type": "string"
+                },
+                "event_date": {
+                    "type": "string"
+                },
+                "fx_rate": {
+                    "type": "number"
+                },
+                "installments": {
+                    "description": "Installments splits an installment purchase into that many monthly\ninstallments. It is only read from requests.",
+                    "type": "integer"
+                },
+                "operation_type_id": {
+                    "type": "integer"
+                },
+                "original_amount": {
+                    "description": "OriginalAmount and OriginalCurrency are what a converted transaction\nwas sent in, and FXRate what it was converted with.",
+                    "type": "number"
+                },
+                "original_currency": {
+                    "type": "string"
+                },
+                "original_transaction_id": {
+                    "description": "OriginalTransactionID is the transaction a reversal reverses.",
+                    "type": "integer"
+                },
+                "plan_id": {
+                    "description": "PlanID is the installment plan an installment belongs to.",
+                    "type": "integer"
+                },
+                "transaction_id": {
+                    "type": "integer"
+                },
+                "transfer_id": {
+                    "description": "TransferID is the transfer a transfer's debit or credit belongs to.",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.TransactionPage": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "description": "NextCursor is empty on the last page.",
+                    "type": "string"
+                },
+                "transactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TransactionImpl"
+                    }
+                }
+            }
+        },
+        "model.Transfer": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Amount is the amount moved, as a positive amount.",
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "credit": {
+                    "$ref": "#/definitions/model.TransactionImpl"
+                },
+                "debit": {
+                    "description": "Debit and Credit are the transactions posted on the source and the\ndestination account.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.TransactionImpl"
+                        }
+                    ]
+                },
+                "destination_account_id": {
+                    "type": "integer"
+                },
+                "source_account_id": {
+                    "type": "integer"
+                },
+                "transfer_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.TransferRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Amount to move, as a positive amount.",
+                    "type": "number"
+                },
+                "destination_account_id": {
+                    "type": "integer"
+                },
+                "source_account_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "server.ErrorDetail": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.ErrorDetail"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "RFC 7807 members, only set for application/problem+json.",
+                    "type": "string"
                 }
             }
         }
@@ -1,24 +1,158 @@
 package main
 
 import (
+	"account-transactions/config"
+	"account-transactions/model"
 	"account-transactions/server"
 	"account-transactions/store"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
-	"net/http"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
-var port = ":8080"
-
 //	@title			account-transactions API
 //	@version		1.0
 //	@description	API for managing accounts and transactions.
 
 // @host	localhost:8080
 func main() {
-	log.Printf("listening on port %s\n", port)
+	// "migrate <up|down|status>" manages the schema instead of serving.
+	args := os.Args[1:]
+	var migrateCommand string
+	if len(args) > 0 && args[0] == "migrate" {
+		if len(args) < 2 {
+			fatal(errors.New("usage: main migrate <up|down|status> [flags]"))
+		}
+		migrateCommand, args = args[1], args[2:]
+	}
+
+	cfg, err := config.Load(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fatal(fmt.Errorf("invalid config: %w", err))
+	}
+
+	log.Printf("effective config:\n%s", cfg)
+	level, _ := cfg.SlogLevel()
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})))
+
+	// Validated by config.Load.
+	policy, _ := model.ParseSettlementPolicy(cfg.Server.SettlementOrder)
+	rounding, _ := model.ParseInstallmentRounding(cfg.Server.InstallmentRounding)
+
+	if migrateCommand != "" {
+		if err := runMigrate(migrateCommand, cfg.Store); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	var db store.Store
+	switch cfg.Store.Driver {
+	case "memory":
+		s := store.NewMemory()
+		s.SettlementPolicy = policy
+		s.InstallmentRounding = rounding
+		db = s
+	default:
+		s, err := openSQLStore(cfg.Store)
+		if err != nil {
+			fatal(err)
+		}
+		if cfg.Store.AutoMigrate {
+			if err := migrateUp(s); err != nil {
+				fatal(err)
+			}
+		}
+		s.SettlementPolicy = policy
+		s.InstallmentRounding = rounding
+		db = s
+	}
+
+	if cfg.Store.FXRatesFile != "" {
+		if err := loadFXRates(db, cfg.Store.FXRatesFile); err != nil {
+			fatal(err)
+		}
+	}
+
+	if err := run(db, cfg.Server); err != nil {
+		fatal(err)
+	}
+}
 
-	db := store.New()
-	r := server.NewRouter(db)
+// run serves the API and sweeps expired authorizations until SIGTERM or
+// SIGINT, drains in-flight requests and then closes the store.
+func run(db store.Store, cfg config.ServerConfig) (err error) {
+	defer func() {
+		if closeErr := db.Close(); closeErr != nil {
+			err = errors.Join(err, fmt.Errorf("closing store: %w", closeErr))
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	// Sweep expired authorizations and idempotency keys while serving, and
+	// stop before the store is closed.
+	sweepCtx, stopSweeping := context.WithCancel(ctx)
+	swept := make(chan struct{})
+	go func() {
+		defer close(swept)
+		server.Sweep(sweepCtx, db, cfg.AuthorizationSweepInterval, cfg.IdempotencyTTL)
+	}()
+	defer func() {
+		stopSweeping()
+		<-swept
+	}()
+
+	ln, err := net.Listen("tcp", cfg.Addr)
+	if err != nil {
+		return err
+	}
+	slog.Info("listening", "addr", ln.Addr().String())
+
+	srv := server.NewHTTPServer(server.NewRouter(db, cfg), cfg)
+	if err := server.Serve(ctx, srv, ln, cfg.ShutdownTimeout); err != nil {
+		return err
+	}
+	slog.Info("server stopped")
+	return nil
+}
+
+// loadFXRates stores the exchange rates in the CSV file at path.
+func loadFXRates(db store.Store, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("reading fx rates: %w", err)
+	}
+	defer f.Close()
+
+	list, err := model.ParseFXRatesCSV(f)
+	if err != nil {
+		return fmt.Errorf("parsing fx rates file %s: %w", path, err)
+	}
+	if err := list.Validate(); err != nil {
+		return fmt.Errorf("invalid fx rates file %s: %w", path, err)
+	}
+	rates, err := db.UpdateFXRates(context.Background(), list.Rates)
+	if err != nil {
+		return fmt.Errorf("storing fx rates: %w", err)
+	}
+	slog.Info("loaded fx rates", "file", path, "rates", len(rates))
+	return nil
+}
 
-	log.Fatal(http.ListenAndServe(port, r))
+// fatal logs err at error level and exits.
+func fatal(err error) {
+	slog.Error(err.Error())
+	os.Exit(1)
 }
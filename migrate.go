@@ -0,0 +1,108 @@
+package main
+
+import (
+	"account-transactions/config"
+	"account-transactions/store"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+)
+
+// runMigrate runs a migrate subcommand against the configured store.
+func runMigrate(command string, cfg config.StoreConfig) (err error) {
+	s, err := openSQLStore(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, s.Close())
+	}()
+
+	switch command {
+	case "up":
+		return migrateUp(s)
+	case "down":
+		return migrateDown(s)
+	case "status":
+		return migrateStatus(s)
+	default:
+		return fmt.Errorf("unknown migrate command %q: must be up, down or status", command)
+	}
+}
+
+// openSQLStore connects to the configured SQL database.
+func openSQLStore(cfg config.StoreConfig) (*store.StoreImpl, error) {
+	switch cfg.Driver {
+	case "mysql":
+		return store.New(cfg.MySQL)
+	case "postgres":
+		return store.NewPostgres(cfg.Postgres)
+	case "sqlite":
+		return store.NewSQLite(cfg.SQLite)
+	default:
+		return nil, fmt.Errorf("the %s store has no schema to migrate", cfg.Driver)
+	}
+}
+
+// migrateUp applies every pending migration.
+func migrateUp(s *store.StoreImpl) error {
+	m, err := s.Migrator()
+	if err != nil {
+		return err
+	}
+	applied, err := m.Up(context.Background())
+	for _, migration := range applied {
+		slog.Info("applied migration", "migration", migration)
+	}
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		slog.Info("schema is up to date")
+	}
+	return nil
+}
+
+// migrateDown reverts the latest migration.
+func migrateDown(s *store.StoreImpl) error {
+	m, err := s.Migrator()
+	if err != nil {
+		return err
+	}
+	reverted, err := m.Down(context.Background())
+	if err != nil {
+		return err
+	}
+	if reverted == nil {
+		slog.Info("no migration to revert")
+		return nil
+	}
+	slog.Info("reverted migration", "migration", *reverted)
+	return nil
+}
+
+// migrateStatus prints every migration and when it was applied.
+func migrateStatus(s *store.StoreImpl) error {
+	m, err := s.Migrator()
+	if err != nil {
+		return err
+	}
+	statuses, err := m.Status(context.Background())
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MIGRATION\tAPPLIED AT")
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%s\t%s\n", status.Migration, appliedAt)
+	}
+	return w.Flush()
+}
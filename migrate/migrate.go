@@ -0,0 +1,291 @@
+// Package migrate applies numbered SQL migrations and records them in a
+// schema version table.
+//
+// Migrations are read from pairs of files named
+// <version>_<name>.up.sql and <version>_<name>.down.sql, e.g.
+// 0001_create_tables.up.sql. Statements within a file are separated by a
+// semicolon at the end of a line.
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// VersionTable records the applied migrations.
+const VersionTable = "SchemaMigrations"
+
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// String returns the migration's file name stem, e.g. 0001_create_tables.
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+// Status is a migration and when it was applied, if it was.
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Load reads the migrations in the root of fsys, ordered by version. Every
+// migration must have both an up and a down file.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, _ := strconv.Atoi(match[1])
+		body, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %s needs both an up and a down file", m)
+		}
+		migrations = append(migrations, *m)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int { return a.Version - b.Version })
+	return migrations, nil
+}
+
+// Migrator applies migrations to a database.
+type Migrator struct {
+	db         *sqlx.DB
+	migrations []Migration
+
+	// Lock and Unlock, if set, take and release a lock around Up and Down
+	// so that only one migrator changes the schema at a time. Lock must
+	// wait for the lock and return a single row holding 1 once it is
+	// taken, e.g. MySQL's SELECT GET_LOCK('SchemaMigrations', -1). Both
+	// run on the same connection, as session locks belong to it.
+	Lock   string
+	Unlock string
+}
+
+// New returns a Migrator for the migrations in fsys.
+func New(db *sqlx.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, fmt.Errorf("loading migrations: %w", err)
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Up applies every pending migration in order and returns them.
+func (m *Migrator) Up(ctx context.Context) (done []Migration, err error) {
+	unlock, err := m.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = errors.Join(err, unlock()) }()
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		err := m.run(ctx, migration.Up,
+			"INSERT INTO "+VersionTable+"(Version, Name, Applied_At) VALUES( ?, ?, ? )",
+			migration.Version, migration.Name, time.Now().UTC().Truncate(time.Second))
+		if err != nil {
+			return done, fmt.Errorf("applying migration %s: %w", migration, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Down reverts the latest applied migration and returns it, or nil if none
+// is applied.
+func (m *Migrator) Down(ctx context.Context) (reverted *Migration, err error) {
+	unlock, err := m.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = errors.Join(err, unlock()) }()
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		err := m.run(ctx, migration.Down, "DELETE FROM "+VersionTable+" WHERE Version=?", migration.Version)
+		if err != nil {
+			return nil, fmt.Errorf("reverting migration %s: %w", migration, err)
+		}
+		return &migration, nil
+	}
+	return nil, nil
+}
+
+// Status lists every migration and when it was applied.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i] = Status{Migration: migration}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			statuses[i].AppliedAt = &appliedAt
+		}
+	}
+	return statuses, nil
+}
+
+// lock takes the migration lock, if there is one, and returns the function
+// that releases it.
+func (m *Migrator) lock(ctx context.Context) (func() error, error) {
+	if m.Lock == "" {
+		return func() error { return nil }, nil
+	}
+	conn, err := m.db.Connx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("locking migrations: %w", err)
+	}
+
+	var taken int
+	if err := conn.QueryRowxContext(ctx, m.Lock).Scan(&taken); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("locking migrations: %w", err)
+	}
+	if taken != 1 {
+		conn.Close()
+		return nil, fmt.Errorf("locking migrations: lock returned %d", taken)
+	}
+	return func() error {
+		// Release the lock even if ctx is done, or the pooled connection
+		// keeps holding it.
+		_, err := conn.ExecContext(context.WithoutCancel(ctx), m.Unlock)
+		if closeErr := conn.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("unlocking migrations: %w", err)
+		}
+		return nil
+	}, nil
+}
+
+// applied creates the version table if needed and returns when each
+// applied version was applied.
+func (m *Migrator) applied(ctx context.Context) (map[int]time.Time, error) {
+	_, err := m.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+VersionTable+" (Version BIGINT NOT NULL, Name VARCHAR (255) NOT NULL, Applied_At TIMESTAMP NOT NULL, PRIMARY KEY (Version))")
+	if err != nil {
+		return nil, fmt.Errorf("creating %s: %w", VersionTable, err)
+	}
+
+	var rows []struct {
+		Version   int       `db:"Version"`
+		AppliedAt time.Time `db:"Applied_At"`
+	}
+	if err := m.db.SelectContext(ctx, &rows, "SELECT Version, Applied_At FROM "+VersionTable); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", VersionTable, err)
+	}
+
+	applied := make(map[int]time.Time, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row.AppliedAt
+	}
+	return applied, nil
+}
+
+// run executes the statements of a migration and then records it, in one
+// DB transaction. Databases such as MySQL commit DDL statements
+// implicitly, so a failed migration may still have to be cleaned up by
+// hand.
+func (m *Migrator) run(ctx context.Context, script string, record string, args ...any) error {
+	tx, err := m.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // No-op once committed.
+
+	for _, stmt := range splitStatements(script) {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, tx.Rebind(record), args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// splitStatements splits a script on semicolons that end a line, dropping
+// comment lines and empty statements.
+func splitStatements(script string) []string {
+	var stmts []string
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			if stmt := strings.TrimSuffix(strings.TrimSpace(current.String()), ";"); stmt != "" {
+				stmts = append(stmts, stmt)
+			}
+			current.Reset()
+		}
+	}
+	if stmt := strings.TrimSpace(current.String()); stmt != "" {
+		stmts = append(stmts, stmt)
+	}
+	return stmts
+}
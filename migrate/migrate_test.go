@@ -0,0 +1,200 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errSyntax = errors.New("syntax error")
+
+var testMigrations = fstest.MapFS{
+	"0002_seed.up.sql":     {Data: []byte("-- Seed the table.\nINSERT INTO T VALUES (1);\nINSERT INTO T VALUES (2);\n")},
+	"0002_seed.down.sql":   {Data: []byte("DELETE FROM T;")},
+	"0001_create.up.sql":   {Data: []byte("CREATE TABLE T (\n    ID int\n);\n")},
+	"0001_create.down.sql": {Data: []byte("DROP TABLE T;")},
+	"README.md":            {Data: []byte("Not a migration.")},
+}
+
+func newMigrator(t *testing.T) (*Migrator, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	m, err := New(sqlx.NewDb(db, "sqlmock"), testMigrations)
+	require.NoError(t, err)
+	return m, mock
+}
+
+func expectApplied(mock sqlmock.Sqlmock, versions ...int) {
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS SchemaMigrations (Version BIGINT NOT NULL, Name VARCHAR (255) NOT NULL, Applied_At TIMESTAMP NOT NULL, PRIMARY KEY (Version))").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	rows := sqlmock.NewRows([]string{"Version", "Applied_At"})
+	for _, version := range versions {
+		rows.AddRow(version, time.Date(2025, 10, 27, 0, 0, 0, 0, time.UTC))
+	}
+	mock.ExpectQuery("SELECT Version, Applied_At FROM SchemaMigrations").WillReturnRows(rows)
+}
+
+func TestLoad(t *testing.T) {
+	// When.
+	migrations, err := Load(testMigrations)
+
+	// Then.
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	assert.Equal(t, "0001_create", migrations[0].String())
+	assert.Equal(t, "0002_seed", migrations[1].String())
+	assert.Equal(t, "DELETE FROM T;", migrations[1].Down)
+}
+
+func TestLoad_MissingDown(t *testing.T) {
+	// When.
+	_, err := Load(fstest.MapFS{"0001_create.up.sql": {Data: []byte("CREATE TABLE T (ID int);")}})
+
+	// Then.
+	require.ErrorContains(t, err, "needs both an up and a down file")
+}
+
+func TestSplitStatements(t *testing.T) {
+	// When.
+	stmts := splitStatements(string(testMigrations["0002_seed.up.sql"].Data))
+
+	// Then.
+	assert.Equal(t, []string{"INSERT INTO T VALUES (1)", "INSERT INTO T VALUES (2)"}, stmts)
+}
+
+func TestUp_AppliesPending(t *testing.T) {
+	// Given.
+	m, mock := newMigrator(t)
+	expectApplied(mock, 1)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO T VALUES (1)").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO T VALUES (2)").WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectExec("INSERT INTO SchemaMigrations(Version, Name, Applied_At) VALUES( ?, ?, ? )").
+		WithArgs(2, "seed", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// When.
+	applied, err := m.Up(context.Background())
+
+	// Then.
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+	assert.Equal(t, 2, applied[0].Version)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUp_StopsAtFailure(t *testing.T) {
+	// Given.
+	m, mock := newMigrator(t)
+	expectApplied(mock)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE TABLE T (\n    ID int\n)").WillReturnError(errSyntax)
+	mock.ExpectRollback()
+
+	// When.
+	applied, err := m.Up(context.Background())
+
+	// Then.
+	require.ErrorIs(t, err, errSyntax)
+	assert.Contains(t, err.Error(), "0001_create")
+	assert.Empty(t, applied)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUp_Locks(t *testing.T) {
+	// Given.
+	m, mock := newMigrator(t)
+	m.Lock = "SELECT GET_LOCK('SchemaMigrations', -1)"
+	m.Unlock = "SELECT RELEASE_LOCK('SchemaMigrations')"
+
+	mock.ExpectQuery("SELECT GET_LOCK('SchemaMigrations', -1)").WillReturnRows(sqlmock.NewRows([]string{"taken"}).AddRow(1))
+	expectApplied(mock, 1, 2)
+	mock.ExpectExec("SELECT RELEASE_LOCK('SchemaMigrations')").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// When.
+	applied, err := m.Up(context.Background())
+
+	// Then.
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUp_LockNotTaken(t *testing.T) {
+	// Given.
+	m, mock := newMigrator(t)
+	m.Lock = "SELECT GET_LOCK('SchemaMigrations', -1)"
+	m.Unlock = "SELECT RELEASE_LOCK('SchemaMigrations')"
+
+	mock.ExpectQuery("SELECT GET_LOCK('SchemaMigrations', -1)").WillReturnRows(sqlmock.NewRows([]string{"taken"}).AddRow(0))
+
+	// When.
+	applied, err := m.Up(context.Background())
+
+	// Then.
+	require.ErrorContains(t, err, "locking migrations")
+	assert.Empty(t, applied)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDown_RevertsLatest(t *testing.T) {
+	// Given.
+	m, mock := newMigrator(t)
+	expectApplied(mock, 1, 2)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM T").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("DELETE FROM SchemaMigrations WHERE Version=?").
+		WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// When.
+	reverted, err := m.Down(context.Background())
+
+	// Then.
+	require.NoError(t, err)
+	require.NotNil(t, reverted)
+	assert.Equal(t, 2, reverted.Version)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDown_NothingApplied(t *testing.T) {
+	// Given.
+	m, mock := newMigrator(t)
+	expectApplied(mock)
+
+	// When.
+	reverted, err := m.Down(context.Background())
+
+	// Then.
+	require.NoError(t, err)
+	assert.Nil(t, reverted)
+}
+
+func TestStatus(t *testing.T) {
+	// Given.
+	m, mock := newMigrator(t)
+	expectApplied(mock, 1)
+
+	// When.
+	statuses, err := m.Status(context.Background())
+
+	// Then.
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	assert.NotNil(t, statuses[0].AppliedAt)
+	assert.Nil(t, statuses[1].AppliedAt)
+}
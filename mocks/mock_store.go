@@ -11,7 +11,9 @@ package mock_store
 
 import (
 	model "account-transactions/model"
+	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -40,224 +42,1192 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// CaptureAuthorization mocks base method.
+func (m *MockStore) CaptureAuthorization(arg0 context.Context, arg1 int, arg2 *model.Money) (*model.TransactionImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureAuthorization", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.TransactionImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CaptureAuthorization indicates an expected call of CaptureAuthorization.
+func (mr *MockStoreMockRecorder) CaptureAuthorization(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureAuthorization", reflect.TypeOf((*MockStore)(nil).CaptureAuthorization), arg0, arg1, arg2)
+}
+
+// Close mocks base method.
+func (m *MockStore) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockStoreMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockStore)(nil).Close))
+}
+
+// CompleteIdempotencyKey mocks base method.
+func (m *MockStore) CompleteIdempotencyKey(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteIdempotencyKey", ctx, key, statusCode, contentType, body)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteIdempotencyKey indicates an expected call of CompleteIdempotencyKey.
+func (mr *MockStoreMockRecorder) CompleteIdempotencyKey(ctx, key, statusCode, contentType, body any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteIdempotencyKey", reflect.TypeOf((*MockStore)(nil).CompleteIdempotencyKey), ctx, key, statusCode, contentType, body)
+}
+
 // CreateAccount mocks base method.
-func (m *MockStore) CreateAccount(arg0 string) (*model.AccountImpl, error) {
+func (m *MockStore) CreateAccount(arg0 context.Context, arg1 string, arg2 model.Currency) (*model.AccountImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccount", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.AccountImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccount indicates an expected call of CreateAccount.
+func (mr *MockStoreMockRecorder) CreateAccount(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockStore)(nil).CreateAccount), arg0, arg1, arg2)
+}
+
+// CreateAuthorization mocks base method.
+func (m *MockStore) CreateAuthorization(arg0 context.Context, arg1 model.AuthorizationImpl) (*model.AuthorizationImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuthorization", arg0, arg1)
+	ret0, _ := ret[0].(*model.AuthorizationImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuthorization indicates an expected call of CreateAuthorization.
+func (mr *MockStoreMockRecorder) CreateAuthorization(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuthorization", reflect.TypeOf((*MockStore)(nil).CreateAuthorization), arg0, arg1)
+}
+
+// CreateDebit mocks base method.
+func (m *MockStore) CreateDebit(arg0 context.Context, arg1 model.TransactionImpl) (*model.TransactionImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDebit", arg0, arg1)
+	ret0, _ := ret[0].(*model.TransactionImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDebit indicates an expected call of CreateDebit.
+func (mr *MockStoreMockRecorder) CreateDebit(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDebit", reflect.TypeOf((*MockStore)(nil).CreateDebit), arg0, arg1)
+}
+
+// CreateInstallmentPlan mocks base method.
+func (m *MockStore) CreateInstallmentPlan(arg0 context.Context, arg1 model.TransactionImpl, arg2 int) (*model.InstallmentPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInstallmentPlan", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.InstallmentPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInstallmentPlan indicates an expected call of CreateInstallmentPlan.
+func (mr *MockStoreMockRecorder) CreateInstallmentPlan(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInstallmentPlan", reflect.TypeOf((*MockStore)(nil).CreateInstallmentPlan), arg0, arg1, arg2)
+}
+
+// CreateTransaction mocks base method.
+func (m *MockStore) CreateTransaction(arg0 context.Context, arg1 model.TransactionImpl) (*model.TransactionImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransaction", arg0, arg1)
+	ret0, _ := ret[0].(*model.TransactionImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransaction indicates an expected call of CreateTransaction.
+func (mr *MockStoreMockRecorder) CreateTransaction(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransaction", reflect.TypeOf((*MockStore)(nil).CreateTransaction), arg0, arg1)
+}
+
+// CreateTransfer mocks base method.
+func (m *MockStore) CreateTransfer(arg0 context.Context, arg1 model.Transfer) (*model.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransfer", arg0, arg1)
+	ret0, _ := ret[0].(*model.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransfer indicates an expected call of CreateTransfer.
+func (mr *MockStoreMockRecorder) CreateTransfer(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransfer", reflect.TypeOf((*MockStore)(nil).CreateTransfer), arg0, arg1)
+}
+
+// ExpireAuthorizations mocks base method.
+func (m *MockStore) ExpireAuthorizations(arg0 context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireAuthorizations", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireAuthorizations indicates an expected call of ExpireAuthorizations.
+func (mr *MockStoreMockRecorder) ExpireAuthorizations(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireAuthorizations", reflect.TypeOf((*MockStore)(nil).ExpireAuthorizations), arg0)
+}
+
+// GetAccount mocks base method.
+func (m *MockStore) GetAccount(arg0 context.Context, arg1 int) (*model.AccountImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccount", arg0, arg1)
+	ret0, _ := ret[0].(*model.AccountImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccount indicates an expected call of GetAccount.
+func (mr *MockStoreMockRecorder) GetAccount(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockStore)(nil).GetAccount), arg0, arg1)
+}
+
+// GetAuthorization mocks base method.
+func (m *MockStore) GetAuthorization(arg0 context.Context, arg1 int) (*model.AuthorizationImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorization", arg0, arg1)
+	ret0, _ := ret[0].(*model.AuthorizationImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthorization indicates an expected call of GetAuthorization.
+func (mr *MockStoreMockRecorder) GetAuthorization(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorization", reflect.TypeOf((*MockStore)(nil).GetAuthorization), arg0, arg1)
+}
+
+// GetBalance mocks base method.
+func (m *MockStore) GetBalance(arg0 context.Context, arg1 int) (*model.AccountBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalance", arg0, arg1)
+	ret0, _ := ret[0].(*model.AccountBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalance indicates an expected call of GetBalance.
+func (mr *MockStoreMockRecorder) GetBalance(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalance", reflect.TypeOf((*MockStore)(nil).GetBalance), arg0, arg1)
+}
+
+// GetFXRate mocks base method.
+func (m *MockStore) GetFXRate(arg0 context.Context, arg1, arg2 model.Currency) (*model.FXRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFXRate", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.FXRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFXRate indicates an expected call of GetFXRate.
+func (mr *MockStoreMockRecorder) GetFXRate(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFXRate", reflect.TypeOf((*MockStore)(nil).GetFXRate), arg0, arg1, arg2)
+}
+
+// GetInstallmentPlan mocks base method.
+func (m *MockStore) GetInstallmentPlan(arg0 context.Context, arg1 int) (*model.InstallmentPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInstallmentPlan", arg0, arg1)
+	ret0, _ := ret[0].(*model.InstallmentPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInstallmentPlan indicates an expected call of GetInstallmentPlan.
+func (mr *MockStoreMockRecorder) GetInstallmentPlan(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInstallmentPlan", reflect.TypeOf((*MockStore)(nil).GetInstallmentPlan), arg0, arg1)
+}
+
+// GetNegativeTransactions mocks base method.
+func (m *MockStore) GetNegativeTransactions(arg0 context.Context, arg1, arg2 int) (model.Transactions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNegativeTransactions", arg0, arg1, arg2)
+	ret0, _ := ret[0].(model.Transactions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNegativeTransactions indicates an expected call of GetNegativeTransactions.
+func (mr *MockStoreMockRecorder) GetNegativeTransactions(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNegativeTransactions", reflect.TypeOf((*MockStore)(nil).GetNegativeTransactions), arg0, arg1, arg2)
+}
+
+// GetOperation mocks base method.
+func (m *MockStore) GetOperation(arg0 context.Context, arg1 int) (*model.OperationImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOperation", arg0, arg1)
+	ret0, _ := ret[0].(*model.OperationImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOperation indicates an expected call of GetOperation.
+func (mr *MockStoreMockRecorder) GetOperation(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOperation", reflect.TypeOf((*MockStore)(nil).GetOperation), arg0, arg1)
+}
+
+// GetStatement mocks base method.
+func (m *MockStore) GetStatement(arg0 context.Context, arg1 int, arg2, arg3 *time.Time) (*model.Statement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatement", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*model.Statement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatement indicates an expected call of GetStatement.
+func (mr *MockStoreMockRecorder) GetStatement(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatement", reflect.TypeOf((*MockStore)(nil).GetStatement), arg0, arg1, arg2, arg3)
+}
+
+// GetTransaction mocks base method.
+func (m *MockStore) GetTransaction(arg0 context.Context, arg1 int) (*model.TransactionImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransaction", arg0, arg1)
+	ret0, _ := ret[0].(*model.TransactionImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransaction indicates an expected call of GetTransaction.
+func (mr *MockStoreMockRecorder) GetTransaction(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransaction", reflect.TypeOf((*MockStore)(nil).GetTransaction), arg0, arg1)
+}
+
+// GetTransfer mocks base method.
+func (m *MockStore) GetTransfer(arg0 context.Context, arg1 int) (*model.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransfer", arg0, arg1)
+	ret0, _ := ret[0].(*model.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransfer indicates an expected call of GetTransfer.
+func (mr *MockStoreMockRecorder) GetTransfer(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockStore)(nil).GetTransfer), arg0, arg1)
+}
+
+// ListAccountStatusChanges mocks base method.
+func (m *MockStore) ListAccountStatusChanges(arg0 context.Context, arg1 int) ([]model.AccountStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountStatusChanges", arg0, arg1)
+	ret0, _ := ret[0].([]model.AccountStatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountStatusChanges indicates an expected call of ListAccountStatusChanges.
+func (mr *MockStoreMockRecorder) ListAccountStatusChanges(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountStatusChanges", reflect.TypeOf((*MockStore)(nil).ListAccountStatusChanges), arg0, arg1)
+}
+
+// ListCreditLimitChanges mocks base method.
+func (m *MockStore) ListCreditLimitChanges(arg0 context.Context, arg1 int) ([]model.CreditLimitChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCreditLimitChanges", arg0, arg1)
+	ret0, _ := ret[0].([]model.CreditLimitChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCreditLimitChanges indicates an expected call of ListCreditLimitChanges.
+func (mr *MockStoreMockRecorder) ListCreditLimitChanges(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCreditLimitChanges", reflect.TypeOf((*MockStore)(nil).ListCreditLimitChanges), arg0, arg1)
+}
+
+// ListFXRates mocks base method.
+func (m *MockStore) ListFXRates(arg0 context.Context) ([]model.FXRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFXRates", arg0)
+	ret0, _ := ret[0].([]model.FXRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFXRates indicates an expected call of ListFXRates.
+func (mr *MockStoreMockRecorder) ListFXRates(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFXRates", reflect.TypeOf((*MockStore)(nil).ListFXRates), arg0)
+}
+
+// ListTransactions mocks base method.
+func (m *MockStore) ListTransactions(arg0 context.Context, arg1 int, arg2 model.TransactionFilter) (model.Transactions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransactions", arg0, arg1, arg2)
+	ret0, _ := ret[0].(model.Transactions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransactions indicates an expected call of ListTransactions.
+func (mr *MockStoreMockRecorder) ListTransactions(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransactions", reflect.TypeOf((*MockStore)(nil).ListTransactions), arg0, arg1, arg2)
+}
+
+// PurgeIdempotencyKeys mocks base method.
+func (m *MockStore) PurgeIdempotencyKeys(ctx context.Context, expiresBefore time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeIdempotencyKeys", ctx, expiresBefore)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeIdempotencyKeys indicates an expected call of PurgeIdempotencyKeys.
+func (mr *MockStoreMockRecorder) PurgeIdempotencyKeys(ctx, expiresBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeIdempotencyKeys", reflect.TypeOf((*MockStore)(nil).PurgeIdempotencyKeys), ctx, expiresBefore)
+}
+
+// ReleaseIdempotencyKey mocks base method.
+func (m *MockStore) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseIdempotencyKey", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseIdempotencyKey indicates an expected call of ReleaseIdempotencyKey.
+func (mr *MockStoreMockRecorder) ReleaseIdempotencyKey(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseIdempotencyKey", reflect.TypeOf((*MockStore)(nil).ReleaseIdempotencyKey), ctx, key)
+}
+
+// ReserveIdempotencyKey mocks base method.
+func (m *MockStore) ReserveIdempotencyKey(ctx context.Context, key, requestHash string, createdAt, expiresBefore time.Time) (*model.IdempotencyRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveIdempotencyKey", ctx, key, requestHash, createdAt, expiresBefore)
+	ret0, _ := ret[0].(*model.IdempotencyRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveIdempotencyKey indicates an expected call of ReserveIdempotencyKey.
+func (mr *MockStoreMockRecorder) ReserveIdempotencyKey(ctx, key, requestHash, createdAt, expiresBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveIdempotencyKey", reflect.TypeOf((*MockStore)(nil).ReserveIdempotencyKey), ctx, key, requestHash, createdAt, expiresBefore)
+}
+
+// ReverseTransaction mocks base method.
+func (m *MockStore) ReverseTransaction(arg0 context.Context, arg1 int, arg2 *model.Money) (*model.TransactionImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReverseTransaction", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.TransactionImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReverseTransaction indicates an expected call of ReverseTransaction.
+func (mr *MockStoreMockRecorder) ReverseTransaction(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReverseTransaction", reflect.TypeOf((*MockStore)(nil).ReverseTransaction), arg0, arg1, arg2)
+}
+
+// SettlePayment mocks base method.
+func (m *MockStore) SettlePayment(arg0 context.Context, arg1 model.TransactionImpl) (*model.TransactionImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SettlePayment", arg0, arg1)
+	ret0, _ := ret[0].(*model.TransactionImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SettlePayment indicates an expected call of SettlePayment.
+func (mr *MockStoreMockRecorder) SettlePayment(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SettlePayment", reflect.TypeOf((*MockStore)(nil).SettlePayment), arg0, arg1)
+}
+
+// UpdateAccountStatus mocks base method.
+func (m *MockStore) UpdateAccountStatus(arg0 context.Context, arg1 model.AccountStatusChange) (*model.AccountStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountStatus", arg0, arg1)
+	ret0, _ := ret[0].(*model.AccountStatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccountStatus indicates an expected call of UpdateAccountStatus.
+func (mr *MockStoreMockRecorder) UpdateAccountStatus(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountStatus", reflect.TypeOf((*MockStore)(nil).UpdateAccountStatus), arg0, arg1)
+}
+
+// UpdateCreditLimit mocks base method.
+func (m *MockStore) UpdateCreditLimit(arg0 context.Context, arg1 model.CreditLimitChange) (*model.CreditLimitChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCreditLimit", arg0, arg1)
+	ret0, _ := ret[0].(*model.CreditLimitChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCreditLimit indicates an expected call of UpdateCreditLimit.
+func (mr *MockStoreMockRecorder) UpdateCreditLimit(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCreditLimit", reflect.TypeOf((*MockStore)(nil).UpdateCreditLimit), arg0, arg1)
+}
+
+// UpdateFXRates mocks base method.
+func (m *MockStore) UpdateFXRates(arg0 context.Context, arg1 []model.FXRate) ([]model.FXRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFXRates", arg0, arg1)
+	ret0, _ := ret[0].([]model.FXRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateFXRates indicates an expected call of UpdateFXRates.
+func (mr *MockStoreMockRecorder) UpdateFXRates(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFXRates", reflect.TypeOf((*MockStore)(nil).UpdateFXRates), arg0, arg1)
+}
+
+// UpdateNegativeTransactions mocks base method.
+func (m *MockStore) UpdateNegativeTransactions(arg0 context.Context, arg1 model.Transactions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNegativeTransactions", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateNegativeTransactions indicates an expected call of UpdateNegativeTransactions.
+func (mr *MockStoreMockRecorder) UpdateNegativeTransactions(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNegativeTransactions", reflect.TypeOf((*MockStore)(nil).UpdateNegativeTransactions), arg0, arg1)
+}
+
+// VoidAuthorization mocks base method.
+func (m *MockStore) VoidAuthorization(arg0 context.Context, arg1 int) (*model.AuthorizationImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VoidAuthorization", arg0, arg1)
+	ret0, _ := ret[0].(*model.AuthorizationImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VoidAuthorization indicates an expected call of VoidAuthorization.
+func (mr *MockStoreMockRecorder) VoidAuthorization(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoidAuthorization", reflect.TypeOf((*MockStore)(nil).VoidAuthorization), arg0, arg1)
+}
+
+// MockAccount is a mock of Account interface.
+type MockAccount struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountMockRecorder
+	isgomock struct{}
+}
+
+// MockAccountMockRecorder is the mock recorder for MockAccount.
+type MockAccountMockRecorder struct {
+	mock *MockAccount
+}
+
+// NewMockAccount creates a new mock instance.
+func NewMockAccount(ctrl *gomock.Controller) *MockAccount {
+	mock := &MockAccount{ctrl: ctrl}
+	mock.recorder = &MockAccountMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccount) EXPECT() *MockAccountMockRecorder {
+	return m.recorder
+}
+
+// CreateAccount mocks base method.
+func (m *MockAccount) CreateAccount(arg0 context.Context, arg1 string, arg2 model.Currency) (*model.AccountImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccount", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.AccountImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccount indicates an expected call of CreateAccount.
+func (mr *MockAccountMockRecorder) CreateAccount(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockAccount)(nil).CreateAccount), arg0, arg1, arg2)
+}
+
+// GetAccount mocks base method.
+func (m *MockAccount) GetAccount(arg0 context.Context, arg1 int) (*model.AccountImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccount", arg0, arg1)
+	ret0, _ := ret[0].(*model.AccountImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccount indicates an expected call of GetAccount.
+func (mr *MockAccountMockRecorder) GetAccount(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockAccount)(nil).GetAccount), arg0, arg1)
+}
+
+// GetBalance mocks base method.
+func (m *MockAccount) GetBalance(arg0 context.Context, arg1 int) (*model.AccountBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalance", arg0, arg1)
+	ret0, _ := ret[0].(*model.AccountBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalance indicates an expected call of GetBalance.
+func (mr *MockAccountMockRecorder) GetBalance(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalance", reflect.TypeOf((*MockAccount)(nil).GetBalance), arg0, arg1)
+}
+
+// GetStatement mocks base method.
+func (m *MockAccount) GetStatement(arg0 context.Context, arg1 int, arg2, arg3 *time.Time) (*model.Statement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatement", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*model.Statement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatement indicates an expected call of GetStatement.
+func (mr *MockAccountMockRecorder) GetStatement(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatement", reflect.TypeOf((*MockAccount)(nil).GetStatement), arg0, arg1, arg2, arg3)
+}
+
+// ListAccountStatusChanges mocks base method.
+func (m *MockAccount) ListAccountStatusChanges(arg0 context.Context, arg1 int) ([]model.AccountStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountStatusChanges", arg0, arg1)
+	ret0, _ := ret[0].([]model.AccountStatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountStatusChanges indicates an expected call of ListAccountStatusChanges.
+func (mr *MockAccountMockRecorder) ListAccountStatusChanges(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountStatusChanges", reflect.TypeOf((*MockAccount)(nil).ListAccountStatusChanges), arg0, arg1)
+}
+
+// ListCreditLimitChanges mocks base method.
+func (m *MockAccount) ListCreditLimitChanges(arg0 context.Context, arg1 int) ([]model.CreditLimitChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCreditLimitChanges", arg0, arg1)
+	ret0, _ := ret[0].([]model.CreditLimitChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCreditLimitChanges indicates an expected call of ListCreditLimitChanges.
+func (mr *MockAccountMockRecorder) ListCreditLimitChanges(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCreditLimitChanges", reflect.TypeOf((*MockAccount)(nil).ListCreditLimitChanges), arg0, arg1)
+}
+
+// UpdateAccountStatus mocks base method.
+func (m *MockAccount) UpdateAccountStatus(arg0 context.Context, arg1 model.AccountStatusChange) (*model.AccountStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountStatus", arg0, arg1)
+	ret0, _ := ret[0].(*model.AccountStatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccountStatus indicates an expected call of UpdateAccountStatus.
+func (mr *MockAccountMockRecorder) UpdateAccountStatus(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountStatus", reflect.TypeOf((*MockAccount)(nil).UpdateAccountStatus), arg0, arg1)
+}
+
+// UpdateCreditLimit mocks base method.
+func (m *MockAccount) UpdateCreditLimit(arg0 context.Context, arg1 model.CreditLimitChange) (*model.CreditLimitChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCreditLimit", arg0, arg1)
+	ret0, _ := ret[0].(*model.CreditLimitChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCreditLimit indicates an expected call of UpdateCreditLimit.
+func (mr *MockAccountMockRecorder) UpdateCreditLimit(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCreditLimit", reflect.TypeOf((*MockAccount)(nil).UpdateCreditLimit), arg0, arg1)
+}
+
+// MockOperation is a mock of Operation interface.
+type MockOperation struct {
+	ctrl     *gomock.Controller
+	recorder *MockOperationMockRecorder
+	isgomock struct{}
+}
+
+// MockOperationMockRecorder is the mock recorder for MockOperation.
+type MockOperationMockRecorder struct {
+	mock *MockOperation
+}
+
+// NewMockOperation creates a new mock instance.
+func NewMockOperation(ctrl *gomock.Controller) *MockOperation {
+	mock := &MockOperation{ctrl: ctrl}
+	mock.recorder = &MockOperationMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOperation) EXPECT() *MockOperationMockRecorder {
+	return m.recorder
+}
+
+// GetOperation mocks base method.
+func (m *MockOperation) GetOperation(arg0 context.Context, arg1 int) (*model.OperationImpl, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateAccount", arg0)
-	ret0, _ := ret[0].(*model.AccountImpl)
+	ret := m.ctrl.Call(m, "GetOperation", arg0, arg1)
+	ret0, _ := ret[0].(*model.OperationImpl)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateAccount indicates an expected call of CreateAccount.
-func (mr *MockStoreMockRecorder) CreateAccount(arg0 any) *gomock.Call {
+// GetOperation indicates an expected call of GetOperation.
+func (mr *MockOperationMockRecorder) GetOperation(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockStore)(nil).CreateAccount), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOperation", reflect.TypeOf((*MockOperation)(nil).GetOperation), arg0, arg1)
 }
 
-// CreateTransaction mocks base method.
-func (m *MockStore) CreateTransaction(arg0 model.TransactionImpl) (*model.TransactionImpl, error) {
+// MockTransaction is a mock of Transaction interface.
+type MockTransaction struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionMockRecorder
+	isgomock struct{}
+}
+
+// MockTransactionMockRecorder is the mock recorder for MockTransaction.
+type MockTransactionMockRecorder struct {
+	mock *MockTransaction
+}
+
+// NewMockTransaction creates a new mock instance.
+func NewMockTransaction(ctrl *gomock.Controller) *MockTransaction {
+	mock := &MockTransaction{ctrl: ctrl}
+	mock.recorder = &MockTransactionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransaction) EXPECT() *MockTransactionMockRecorder {
+	return m.recorder
+}
+
+// CreateDebit mocks base method.
+func (m *MockTransaction) CreateDebit(arg0 context.Context, arg1 model.TransactionImpl) (*model.TransactionImpl, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTransaction", arg0)
+	ret := m.ctrl.Call(m, "CreateDebit", arg0, arg1)
 	ret0, _ := ret[0].(*model.TransactionImpl)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateTransaction indicates an expected call of CreateTransaction.
-func (mr *MockStoreMockRecorder) CreateTransaction(arg0 any) *gomock.Call {
+// CreateDebit indicates an expected call of CreateDebit.
+func (mr *MockTransactionMockRecorder) CreateDebit(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransaction", reflect.TypeOf((*MockStore)(nil).CreateTransaction), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDebit", reflect.TypeOf((*MockTransaction)(nil).CreateDebit), arg0, arg1)
 }
 
-// GetAccount mocks base method.
-func (m *MockStore) GetAccount(arg0 int) (*model.AccountImpl, error) {
+// CreateTransaction mocks base method.
+func (m *MockTransaction) CreateTransaction(arg0 context.Context, arg1 model.TransactionImpl) (*model.TransactionImpl, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccount", arg0)
-	ret0, _ := ret[0].(*model.AccountImpl)
+	ret := m.ctrl.Call(m, "CreateTransaction", arg0, arg1)
+	ret0, _ := ret[0].(*model.TransactionImpl)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccount indicates an expected call of GetAccount.
-func (mr *MockStoreMockRecorder) GetAccount(arg0 any) *gomock.Call {
+// CreateTransaction indicates an expected call of CreateTransaction.
+func (mr *MockTransactionMockRecorder) CreateTransaction(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockStore)(nil).GetAccount), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransaction", reflect.TypeOf((*MockTransaction)(nil).CreateTransaction), arg0, arg1)
 }
 
-// GetOperation mocks base method.
-func (m *MockStore) GetOperation(arg0 int) (*model.OperationImpl, error) {
+// GetNegativeTransactions mocks base method.
+func (m *MockTransaction) GetNegativeTransactions(arg0 context.Context, arg1, arg2 int) (model.Transactions, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOperation", arg0)
-	ret0, _ := ret[0].(*model.OperationImpl)
+	ret := m.ctrl.Call(m, "GetNegativeTransactions", arg0, arg1, arg2)
+	ret0, _ := ret[0].(model.Transactions)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetOperation indicates an expected call of GetOperation.
-func (mr *MockStoreMockRecorder) GetOperation(arg0 any) *gomock.Call {
+// GetNegativeTransactions indicates an expected call of GetNegativeTransactions.
+func (mr *MockTransactionMockRecorder) GetNegativeTransactions(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOperation", reflect.TypeOf((*MockStore)(nil).GetOperation), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNegativeTransactions", reflect.TypeOf((*MockTransaction)(nil).GetNegativeTransactions), arg0, arg1, arg2)
 }
 
 // GetTransaction mocks base method.
-func (m *MockStore) GetTransaction(arg0 int) (*model.TransactionImpl, error) {
+func (m *MockTransaction) GetTransaction(arg0 context.Context, arg1 int) (*model.TransactionImpl, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransaction", arg0)
+	ret := m.ctrl.Call(m, "GetTransaction", arg0, arg1)
 	ret0, _ := ret[0].(*model.TransactionImpl)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTransaction indicates an expected call of GetTransaction.
-func (mr *MockStoreMockRecorder) GetTransaction(arg0 any) *gomock.Call {
+func (mr *MockTransactionMockRecorder) GetTransaction(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransaction", reflect.TypeOf((*MockStore)(nil).GetTransaction), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransaction", reflect.TypeOf((*MockTransaction)(nil).GetTransaction), arg0, arg1)
 }
 
-// MockAccount is a mock of Account interface.
-type MockAccount struct {
+// ListTransactions mocks base method.
+func (m *MockTransaction) ListTransactions(arg0 context.Context, arg1 int, arg2 model.TransactionFilter) (model.Transactions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransactions", arg0, arg1, arg2)
+	ret0, _ := ret[0].(model.Transactions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransactions indicates an expected call of ListTransactions.
+func (mr *MockTransactionMockRecorder) ListTransactions(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransactions", reflect.TypeOf((*MockTransaction)(nil).ListTransactions), arg0, arg1, arg2)
+}
+
+// ReverseTransaction mocks base method.
+func (m *MockTransaction) ReverseTransaction(arg0 context.Context, arg1 int, arg2 *model.Money) (*model.TransactionImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReverseTransaction", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.TransactionImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReverseTransaction indicates an expected call of ReverseTransaction.
+func (mr *MockTransactionMockRecorder) ReverseTransaction(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReverseTransaction", reflect.TypeOf((*MockTransaction)(nil).ReverseTransaction), arg0, arg1, arg2)
+}
+
+// SettlePayment mocks base method.
+func (m *MockTransaction) SettlePayment(arg0 context.Context, arg1 model.TransactionImpl) (*model.TransactionImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SettlePayment", arg0, arg1)
+	ret0, _ := ret[0].(*model.TransactionImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SettlePayment indicates an expected call of SettlePayment.
+func (mr *MockTransactionMockRecorder) SettlePayment(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SettlePayment", reflect.TypeOf((*MockTransaction)(nil).SettlePayment), arg0, arg1)
+}
+
+// UpdateNegativeTransactions mocks base method.
+func (m *MockTransaction) UpdateNegativeTransactions(arg0 context.Context, arg1 model.Transactions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNegativeTransactions", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateNegativeTransactions indicates an expected call of UpdateNegativeTransactions.
+func (mr *MockTransactionMockRecorder) UpdateNegativeTransactions(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNegativeTransactions", reflect.TypeOf((*MockTransaction)(nil).UpdateNegativeTransactions), arg0, arg1)
+}
+
+// MockAuthorization is a mock of Authorization interface.
+type MockAuthorization struct {
 	ctrl     *gomock.Controller
-	recorder *MockAccountMockRecorder
+	recorder *MockAuthorizationMockRecorder
 	isgomock struct{}
 }
 
-// MockAccountMockRecorder is the mock recorder for MockAccount.
-type MockAccountMockRecorder struct {
-	mock *MockAccount
+// MockAuthorizationMockRecorder is the mock recorder for MockAuthorization.
+type MockAuthorizationMockRecorder struct {
+	mock *MockAuthorization
 }
 
-// NewMockAccount creates a new mock instance.
-func NewMockAccount(ctrl *gomock.Controller) *MockAccount {
-	mock := &MockAccount{ctrl: ctrl}
-	mock.recorder = &MockAccountMockRecorder{mock}
+// NewMockAuthorization creates a new mock instance.
+func NewMockAuthorization(ctrl *gomock.Controller) *MockAuthorization {
+	mock := &MockAuthorization{ctrl: ctrl}
+	mock.recorder = &MockAuthorizationMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockAccount) EXPECT() *MockAccountMockRecorder {
+func (m *MockAuthorization) EXPECT() *MockAuthorizationMockRecorder {
 	return m.recorder
 }
 
-// CreateAccount mocks base method.
-func (m *MockAccount) CreateAccount(arg0 string) (*model.AccountImpl, error) {
+// CaptureAuthorization mocks base method.
+func (m *MockAuthorization) CaptureAuthorization(arg0 context.Context, arg1 int, arg2 *model.Money) (*model.TransactionImpl, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateAccount", arg0)
-	ret0, _ := ret[0].(*model.AccountImpl)
+	ret := m.ctrl.Call(m, "CaptureAuthorization", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.TransactionImpl)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateAccount indicates an expected call of CreateAccount.
-func (mr *MockAccountMockRecorder) CreateAccount(arg0 any) *gomock.Call {
+// CaptureAuthorization indicates an expected call of CaptureAuthorization.
+func (mr *MockAuthorizationMockRecorder) CaptureAuthorization(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockAccount)(nil).CreateAccount), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureAuthorization", reflect.TypeOf((*MockAuthorization)(nil).CaptureAuthorization), arg0, arg1, arg2)
 }
 
-// GetAccount mocks base method.
-func (m *MockAccount) GetAccount(arg0 int) (*model.AccountImpl, error) {
+// CreateAuthorization mocks base method.
+func (m *MockAuthorization) CreateAuthorization(arg0 context.Context, arg1 model.AuthorizationImpl) (*model.AuthorizationImpl, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccount", arg0)
-	ret0, _ := ret[0].(*model.AccountImpl)
+	ret := m.ctrl.Call(m, "CreateAuthorization", arg0, arg1)
+	ret0, _ := ret[0].(*model.AuthorizationImpl)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccount indicates an expected call of GetAccount.
-func (mr *MockAccountMockRecorder) GetAccount(arg0 any) *gomock.Call {
+// CreateAuthorization indicates an expected call of CreateAuthorization.
+func (mr *MockAuthorizationMockRecorder) CreateAuthorization(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockAccount)(nil).GetAccount), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuthorization", reflect.TypeOf((*MockAuthorization)(nil).CreateAuthorization), arg0, arg1)
 }
 
-// MockOperation is a mock of Operation interface.
-type MockOperation struct {
+// ExpireAuthorizations mocks base method.
+func (m *MockAuthorization) ExpireAuthorizations(arg0 context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireAuthorizations", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireAuthorizations indicates an expected call of ExpireAuthorizations.
+func (mr *MockAuthorizationMockRecorder) ExpireAuthorizations(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireAuthorizations", reflect.TypeOf((*MockAuthorization)(nil).ExpireAuthorizations), arg0)
+}
+
+// GetAuthorization mocks base method.
+func (m *MockAuthorization) GetAuthorization(arg0 context.Context, arg1 int) (*model.AuthorizationImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorization", arg0, arg1)
+	ret0, _ := ret[0].(*model.AuthorizationImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthorization indicates an expected call of GetAuthorization.
+func (mr *MockAuthorizationMockRecorder) GetAuthorization(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorization", reflect.TypeOf((*MockAuthorization)(nil).GetAuthorization), arg0, arg1)
+}
+
+// VoidAuthorization mocks base method.
+func (m *MockAuthorization) VoidAuthorization(arg0 context.Context, arg1 int) (*model.AuthorizationImpl, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VoidAuthorization", arg0, arg1)
+	ret0, _ := ret[0].(*model.AuthorizationImpl)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VoidAuthorization indicates an expected call of VoidAuthorization.
+func (mr *MockAuthorizationMockRecorder) VoidAuthorization(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoidAuthorization", reflect.TypeOf((*MockAuthorization)(nil).VoidAuthorization), arg0, arg1)
+}
+
+// MockInstallment is a mock of Installment interface.
+type MockInstallment struct {
 	ctrl     *gomock.Controller
-	recorder *MockOperationMockRecorder
+	recorder *MockInstallmentMockRecorder
 	isgomock struct{}
 }
 
-// MockOperationMockRecorder is the mock recorder for MockOperation.
-type MockOperationMockRecorder struct {
-	mock *MockOperation
+// MockInstallmentMockRecorder is the mock recorder for MockInstallment.
+type MockInstallmentMockRecorder struct {
+	mock *MockInstallment
 }
 
-// NewMockOperation creates a new mock instance.
-func NewMockOperation(ctrl *gomock.Controller) *MockOperation {
-	mock := &MockOperation{ctrl: ctrl}
-	mock.recorder = &MockOperationMockRecorder{mock}
+// NewMockInstallment creates a new mock instance.
+func NewMockInstallment(ctrl *gomock.Controller) *MockInstallment {
+	mock := &MockInstallment{ctrl: ctrl}
+	mock.recorder = &MockInstallmentMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockOperation) EXPECT() *MockOperationMockRecorder {
+func (m *MockInstallment) EXPECT() *MockInstallmentMockRecorder {
 	return m.recorder
 }
 
-// GetOperation mocks base method.
-func (m *MockOperation) GetOperation(arg0 int) (*model.OperationImpl, error) {
+// CreateInstallmentPlan mocks base method.
+func (m *MockInstallment) CreateInstallmentPlan(arg0 context.Context, arg1 model.TransactionImpl, arg2 int) (*model.InstallmentPlan, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOperation", arg0)
-	ret0, _ := ret[0].(*model.OperationImpl)
+	ret := m.ctrl.Call(m, "CreateInstallmentPlan", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.InstallmentPlan)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetOperation indicates an expected call of GetOperation.
-func (mr *MockOperationMockRecorder) GetOperation(arg0 any) *gomock.Call {
+// CreateInstallmentPlan indicates an expected call of CreateInstallmentPlan.
+func (mr *MockInstallmentMockRecorder) CreateInstallmentPlan(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOperation", reflect.TypeOf((*MockOperation)(nil).GetOperation), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInstallmentPlan", reflect.TypeOf((*MockInstallment)(nil).CreateInstallmentPlan), arg0, arg1, arg2)
 }
 
-// MockTransaction is a mock of Transaction interface.
-type MockTransaction struct {
+// GetInstallmentPlan mocks base method.
+func (m *MockInstallment) GetInstallmentPlan(arg0 context.Context, arg1 int) (*model.InstallmentPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInstallmentPlan", arg0, arg1)
+	ret0, _ := ret[0].(*model.InstallmentPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInstallmentPlan indicates an expected call of GetInstallmentPlan.
+func (mr *MockInstallmentMockRecorder) GetInstallmentPlan(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInstallmentPlan", reflect.TypeOf((*MockInstallment)(nil).GetInstallmentPlan), arg0, arg1)
+}
+
+// MockTransfer is a mock of Transfer interface.
+type MockTransfer struct {
 	ctrl     *gomock.Controller
-	recorder *MockTransactionMockRecorder
+	recorder *MockTransferMockRecorder
 	isgomock struct{}
 }
 
-// MockTransactionMockRecorder is the mock recorder for MockTransaction.
-type MockTransactionMockRecorder struct {
-	mock *MockTransaction
+// MockTransferMockRecorder is the mock recorder for MockTransfer.
+type MockTransferMockRecorder struct {
+	mock *MockTransfer
 }
 
-// NewMockTransaction creates a new mock instance.
-func NewMockTransaction(ctrl *gomock.Controller) *MockTransaction {
-	mock := &MockTransaction{ctrl: ctrl}
-	mock.recorder = &MockTransactionMockRecorder{mock}
+// NewMockTransfer creates a new mock instance.
+func NewMockTransfer(ctrl *gomock.Controller) *MockTransfer {
+	mock := &MockTransfer{ctrl: ctrl}
+	mock.recorder = &MockTransferMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockTransaction) EXPECT() *MockTransactionMockRecorder {
+func (m *MockTransfer) EXPECT() *MockTransferMockRecorder {
 	return m.recorder
 }
 
-// CreateTransaction mocks base method.
-func (m *MockTransaction) CreateTransaction(arg0 model.TransactionImpl) (*model.TransactionImpl, error) {
+// CreateTransfer mocks base method.
+func (m *MockTransfer) CreateTransfer(arg0 context.Context, arg1 model.Transfer) (*model.Transfer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTransaction", arg0)
-	ret0, _ := ret[0].(*model.TransactionImpl)
+	ret := m.ctrl.Call(m, "CreateTransfer", arg0, arg1)
+	ret0, _ := ret[0].(*model.Transfer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateTransaction indicates an expected call of CreateTransaction.
-func (mr *MockTransactionMockRecorder) CreateTransaction(arg0 any) *gomock.Call {
+// CreateTransfer indicates an expected call of CreateTransfer.
+func (mr *MockTransferMockRecorder) CreateTransfer(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransaction", reflect.TypeOf((*MockTransaction)(nil).CreateTransaction), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransfer", reflect.TypeOf((*MockTransfer)(nil).CreateTransfer), arg0, arg1)
 }
 
-// GetTransaction mocks base method.
-func (m *MockTransaction) GetTransaction(arg0 int) (*model.TransactionImpl, error) {
+// GetTransfer mocks base method.
+func (m *MockTransfer) GetTransfer(arg0 context.Context, arg1 int) (*model.Transfer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransaction", arg0)
-	ret0, _ := ret[0].(*model.TransactionImpl)
+	ret := m.ctrl.Call(m, "GetTransfer", arg0, arg1)
+	ret0, _ := ret[0].(*model.Transfer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTransaction indicates an expected call of GetTransaction.
-func (mr *MockTransactionMockRecorder) GetTransaction(arg0 any) *gomock.Call {
+// GetTransfer indicates an expected call of GetTransfer.
+func (mr *MockTransferMockRecorder) GetTransfer(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockTransfer)(nil).GetTransfer), arg0, arg1)
+}
+
+// MockFXRate is a mock of FXRate interface.
+type MockFXRate struct {
+	ctrl     *gomock.Controller
+	recorder *MockFXRateMockRecorder
+	isgomock struct{}
+}
+
+// MockFXRateMockRecorder is the mock recorder for MockFXRate.
+type MockFXRateMockRecorder struct {
+	mock *MockFXRate
+}
+
+// NewMockFXRate creates a new mock instance.
+func NewMockFXRate(ctrl *gomock.Controller) *MockFXRate {
+	mock := &MockFXRate{ctrl: ctrl}
+	mock.recorder = &MockFXRateMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFXRate) EXPECT() *MockFXRateMockRecorder {
+	return m.recorder
+}
+
+// GetFXRate mocks base method.
+func (m *MockFXRate) GetFXRate(arg0 context.Context, arg1, arg2 model.Currency) (*model.FXRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFXRate", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.FXRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFXRate indicates an expected call of GetFXRate.
+func (mr *MockFXRateMockRecorder) GetFXRate(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFXRate", reflect.TypeOf((*MockFXRate)(nil).GetFXRate), arg0, arg1, arg2)
+}
+
+// ListFXRates mocks base method.
+func (m *MockFXRate) ListFXRates(arg0 context.Context) ([]model.FXRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFXRates", arg0)
+	ret0, _ := ret[0].([]model.FXRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFXRates indicates an expected call of ListFXRates.
+func (mr *MockFXRateMockRecorder) ListFXRates(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFXRates", reflect.TypeOf((*MockFXRate)(nil).ListFXRates), arg0)
+}
+
+// UpdateFXRates mocks base method.
+func (m *MockFXRate) UpdateFXRates(arg0 context.Context, arg1 []model.FXRate) ([]model.FXRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFXRates", arg0, arg1)
+	ret0, _ := ret[0].([]model.FXRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateFXRates indicates an expected call of UpdateFXRates.
+func (mr *MockFXRateMockRecorder) UpdateFXRates(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFXRates", reflect.TypeOf((*MockFXRate)(nil).UpdateFXRates), arg0, arg1)
+}
+
+// MockIdempotency is a mock of Idempotency interface.
+type MockIdempotency struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyMockRecorder
+	isgomock struct{}
+}
+
+// MockIdempotencyMockRecorder is the mock recorder for MockIdempotency.
+type MockIdempotencyMockRecorder struct {
+	mock *MockIdempotency
+}
+
+// NewMockIdempotency creates a new mock instance.
+func NewMockIdempotency(ctrl *gomock.Controller) *MockIdempotency {
+	mock := &MockIdempotency{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotency) EXPECT() *MockIdempotencyMockRecorder {
+	return m.recorder
+}
+
+// CompleteIdempotencyKey mocks base method.
+func (m *MockIdempotency) CompleteIdempotencyKey(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteIdempotencyKey", ctx, key, statusCode, contentType, body)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteIdempotencyKey indicates an expected call of CompleteIdempotencyKey.
+func (mr *MockIdempotencyMockRecorder) CompleteIdempotencyKey(ctx, key, statusCode, contentType, body any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteIdempotencyKey", reflect.TypeOf((*MockIdempotency)(nil).CompleteIdempotencyKey), ctx, key, statusCode, contentType, body)
+}
+
+// PurgeIdempotencyKeys mocks base method.
+func (m *MockIdempotency) PurgeIdempotencyKeys(ctx context.Context, expiresBefore time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeIdempotencyKeys", ctx, expiresBefore)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeIdempotencyKeys indicates an expected call of PurgeIdempotencyKeys.
+func (mr *MockIdempotencyMockRecorder) PurgeIdempotencyKeys(ctx, expiresBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeIdempotencyKeys", reflect.TypeOf((*MockIdempotency)(nil).PurgeIdempotencyKeys), ctx, expiresBefore)
+}
+
+// ReleaseIdempotencyKey mocks base method.
+func (m *MockIdempotency) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseIdempotencyKey", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseIdempotencyKey indicates an expected call of ReleaseIdempotencyKey.
+func (mr *MockIdempotencyMockRecorder) ReleaseIdempotencyKey(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseIdempotencyKey", reflect.TypeOf((*MockIdempotency)(nil).ReleaseIdempotencyKey), ctx, key)
+}
+
+// ReserveIdempotencyKey mocks base method.
+func (m *MockIdempotency) ReserveIdempotencyKey(ctx context.Context, key, requestHash string, createdAt, expiresBefore time.Time) (*model.IdempotencyRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveIdempotencyKey", ctx, key, requestHash, createdAt, expiresBefore)
+	ret0, _ := ret[0].(*model.IdempotencyRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveIdempotencyKey indicates an expected call of ReserveIdempotencyKey.
+func (mr *MockIdempotencyMockRecorder) ReserveIdempotencyKey(ctx, key, requestHash, createdAt, expiresBefore any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransaction", reflect.TypeOf((*MockTransaction)(nil).GetTransaction), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveIdempotencyKey", reflect.TypeOf((*MockIdempotency)(nil).ReserveIdempotencyKey), ctx, key, requestHash, createdAt, expiresBefore)
 }
@@ -0,0 +1,66 @@
+package model
+
+import "slices"
+
+// Allocation records that a credit, such as a payment, settled Amount of a
+// debit. Allocations are only ever added: releasing one, when either side
+// is reversed, is recorded as another with a negative amount.
+type Allocation struct {
+	CreditTransactionID int   `db:"Credit_Transaction_ID"`
+	DebitTransactionID  int   `db:"Debit_Transaction_ID"`
+	Amount              Money `db:"Amount"`
+}
+
+// Allocations lists allocations. Those returned by ProcessNegativePayments
+// and ProcessPositivePayments leave the side of the transaction being
+// posted 0, as it has no ID until it is stored; see For.
+type Allocations []Allocation
+
+// For returns the allocations with the sides left 0 set to the transaction.
+func (a Allocations) For(transactionId int) Allocations {
+	result := slices.Clone(a)
+	for i := range result {
+		if result[i].CreditTransactionID == 0 {
+			result[i].CreditTransactionID = transactionId
+		}
+		if result[i].DebitTransactionID == 0 {
+			result[i].DebitTransactionID = transactionId
+		}
+	}
+	return result
+}
+
+// Net sums the allocations, passed in the order they were made, of each
+// credit and debit pair, and drops the pairs that were fully released. The
+// pair allocated to last comes first.
+func (a Allocations) Net() Allocations {
+	type pair struct{ credit, debit int }
+	index := map[pair]int{}
+	var net Allocations
+	var last []int
+	for i, allocation := range a {
+		key := pair{allocation.CreditTransactionID, allocation.DebitTransactionID}
+		j, ok := index[key]
+		if !ok {
+			j = len(net)
+			index[key] = j
+			net = append(net, Allocation{CreditTransactionID: key.credit, DebitTransactionID: key.debit})
+			last = append(last, 0)
+		}
+		net[j].Amount += allocation.Amount
+		last[j] = i
+	}
+
+	order := make([]int, len(net))
+	for j := range order {
+		order[j] = j
+	}
+	slices.SortFunc(order, func(x, y int) int { return last[y] - last[x] })
+	result := Allocations{}
+	for _, j := range order {
+		if net[j].Amount > 0 {
+			result = append(result, net[j])
+		}
+	}
+	return result
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllocations_For(t *testing.T) {
+	// Given.
+	allocations := Allocations{
+		{DebitTransactionID: 2, Amount: MustParseMoney("5.00")},
+		{CreditTransactionID: 3, Amount: MustParseMoney("1.00")},
+		{CreditTransactionID: 4, DebitTransactionID: 5, Amount: MustParseMoney("-2.00")},
+	}
+
+	// When.
+	got := allocations.For(9)
+
+	// Then.
+	assert.Equal(t, Allocations{
+		{CreditTransactionID: 9, DebitTransactionID: 2, Amount: MustParseMoney("5.00")},
+		{CreditTransactionID: 3, DebitTransactionID: 9, Amount: MustParseMoney("1.00")},
+		{CreditTransactionID: 4, DebitTransactionID: 5, Amount: MustParseMoney("-2.00")},
+	}, got)
+	assert.Zero(t, allocations[0].CreditTransactionID, "the allocations are not changed")
+}
+
+func TestAllocations_Net(t *testing.T) {
+	// Given.
+	allocations := Allocations{
+		{CreditTransactionID: 1, DebitTransactionID: 2, Amount: MustParseMoney("30.00")},
+		{CreditTransactionID: 1, DebitTransactionID: 3, Amount: MustParseMoney("20.00")},
+		{CreditTransactionID: 4, DebitTransactionID: 2, Amount: MustParseMoney("10.00")},
+		{CreditTransactionID: 1, DebitTransactionID: 2, Amount: MustParseMoney("-5.00")},
+		{CreditTransactionID: 1, DebitTransactionID: 3, Amount: MustParseMoney("-20.00")},
+	}
+
+	// When.
+	got := allocations.Net()
+
+	// Then.
+	// The fully released pair is dropped and the last allocated comes first.
+	assert.Equal(t, Allocations{
+		{CreditTransactionID: 1, DebitTransactionID: 2, Amount: MustParseMoney("25.00")},
+		{CreditTransactionID: 4, DebitTransactionID: 2, Amount: MustParseMoney("10.00")},
+	}, got)
+}
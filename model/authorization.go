@@ -0,0 +1,98 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrNotDebit            = errors.New("only debits can be authorized")
+	ErrAuthorizationClosed = errors.New("authorization is no longer pending")
+	ErrCaptureExceedsHold  = errors.New("capture is more than the authorized amount")
+)
+
+// AuthorizationStatus is where an authorization is in its life cycle. Only
+// a pending authorization holds credit; the others are final.
+type AuthorizationStatus string
+
+const (
+	AuthorizationPending  AuthorizationStatus = "PENDING"
+	AuthorizationCaptured AuthorizationStatus = "CAPTURED"
+	AuthorizationVoided   AuthorizationStatus = "VOIDED"
+	AuthorizationExpired  AuthorizationStatus = "EXPIRED"
+)
+
+// AuthorizationImpl holds part of an account's available credit for a debit
+// that is posted later, when the authorization is captured.
+type AuthorizationImpl struct {
+	AuthorizationID *int `json:"authorization_id" db:"Authorization_ID"`
+	AccountID       int  `json:"account_id" db:"Account_ID"`
+	// OperationTypeID is the debit operation the capture is posted as.
+	OperationTypeID int `json:"operation_type_id" db:"OperationType_ID"`
+	// Amount is the amount held, stored negative like the debit.
+	Amount    Money               `json:"amount" db:"Amount" swaggertype:"number"`
+	Status    AuthorizationStatus `json:"status" db:"Status"`
+	CreatedAt *time.Time          `json:"created_at,omitempty" db:"Created_At"`
+	// ExpiresAt is when the hold lapses if it is neither captured nor
+	// voided first.
+	ExpiresAt *time.Time `json:"expires_at,omitempty" db:"Expires_At"`
+	// ClosedAt is when the authorization left the pending status.
+	ClosedAt *time.Time `json:"closed_at,omitempty" db:"Closed_At"`
+	// TransactionID is the debit posted by the capture.
+	TransactionID *int `json:"transaction_id,omitempty" db:"Transaction_ID"`
+}
+
+// AuthorizationRequest is the request to authorize a debit.
+type AuthorizationRequest struct {
+	AccountID       int   `json:"account_id"`
+	OperationTypeID int   `json:"operation_type_id"`
+	Amount          Money `json:"amount" swaggertype:"number"`
+}
+
+// CaptureRequest is the request to capture an authorization.
+type CaptureRequest struct {
+	// Amount to post, as a positive amount. When left out, the whole
+	// authorized amount is posted. The rest of a partial capture is
+	// released.
+	Amount *Money `json:"amount,omitempty" swaggertype:"number"`
+}
+
+// CheckPending returns ErrAuthorizationClosed unless the authorization is
+// pending and has not expired by now. An authorization can outlive its
+// expiry until the sweeper marks it expired, but it no longer holds credit
+// and cannot be captured.
+func (a *AuthorizationImpl) CheckPending(now time.Time) error {
+	switch {
+	case a.Status != AuthorizationPending:
+		return fmt.Errorf("%w: authorization %d is %s", ErrAuthorizationClosed, *a.AuthorizationID, a.Status)
+	case a.ExpiresAt != nil && !a.ExpiresAt.After(now):
+		return fmt.Errorf("%w: authorization %d expired at %s", ErrAuthorizationClosed, *a.AuthorizationID, a.ExpiresAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// NewCapture returns the debit that captures amount of the authorization,
+// or all of it if amount is nil.
+func (a *AuthorizationImpl) NewCapture(amount *Money) (*TransactionImpl, error) {
+	captured := a.Amount
+	if amount != nil {
+		if *amount > -a.Amount {
+			return nil, fmt.Errorf("%w: authorization %d holds %s", ErrCaptureExceedsHold, *a.AuthorizationID, -a.Amount)
+		}
+		captured = -*amount
+	}
+	return NewTransaction(nil, a.AccountID, a.OperationTypeID, captured, 0, nil), nil
+}
+
+// Held returns the amount the pending authorizations that have not expired
+// by now hold, as a positive amount.
+func Held(authorizations []AuthorizationImpl, now time.Time) Money {
+	var total Money
+	for _, authorization := range authorizations {
+		if authorization.CheckPending(now) == nil {
+			total -= authorization.Amount
+		}
+	}
+	return total
+}
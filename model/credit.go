@@ -0,0 +1,83 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrCreditLimitExceeded = errors.New("credit limit exceeded")
+
+// MaxCreditLimit is the largest credit limit an account can be given.
+var MaxCreditLimit = MustParseMoney("100000000.00")
+
+// CreditLimitChange records a change of an account's credit limit.
+type CreditLimitChange struct {
+	ChangeID  *int   `json:"change_id" db:"Change_ID"`
+	AccountID int    `json:"account_id" db:"Account_ID"`
+	OldLimit  *Money `json:"old_limit" db:"Old_Limit" swaggertype:"number"`
+	NewLimit  Money  `json:"new_limit" db:"New_Limit" swaggertype:"number"`
+	// Reason says why the limit was changed, e.g. a credit review.
+	Reason    string     `json:"reason" db:"Reason"`
+	ChangedAt *time.Time `json:"changed_at,omitempty" db:"Changed_At"`
+}
+
+// CreditLimitHistory lists an account's credit limit changes.
+type CreditLimitHistory struct {
+	Changes []CreditLimitChange `json:"changes"`
+}
+
+// CreditLimitUpdate is the request to change an account's credit limit.
+type CreditLimitUpdate struct {
+	CreditLimit *Money `json:"credit_limit" swaggertype:"number"`
+	Reason      string `json:"reason"`
+}
+
+// Outstanding returns the unpaid balance of the debts as a positive amount.
+func Outstanding(debts Transactions) Money {
+	var total Money
+	for _, debt := range debts {
+		if debt.Balance < 0 {
+			total -= debt.Balance
+		}
+	}
+	return total
+}
+
+// PaymentCredit returns the unapplied balance of the payments.
+func PaymentCredit(credits Transactions) Money {
+	var total Money
+	for _, credit := range credits {
+		if credit.Balance > 0 {
+			total += credit.Balance
+		}
+	}
+	return total
+}
+
+// AvailableCredit returns how much more the account may owe given its
+// outstanding debts and the amount held by its pending authorizations, or
+// nil if it has no credit limit. It is negative if the limit was lowered
+// below what the account already owes.
+func (a *AccountImpl) AvailableCredit(debts Transactions, held Money) *Money {
+	if a.CreditLimit == nil {
+		return nil
+	}
+	available := *a.CreditLimit - Outstanding(debts) - held
+	return &available
+}
+
+// CheckDebit returns ErrCreditLimitExceeded if a debit of amount, stored
+// negative, does not fit in the account's available credit plus the
+// unapplied balance of its payments, which pay for debits first.
+func (a *AccountImpl) CheckDebit(debts Transactions, credits Transactions, held Money, amount Money) error {
+	available := a.AvailableCredit(debts, held)
+	if available == nil {
+		return nil
+	}
+	spendable := *available + PaymentCredit(credits)
+	if -amount <= spendable {
+		return nil
+	}
+	return fmt.Errorf("%w: %s is more than the available credit of %s", ErrCreditLimitExceeded, -amount, max(spendable, 0))
+}
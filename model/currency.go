@@ -0,0 +1,256 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Currency is an ISO 4217 alphabetic code, e.g. USD. Amounts keep two
+// fractional digits whatever their currency.
+type Currency string
+
+// DefaultCurrency is the currency of accounts opened without one. The
+// 0009_add_currencies migration gives it to the accounts and transactions
+// that existed before.
+const DefaultCurrency Currency = "USD"
+
+var (
+	ErrCurrencyMismatch = errors.New("currencies do not match")
+	ErrInvalidFXRate    = errors.New("invalid fx rate")
+)
+
+// Valid reports whether the currency is three upper case letters.
+func (c Currency) Valid() bool {
+	if len(c) != 3 {
+		return false
+	}
+	for _, r := range c {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// rateScale is the number of Rate units in 1.
+const rateScale = 100_000_000
+
+// MaxRate is the largest exchange rate accepted. It keeps converted
+// amounts well within the DECIMAL(18,2) columns.
+var MaxRate = MustParseRate("1000000")
+
+// Rate is an exact exchange rate with up to eight fractional digits. It
+// matches the DECIMAL(18,8) FX_Rate columns in the database.
+type Rate int64
+
+// ParseRate parses a decimal string with at most eight fractional digits.
+func ParseRate(s string) (Rate, error) {
+	units, fraction, hasPoint := strings.Cut(s, ".")
+	if units == "" || !isDigits(units) || (hasPoint && !isDigits(fraction)) {
+		return 0, fmt.Errorf("%w %q", ErrInvalidFXRate, s)
+	}
+	if len(fraction) > 8 {
+		return 0, fmt.Errorf("%w %q: more than eight fractional digits", ErrInvalidFXRate, s)
+	}
+
+	u, err := strconv.ParseInt(units, 10, 64)
+	if err != nil || u > math.MaxInt64/rateScale-1 {
+		return 0, fmt.Errorf("%w %q: out of range", ErrInvalidFXRate, s)
+	}
+	f, _ := strconv.ParseInt(fraction+strings.Repeat("0", 8-len(fraction)), 10, 64)
+	return Rate(u*rateScale + f), nil
+}
+
+// MustParseRate is like ParseRate but panics on error.
+// It is meant for constants and tests.
+func MustParseRate(s string) Rate {
+	r, err := ParseRate(s)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// String formats the rate without trailing zeros, e.g. 1.0825.
+func (r Rate) String() string {
+	s := fmt.Sprintf("%d.%08d", int64(r)/rateScale, int64(r)%rateScale)
+	return strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+}
+
+// MarshalJSON encodes the rate as a JSON number, e.g. 1.0825.
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or string with at most eight
+// fractional digits.
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	parsed, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// Scan implements sql.Scanner for DECIMAL columns.
+func (r *Rate) Scan(src any) error {
+	switch v := src.(type) {
+	case []byte:
+		return r.scanString(string(v))
+	case string:
+		return r.scanString(v)
+	case nil:
+		*r = 0
+		return nil
+	default:
+		return fmt.Errorf("%w: cannot scan %T", ErrInvalidFXRate, src)
+	}
+}
+
+func (r *Rate) scanString(s string) error {
+	// Be lenient about trailing zeros from other scales, as Money is.
+	if units, fraction, ok := strings.Cut(s, "."); ok && len(fraction) > 8 {
+		s = units + "." + strings.TrimRight(fraction, "0")
+	}
+	parsed, err := ParseRate(strings.TrimSuffix(s, "."))
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// Value implements driver.Valuer. The rate is sent as a decimal string so
+// the database stores it exactly.
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+// Convert multiplies the amount by the rate, rounding half away from zero
+// to the cent.
+func (r Rate) Convert(amount Money) Money {
+	product := new(big.Int).Mul(big.NewInt(int64(amount)), big.NewInt(int64(r)))
+	quotient, remainder := new(big.Int).QuoRem(product, big.NewInt(rateScale), new(big.Int))
+	if remainder.Abs(remainder).Cmp(big.NewInt(rateScale/2)) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(product.Sign())))
+	}
+	return Money(quotient.Int64())
+}
+
+// FXRate converts amounts from its base currency to its quote currency:
+// one unit of Base is worth Rate units of Quote. Rates only work in the
+// direction they are given.
+type FXRate struct {
+	Base      Currency   `json:"base_currency" db:"Base_Currency" swaggertype:"string"`
+	Quote     Currency   `json:"quote_currency" db:"Quote_Currency" swaggertype:"string"`
+	Rate      Rate       `json:"rate" db:"Rate" swaggertype:"number"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty" db:"Updated_At"`
+}
+
+// FXRateList is a set of exchange rates, as loaded and listed by the
+// admin endpoints.
+type FXRateList struct {
+	Rates []FXRate `json:"rates"`
+}
+
+// fxRatesCSVHeader is the header line FX rate CSV files must start with.
+var fxRatesCSVHeader = []string{"base_currency", "quote_currency", "rate"}
+
+// ParseFXRatesCSV reads exchange rates from CSV with the columns
+// base_currency, quote_currency and rate, after a header line naming them.
+// The rates still have to be validated.
+func ParseFXRatesCSV(r io.Reader) (*FXRateList, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = len(fxRatesCSVHeader)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	switch {
+	case err == io.EOF:
+		return nil, fmt.Errorf("%w: empty CSV", ErrInvalidFXRate)
+	case err != nil:
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFXRate, err)
+	}
+	for i, name := range fxRatesCSVHeader {
+		if !strings.EqualFold(strings.TrimSpace(header[i]), name) {
+			return nil, fmt.Errorf("%w: the CSV header must be %s", ErrInvalidFXRate, strings.Join(fxRatesCSVHeader, ","))
+		}
+	}
+
+	list := &FXRateList{Rates: []FXRate{}}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return list, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFXRate, err)
+		}
+		rate, err := ParseRate(strings.TrimSpace(record[2]))
+		if err != nil {
+			line, _ := reader.FieldPos(2)
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		list.Rates = append(list.Rates, FXRate{
+			Base:  Currency(strings.ToUpper(strings.TrimSpace(record[0]))),
+			Quote: Currency(strings.ToUpper(strings.TrimSpace(record[1]))),
+			Rate:  rate,
+		})
+	}
+}
+
+// Convert books the transaction in the rate's quote currency and keeps
+// the amount and currency it was sent in, with the rate, as its original
+// ones. The transaction must be in the rate's base currency.
+func (t *TransactionImpl) Convert(rate FXRate) error {
+	if t.Currency != rate.Base {
+		return fmt.Errorf("%w: cannot convert %s with a %s rate", ErrCurrencyMismatch, t.Currency, rate.Base)
+	}
+	amount := rate.Rate.Convert(t.Amount)
+	if amount == 0 {
+		return fmt.Errorf("%w: %s %s is less than 0.01 %s", ErrZeroAmount, t.Amount, t.Currency, rate.Quote)
+	}
+
+	originalAmount, originalCurrency, fxRate := t.Amount, t.Currency, rate.Rate
+	t.OriginalAmount, t.OriginalCurrency, t.FXRate = &originalAmount, &originalCurrency, &fxRate
+	t.Amount, t.Currency = amount, rate.Quote
+	return nil
+}
+
+// Book puts the transaction in the account's currency. Transactions sent
+// without a currency are in the account's; any other currency has to be
+// converted first.
+func (a *AccountImpl) Book(t *TransactionImpl) error {
+	switch t.Currency {
+	case "":
+		t.Currency = a.Currency
+	case a.Currency:
+	default:
+		return fmt.Errorf("%w: account %d is in %s, not %s", ErrCurrencyMismatch, *a.AccountID, a.Currency, t.Currency)
+	}
+	return nil
+}
+
+// checkCurrency refuses to settle transactions in another currency than
+// the amount settling them.
+func checkCurrency(transactions Transactions, currency Currency) error {
+	for _, transaction := range transactions {
+		if transaction.Currency != currency {
+			return fmt.Errorf("%w: transaction %d is in %s, not %s", ErrCurrencyMismatch, *transaction.TransactionID, transaction.Currency, currency)
+		}
+	}
+	return nil
+}
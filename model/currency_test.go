@@ -0,0 +1,195 @@
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurrency_Valid(t *testing.T) {
+	assert.True(t, Currency("EUR").Valid())
+	assert.False(t, Currency("").Valid())
+	assert.False(t, Currency("eur").Valid())
+	assert.False(t, Currency("EURO").Valid())
+	assert.False(t, Currency("E1R").Valid())
+}
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Rate
+		wantErr bool
+	}{
+		{in: "1", want: 100_000_000},
+		{in: "1.0825", want: 108_250_000},
+		{in: "0.00000001", want: 1},
+		{in: "150.5", want: 15_050_000_000},
+		{in: "0.000000001", wantErr: true},
+		{in: "-1", wantErr: true},
+		{in: "", wantErr: true},
+		{in: ".5", wantErr: true},
+		{in: "1e2", wantErr: true},
+		{in: "99999999999999999999", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseRate(tt.in)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidFXRate)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRate_JSON(t *testing.T) {
+	// Given.
+	var got struct {
+		Number Rate `json:"number"`
+		String Rate `json:"string"`
+	}
+
+	// When.
+	err := json.Unmarshal([]byte(`{"number": 1.0825, "string": "0.92"}`), &got)
+
+	// Then.
+	require.NoError(t, err)
+	assert.Equal(t, MustParseRate("1.0825"), got.Number)
+	assert.Equal(t, MustParseRate("0.92"), got.String)
+
+	out, err := json.Marshal(got)
+	require.NoError(t, err)
+	assert.Equal(t, `{"number":1.0825,"string":0.92}`, string(out))
+}
+
+func TestRate_ScanValue(t *testing.T) {
+	tests := []struct {
+		src  any
+		want Rate
+	}{
+		{src: []byte("1.08250000"), want: MustParseRate("1.0825")},
+		{src: "2", want: MustParseRate("2")},
+		{src: "0.9200000000", want: MustParseRate("0.92")},
+		{src: nil, want: 0},
+	}
+	for _, tt := range tests {
+		var got Rate
+		require.NoError(t, got.Scan(tt.src))
+		assert.Equal(t, tt.want, got)
+	}
+
+	// Values round-trip through the decimal string sent to the database.
+	for _, r := range []Rate{1, 100_000_000, 108_250_000, MaxRate} {
+		v, err := r.Value()
+		require.NoError(t, err)
+		var got Rate
+		require.NoError(t, got.Scan(v.(string)))
+		assert.Equal(t, r, got)
+	}
+}
+
+func TestRate_Convert(t *testing.T) {
+	tests := []struct {
+		name   string
+		rate   string
+		amount Money
+		want   Money
+	}{
+		{name: "exact", rate: "1.5", amount: MustParseMoney("10.00"), want: MustParseMoney("15.00")},
+		{name: "rounds down", rate: "1.0825", amount: MustParseMoney("0.01"), want: MustParseMoney("0.01")},
+		{name: "rounds half up", rate: "0.5", amount: MustParseMoney("0.01"), want: MustParseMoney("0.01")},
+		{name: "negative rounds half away from zero", rate: "0.5", amount: MustParseMoney("-0.01"), want: MustParseMoney("-0.01")},
+		{name: "below a cent", rate: "0.4", amount: MustParseMoney("0.01"), want: 0},
+		{name: "largest", rate: "1000000", amount: MaxAmount, want: MustParseMoney("1000000000000.00")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MustParseRate(tt.rate).Convert(tt.amount))
+		})
+	}
+}
+
+func TestParseFXRatesCSV(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		// Given.
+		in := "base_currency,quote_currency,rate\nEUR,USD,1.0825\n usd , eur , 0.92\n"
+
+		// When.
+		got, err := ParseFXRatesCSV(strings.NewReader(in))
+
+		// Then.
+		require.NoError(t, err)
+		assert.Equal(t, []FXRate{
+			{Base: "EUR", Quote: "USD", Rate: MustParseRate("1.0825")},
+			{Base: "USD", Quote: "EUR", Rate: MustParseRate("0.92")},
+		}, got.Rates)
+	})
+
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{name: "empty", in: ""},
+		{name: "no header", in: "EUR,USD,1.0825\n"},
+		{name: "missing column", in: "base_currency,quote_currency,rate\nEUR,USD\n"},
+		{name: "invalid rate", in: "base_currency,quote_currency,rate\nEUR,USD,one\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseFXRatesCSV(strings.NewReader(tt.in))
+			require.ErrorIs(t, err, ErrInvalidFXRate)
+		})
+	}
+}
+
+func TestTransactionImpl_Convert(t *testing.T) {
+	rate := FXRate{Base: "EUR", Quote: "USD", Rate: MustParseRate("1.0825")}
+
+	t.Run("converted", func(t *testing.T) {
+		// Given.
+		transaction := NewTransaction(nil, 1, OperationTypePurchase, MustParseMoney("-100.00"), 0, nil)
+		transaction.Currency = "EUR"
+
+		// When.
+		err := transaction.Convert(rate)
+
+		// Then.
+		require.NoError(t, err)
+		assert.Equal(t, MustParseMoney("-108.25"), transaction.Amount)
+		assert.Equal(t, Currency("USD"), transaction.Currency)
+		assert.Equal(t, MustParseMoney("-100.00"), *transaction.OriginalAmount)
+		assert.Equal(t, Currency("EUR"), *transaction.OriginalCurrency)
+		assert.Equal(t, rate.Rate, *transaction.FXRate)
+	})
+
+	t.Run("other currency", func(t *testing.T) {
+		transaction := NewTransaction(nil, 1, OperationTypePurchase, MustParseMoney("-100.00"), 0, nil)
+		transaction.Currency = "GBP"
+		require.ErrorIs(t, transaction.Convert(rate), ErrCurrencyMismatch)
+	})
+
+	t.Run("below a cent", func(t *testing.T) {
+		transaction := NewTransaction(nil, 1, OperationTypePurchase, MustParseMoney("-0.01"), 0, nil)
+		transaction.Currency = "EUR"
+		require.ErrorIs(t, transaction.Convert(FXRate{Base: "EUR", Quote: "JPY", Rate: MustParseRate("0.001")}), ErrZeroAmount)
+	})
+}
+
+func TestAccountImpl_Book(t *testing.T) {
+	account := NewAccount(IntToPtr(1), "12345678900", "EUR")
+
+	transaction := TransactionImpl{}
+	require.NoError(t, account.Book(&transaction))
+	assert.Equal(t, Currency("EUR"), transaction.Currency)
+
+	transaction = TransactionImpl{Currency: "EUR"}
+	require.NoError(t, account.Book(&transaction))
+
+	transaction = TransactionImpl{Currency: "USD"}
+	require.ErrorIs(t, account.Book(&transaction), ErrCurrencyMismatch)
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// IdempotencyRecord is the stored outcome of a request sent with an
+// Idempotency-Key header.
+type IdempotencyRecord struct {
+	Key         string    `db:"Idempotency_Key"`
+	RequestHash string    `db:"Request_Hash"`
+	StatusCode  int       `db:"Status_Code"`
+	ContentType string    `db:"Content_Type"`
+	Body        []byte    `db:"Response_Body"`
+	CreatedAt   time.Time `db:"Created_At"`
+}
+
+// InProgress reports whether the first request with the key has not
+// finished yet.
+func (r *IdempotencyRecord) InProgress() bool {
+	return r.StatusCode == 0
+}
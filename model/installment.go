@@ -0,0 +1,159 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MaxInstallments is the most installments a purchase can be split into.
+const MaxInstallments = 48
+
+var ErrInvalidInstallments = errors.New("invalid installment count")
+
+// InstallmentRounding says which installment takes the cents left over
+// when a purchase does not split evenly.
+type InstallmentRounding string
+
+const (
+	RemainderOnFirst InstallmentRounding = "first"
+	RemainderOnLast  InstallmentRounding = "last"
+)
+
+// ParseInstallmentRounding parses "first" or "last". The empty string is
+// "first".
+func ParseInstallmentRounding(s string) (InstallmentRounding, error) {
+	switch InstallmentRounding(s) {
+	case "", RemainderOnFirst:
+		return RemainderOnFirst, nil
+	case RemainderOnLast:
+		return RemainderOnLast, nil
+	}
+	return RemainderOnFirst, fmt.Errorf("invalid installment rounding %q: must be first or last", s)
+}
+
+// InstallmentPlan is an installment purchase split into monthly debits.
+type InstallmentPlan struct {
+	PlanID          *int `json:"plan_id" db:"Plan_ID"`
+	AccountID       int  `json:"account_id" db:"Account_ID"`
+	OperationTypeID int  `json:"operation_type_id" db:"OperationType_ID"`
+	// Amount is the purchase's total, stored negative like a debit.
+	Amount           Money      `json:"amount" db:"Amount" swaggertype:"number"`
+	InstallmentCount int        `json:"installment_count" db:"Installment_Count"`
+	CreatedAt        *time.Time `json:"created_at,omitempty" db:"Created_At"`
+	// Currency, OriginalAmount, OriginalCurrency and FXRate are the
+	// purchase's. Each installment is in the same currency.
+	Currency         Currency  `json:"currency,omitempty" db:"Currency" swaggertype:"string"`
+	OriginalAmount   *Money    `json:"original_amount,omitempty" db:"Original_Amount" swaggertype:"number"`
+	OriginalCurrency *Currency `json:"original_currency,omitempty" db:"Original_Currency" swaggertype:"string"`
+	FXRate           *Rate     `json:"fx_rate,omitempty" db:"FX_Rate" swaggertype:"number"`
+	// Debits are the installments, soonest due first.
+	Debits Transactions `json:"-" db:"-"`
+}
+
+// NewInstallmentPlan splits the purchase into count debits. The first is
+// due now and each of the others a month after the one before. The cents
+// that do not split evenly go on the first or last installment.
+func NewInstallmentPlan(purchase TransactionImpl, count int, rounding InstallmentRounding, now time.Time) (*InstallmentPlan, error) {
+	total := -purchase.Amount
+	if count < 1 || count > MaxInstallments || total < Money(count) {
+		return nil, fmt.Errorf("%w: %s cannot be split into %d installments", ErrInvalidInstallments, total, count)
+	}
+
+	plan := &InstallmentPlan{
+		AccountID:        purchase.AccountID,
+		OperationTypeID:  purchase.OperationTypeID,
+		Amount:           purchase.Amount,
+		InstallmentCount: count,
+		CreatedAt:        &now,
+		Currency:         purchase.Currency,
+		OriginalAmount:   purchase.OriginalAmount,
+		OriginalCurrency: purchase.OriginalCurrency,
+		FXRate:           purchase.FXRate,
+	}
+	share, remainder := total/Money(count), total%Money(count)
+	for i := range count {
+		amount := share
+		if (i == 0 && rounding != RemainderOnLast) || (i == count-1 && rounding == RemainderOnLast) {
+			amount += remainder
+		}
+		dueDate := addMonths(now, i)
+		debit := NewTransaction(nil, purchase.AccountID, purchase.OperationTypeID, -amount, 0, nil)
+		debit.DueDate = &dueDate
+		debit.Currency = purchase.Currency
+		plan.Debits = append(plan.Debits, *debit)
+	}
+	return plan, nil
+}
+
+// addMonths adds months to t, keeping its day unless the month is
+// shorter, e.g. a month after January 31 is the last day of February.
+func addMonths(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	first := time.Date(year, month+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(day, lastDay)-1)
+}
+
+// InstallmentStatus says whether an installment is still owed.
+type InstallmentStatus string
+
+const (
+	// InstallmentPaid has no balance left, whether it was paid, paid early
+	// or refunded.
+	InstallmentPaid InstallmentStatus = "PAID"
+	// InstallmentUnpaid is due and has a balance left.
+	InstallmentUnpaid InstallmentStatus = "UNPAID"
+	// InstallmentScheduled is not due yet.
+	InstallmentScheduled InstallmentStatus = "SCHEDULED"
+)
+
+// Installment is one line of an InstallmentSchedule.
+type Installment struct {
+	// Number counts the installments from 1.
+	Number        int               `json:"number"`
+	TransactionID *int              `json:"transaction_id"`
+	DueDate       *time.Time        `json:"due_date"`
+	Amount        Money             `json:"amount" swaggertype:"number"`
+	Balance       Money             `json:"balance" swaggertype:"number"`
+	Status        InstallmentStatus `json:"status"`
+}
+
+// InstallmentSchedule lists an installment plan's installments and what is
+// paid and still owed of it.
+type InstallmentSchedule struct {
+	InstallmentPlan
+	Installments []Installment `json:"installments"`
+	// Paid and Unpaid split the plan's amount, as positive amounts.
+	Paid   Money `json:"paid" swaggertype:"number"`
+	Unpaid Money `json:"unpaid" swaggertype:"number"`
+}
+
+// NewInstallmentSchedule builds the plan's schedule as of now.
+func NewInstallmentSchedule(plan *InstallmentPlan, now time.Time) *InstallmentSchedule {
+	schedule := &InstallmentSchedule{
+		InstallmentPlan: *plan,
+		Installments:    []Installment{},
+		Paid:            -plan.Amount,
+	}
+	for i, debit := range plan.Debits {
+		status := InstallmentScheduled
+		switch {
+		case debit.Balance == 0:
+			status = InstallmentPaid
+		case debit.IsDue(now):
+			status = InstallmentUnpaid
+		}
+		schedule.Installments = append(schedule.Installments, Installment{
+			Number:        i + 1,
+			TransactionID: debit.TransactionID,
+			DueDate:       debit.DueDate,
+			Amount:        debit.Amount,
+			Balance:       debit.Balance,
+			Status:        status,
+		})
+		schedule.Unpaid -= debit.Balance
+	}
+	schedule.Paid -= schedule.Unpaid
+	return schedule
+}
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// TransactionFilter selects and pages an account's transactions.
+type TransactionFilter struct {
+	// OperationTypeID, when set, only matches that operation type.
+	OperationTypeID *int
+	// From and To, when set, bound EventDate. From is inclusive and To is
+	// exclusive.
+	From *time.Time
+	To   *time.Time
+	// Descending lists the newest transactions first.
+	Descending bool
+	// After, when set, starts the page after this position.
+	After *Cursor
+	// Limit is the page size.
+	Limit int
+}
+
+// Cursor is a position in a list of transactions ordered by EventDate and
+// then Transaction_ID.
+type Cursor struct {
+	EventDate     time.Time
+	TransactionID int
+}
+
+// CursorAfter returns the cursor pointing just past the transaction.
+func CursorAfter(transaction TransactionImpl) *Cursor {
+	cursor := &Cursor{TransactionID: *transaction.TransactionID}
+	if transaction.EventDate != nil {
+		cursor.EventDate = *transaction.EventDate
+	}
+	return cursor
+}
+
+// Encode returns the cursor as an opaque string for clients.
+func (c Cursor) Encode() string {
+	raw := fmt.Sprintf("%s|%d", c.EventDate.UTC().Format(time.RFC3339Nano), c.TransactionID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a string returned by Cursor.Encode.
+func DecodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	date, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	eventDate, err := time.Parse(time.RFC3339Nano, date)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	transactionId, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &Cursor{EventDate: eventDate, TransactionID: transactionId}, nil
+}
+
+// TransactionPage is one page of an account's transactions.
+type TransactionPage struct {
+	Transactions Transactions `json:"transactions"`
+	// NextCursor is empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// NewTransactionPage builds a page from up to limit+1 transactions, using
+// the extra one only to tell whether there is a next page.
+func NewTransactionPage(transactions Transactions, limit int) *TransactionPage {
+	page := &TransactionPage{Transactions: transactions}
+	if page.Transactions == nil {
+		page.Transactions = Transactions{}
+	}
+	if len(transactions) > limit {
+		page.Transactions = transactions[:limit]
+		page.NextCursor = CursorAfter(transactions[limit-1]).Encode()
+	}
+	return page
+}
@@ -1,15 +1,60 @@
 package model
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Operation type IDs seeded by the 0002_seed_operation_types,
+// 0005_add_reversals and 0008_add_transfers migrations.
+const (
+	OperationTypePurchase = 1
+	// OperationTypeInstallmentPurchase may be split into installments.
+	OperationTypeInstallmentPurchase = 2
+	OperationTypePayment             = 4
+	// OperationTypeRefund reverses a debit.
+	OperationTypeRefund = 5
+	// OperationTypePaymentReversal reverses a credit.
+	OperationTypePaymentReversal = 6
+	// OperationTypeTransferOut and OperationTypeTransferIn are the debit
+	// and credit of a transfer.
+	OperationTypeTransferOut = 7
+	OperationTypeTransferIn  = 8
+)
 
 type AccountImpl struct {
 	AccountID      *int   `json:"account_id" db:"Account_ID"`
 	DocumentNumber string `json:"document_number" db:"Document_Number"`
+	// CreditLimit caps the account's outstanding debt, nil for no limit.
+	CreditLimit *Money `json:"credit_limit,omitempty" db:"Credit_Limit" swaggertype:"number"`
+	// Currency is what the account's transactions are booked in,
+	// DefaultCurrency if left out when the account is opened.
+	Currency Currency `json:"currency" db:"Currency" swaggertype:"string"`
+	// Status is changed through the block, unblock and close endpoints.
+	// Accounts are opened active.
+	Status AccountStatus `json:"status" db:"Status" swaggertype:"string"`
 }
 
+// Direction says which sign an operation's amounts are stored with.
+type Direction string
+
+const (
+	// DirectionDebit amounts are stored negative, e.g. purchases.
+	DirectionDebit Direction = "DEBIT"
+	// DirectionCredit amounts are stored positive, e.g. payments.
+	DirectionCredit Direction = "CREDIT"
+)
+
+var (
+	ErrZeroAmount        = errors.New("amount must not be zero")
+	ErrInvalidAmountSign = errors.New("amount has the wrong sign for the operation type")
+)
+
 type OperationImpl struct {
-	OperationTypeID int    `json:"operation_type_id" db:"OperationType_ID"`
-	Description     string `json:"description" db:"Description"`
+	OperationTypeID int       `json:"operation_type_id" db:"OperationType_ID"`
+	Description     string    `json:"description" db:"Description"`
+	Direction       Direction `json:"direction" db:"Direction"`
 }
 
 type Transactions []TransactionImpl
@@ -17,19 +62,41 @@ type TransactionImpl struct {
 	TransactionID   *int       `json:"transaction_id" db:"Transaction_ID"`
 	AccountID       int        `json:"account_id" db:"Account_ID"`
 	OperationTypeID int        `json:"operation_type_id" db:"OperationType_ID"`
-	Amount          float32    `json:"amount" db:"Amount"`
-	Balance         float32    `json:"balance" db:"Balance"`
-	EventDate       *time.Time `json:"-" db:"EventDate"`
+	Amount          Money      `json:"amount" db:"Amount" swaggertype:"number"`
+	Balance         Money      `json:"balance" db:"Balance" swaggertype:"number"`
+	EventDate       *time.Time `json:"event_date,omitempty" db:"EventDate"`
+	// OriginalTransactionID is the transaction a reversal reverses.
+	OriginalTransactionID *int `json:"original_transaction_id,omitempty" db:"Original_Transaction_ID"`
+	// PlanID is the installment plan an installment belongs to.
+	PlanID *int `json:"plan_id,omitempty" db:"Plan_ID"`
+	// DueDate is when an installment falls due. Until then it is not
+	// outstanding. Other transactions are due at once.
+	DueDate *time.Time `json:"due_date,omitempty" db:"Due_Date"`
+	// Installments splits an installment purchase into that many monthly
+	// installments. It is only read from requests.
+	Installments *int `json:"installments,omitempty" db:"-"`
+	// TransferID is the transfer a transfer's debit or credit belongs to.
+	TransferID *int `json:"transfer_id,omitempty" db:"Transfer_ID"`
+	// Currency is what Amount and Balance are in, always the account's.
+	// Requests may send an amount in another currency, which is converted.
+	Currency Currency `json:"currency,omitempty" db:"Currency" swaggertype:"string"`
+	// OriginalAmount and OriginalCurrency are what a converted transaction
+	// was sent in, and FXRate what it was converted with.
+	OriginalAmount   *Money    `json:"original_amount,omitempty" db:"Original_Amount" swaggertype:"number"`
+	OriginalCurrency *Currency `json:"original_currency,omitempty" db:"Original_Currency" swaggertype:"string"`
+	FXRate           *Rate     `json:"fx_rate,omitempty" db:"FX_Rate" swaggertype:"number"`
 }
 
-func NewAccount(accountId *int, documentNumber string) *AccountImpl {
+func NewAccount(accountId *int, documentNumber string, currency Currency) *AccountImpl {
 	return &AccountImpl{
 		AccountID:      accountId,
 		DocumentNumber: documentNumber,
+		Currency:       currency,
+		Status:         AccountActive,
 	}
 }
 
-func NewTransaction(transactionId *int, accountId int, operationTypeId int, amount float32, balance float32, eventDate *time.Time) *TransactionImpl {
+func NewTransaction(transactionId *int, accountId int, operationTypeId int, amount Money, balance Money, eventDate *time.Time) *TransactionImpl {
 	return &TransactionImpl{
 		TransactionID:   transactionId,
 		AccountID:       accountId,
@@ -40,15 +107,60 @@ func NewTransaction(transactionId *int, accountId int, operationTypeId int, amou
 	}
 }
 
+// IsDue reports whether the transaction is due by now.
+func (t *TransactionImpl) IsDue(now time.Time) bool {
+	return t.DueDate == nil || !t.DueDate.After(now)
+}
+
 func (t *OperationImpl) IsPurchase() bool {
-	return t.OperationTypeID == 1
+	return t.OperationTypeID == OperationTypePurchase
 }
 
 func (t *OperationImpl) IsPayment() bool {
-	return t.OperationTypeID == 4
+	return t.OperationTypeID == OperationTypePayment
+}
+
+// IsReversal reports whether the operation only reverses other
+// transactions.
+func (t *OperationImpl) IsReversal() bool {
+	return t.OperationTypeID == OperationTypeRefund || t.OperationTypeID == OperationTypePaymentReversal
+}
+
+// IsTransfer reports whether the operation is only posted by transfers.
+func (t *OperationImpl) IsTransfer() bool {
+	return t.OperationTypeID == OperationTypeTransferOut || t.OperationTypeID == OperationTypeTransferIn
+}
+
+func (t *OperationImpl) IsDebit() bool {
+	return t.Direction == DirectionDebit
+}
+
+// NormaliseAmount returns amount with the sign the operation is stored with.
+// Clients may send either a positive amount, which is signed here, or an
+// amount that already has the operation's sign. A negative credit is
+// rejected rather than flipped, as it most likely means the wrong operation.
+func (t *OperationImpl) NormaliseAmount(amount Money) (Money, error) {
+	switch {
+	case amount == 0:
+		return 0, ErrZeroAmount
+	case t.IsDebit() && amount > 0:
+		return -amount, nil
+	case !t.IsDebit() && amount < 0:
+		return 0, fmt.Errorf("%w: %s %s is a credit", ErrInvalidAmountSign, amount, t.Description)
+	}
+	return amount, nil
 }
 
-func ProcessNegativePayments(transactions Transactions, amount float32) (Transactions, float32, error) {
+// ProcessNegativePayments settles debts, in the order given, with a
+// payment in the currency given. Debts in any other currency are refused
+// with ErrCurrencyMismatch. It returns the debts with their new balances,
+// what the payment settled of each, and the part of the payment left over.
+func ProcessNegativePayments(transactions Transactions, amount Money, currency Currency) (Transactions, Allocations, Money, error) {
+	if err := checkCurrency(transactions, currency); err != nil {
+		return nil, nil, 0, err
+	}
+
+	var allocations Allocations
 	currAmount := amount
 	for i, transaction := range transactions {
 		// If payment + amount > 0
@@ -59,9 +171,40 @@ func ProcessNegativePayments(transactions Transactions, amount float32) (Transac
 			// Partial payment
 			transactions[i].Balance += currAmount
 			currAmount = 0
+		}
+		if settled := transactions[i].Balance - transaction.Balance; settled > 0 {
+			allocations = append(allocations, Allocation{DebitTransactionID: *transaction.TransactionID, Amount: settled})
+		}
+	}
+
+	return transactions, allocations, currAmount, nil
+}
+
+// ProcessPositivePayments is the mirror of ProcessNegativePayments: it draws
+// a debit, passed as a negative amount, down from the unapplied balances
+// of earlier payments in the order given. It returns the payments with
+// their new balances, what the debit took from each, and the part of the
+// debit they could not cover, which is still negative. Payments in another
+// currency than the debit's are refused likewise.
+func ProcessPositivePayments(transactions Transactions, amount Money, currency Currency) (Transactions, Allocations, Money, error) {
+	if err := checkCurrency(transactions, currency); err != nil {
+		return nil, nil, 0, err
+	}
+
+	var allocations Allocations
+	currAmount := amount
+	for i, transaction := range transactions {
+		if currAmount >= 0 {
 			break
 		}
+		applied := min(transaction.Balance, -currAmount)
+		if applied <= 0 {
+			continue
+		}
+		transactions[i].Balance -= applied
+		currAmount += applied
+		allocations = append(allocations, Allocation{CreditTransactionID: *transaction.TransactionID, Amount: applied})
 	}
 
-	return transactions, currAmount, nil
+	return transactions, allocations, currAmount, nil
 }
@@ -0,0 +1,608 @@
+package model
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessNegativePayments(t *testing.T) {
+	// Given.
+	transactions := Transactions{
+		{TransactionID: IntToPtr(1), Currency: DefaultCurrency, Balance: MustParseMoney("-50.00")},
+		{TransactionID: IntToPtr(2), Currency: DefaultCurrency, Balance: MustParseMoney("-23.50")},
+		{TransactionID: IntToPtr(3), Currency: DefaultCurrency, Balance: MustParseMoney("-18.70")},
+	}
+
+	// When.
+	got, allocations, left, err := ProcessNegativePayments(transactions, MustParseMoney("60.00"), DefaultCurrency)
+
+	// Then.
+	require.NoError(t, err)
+	assert.Equal(t, Money(0), left)
+	assert.Equal(t, []Money{0, MustParseMoney("-13.50"), MustParseMoney("-18.70")}, []Money{got[0].Balance, got[1].Balance, got[2].Balance})
+	assert.Equal(t, Allocations{
+		{DebitTransactionID: 1, Amount: MustParseMoney("50.00")},
+		{DebitTransactionID: 2, Amount: MustParseMoney("10.00")},
+	}, allocations)
+}
+
+// debts is a random list of negative purchase balances for quick.Check.
+type debts []Money
+
+func (debts) Generate(r *rand.Rand, size int) reflect.Value {
+	d := make(debts, r.Intn(size+1))
+	for i := range d {
+		d[i] = -Money(r.Int63n(1_000_000) + 1)
+	}
+	return reflect.ValueOf(d)
+}
+
+// Property: whatever the purchases, the amount taken off their balances,
+// which is what is allocated to them, plus the amount left over always
+// equals the payment, balances never go positive and older purchases are
+// settled before newer ones.
+func TestProcessNegativePayments_AllocationSumsToPayment(t *testing.T) {
+	property := func(d debts, payment uint32) bool {
+		amount := Money(payment)
+		transactions := make(Transactions, len(d))
+		for i, balance := range d {
+			transactions[i] = TransactionImpl{TransactionID: IntToPtr(i), Currency: DefaultCurrency, Balance: balance}
+		}
+
+		got, allocations, left, err := ProcessNegativePayments(transactions, amount, DefaultCurrency)
+		if err != nil || left < 0 {
+			return false
+		}
+		var recorded Money
+		for _, allocation := range allocations {
+			recorded += allocation.Amount
+		}
+
+		var allocated Money
+		for i, transaction := range got {
+			if transaction.Balance > 0 || transaction.Balance < d[i] {
+				return false
+			}
+			// A later purchase is only touched once earlier ones are cleared.
+			if i > 0 && transaction.Balance != d[i] && got[i-1].Balance != 0 {
+				return false
+			}
+			allocated += transaction.Balance - d[i]
+		}
+		return allocated+left == amount && recorded == allocated
+	}
+
+	require.NoError(t, quick.Check(property, &quick.Config{MaxCount: 2000}))
+}
+
+func TestProcessNegativePayments_RefusesMixedCurrencies(t *testing.T) {
+	// Given.
+	transactions := Transactions{
+		{TransactionID: IntToPtr(1), Currency: "USD", Balance: MustParseMoney("-50.00")},
+		{TransactionID: IntToPtr(2), Currency: "EUR", Balance: MustParseMoney("-23.50")},
+	}
+
+	// When.
+	_, _, _, err := ProcessNegativePayments(transactions, MustParseMoney("60.00"), "USD")
+
+	// Then.
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+	assert.Equal(t, MustParseMoney("-50.00"), transactions[0].Balance, "nothing is settled")
+}
+
+func TestProcessPositivePayments(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount Money
+		want   []Money
+		left   Money
+	}{
+		{name: "partly from the oldest", amount: MustParseMoney("-10.00"), want: []Money{MustParseMoney("20.00"), MustParseMoney("5.00")}},
+		{name: "oldest first", amount: MustParseMoney("-31.00"), want: []Money{0, MustParseMoney("4.00")}},
+		{name: "rest becomes debt", amount: MustParseMoney("-40.00"), want: []Money{0, 0}, left: MustParseMoney("-5.00")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given.
+			transactions := Transactions{
+				{TransactionID: IntToPtr(1), Currency: DefaultCurrency, Balance: MustParseMoney("30.00")},
+				{TransactionID: IntToPtr(2), Currency: DefaultCurrency, Balance: MustParseMoney("5.00")},
+			}
+
+			// When.
+			got, allocations, left, err := ProcessPositivePayments(transactions, tt.amount, DefaultCurrency)
+
+			// Then.
+			require.NoError(t, err)
+			assert.Equal(t, tt.left, left)
+			assert.Equal(t, tt.want, []Money{got[0].Balance, got[1].Balance})
+			var allocated Money
+			for _, allocation := range allocations {
+				assert.Zero(t, allocation.DebitTransactionID)
+				allocated += allocation.Amount
+			}
+			assert.Equal(t, tt.left-tt.amount, allocated)
+		})
+	}
+}
+
+func TestOperationImpl_NormaliseAmount(t *testing.T) {
+	purchase := &OperationImpl{OperationTypeID: 1, Description: "PURCHASE", Direction: DirectionDebit}
+	payment := &OperationImpl{OperationTypeID: 4, Description: "PAYMENT", Direction: DirectionCredit}
+
+	tests := []struct {
+		name      string
+		operation *OperationImpl
+		amount    Money
+		want      Money
+		wantErr   error
+	}{
+		{name: "positive debit is negated", operation: purchase, amount: 5000, want: -5000},
+		{name: "negative debit is kept", operation: purchase, amount: -5000, want: -5000},
+		{name: "positive credit is kept", operation: payment, amount: 5000, want: 5000},
+		{name: "negative credit is rejected", operation: payment, amount: -5000, wantErr: ErrInvalidAmountSign},
+		{name: "zero is rejected", operation: purchase, amount: 0, wantErr: ErrZeroAmount},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.operation.NormaliseAmount(tt.amount)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSettlementPolicy_Order(t *testing.T) {
+	debts := func() Transactions {
+		return Transactions{
+			{TransactionID: IntToPtr(1), OperationTypeID: 1},
+			{TransactionID: IntToPtr(2), OperationTypeID: 3},
+			{TransactionID: IntToPtr(3), OperationTypeID: 2},
+			{TransactionID: IntToPtr(4), OperationTypeID: 3},
+		}
+	}
+	ids := func(transactions Transactions) []int {
+		var ids []int
+		for _, transaction := range transactions {
+			ids = append(ids, *transaction.TransactionID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []int{1, 2, 3, 4}, ids(OldestFirst.Order(debts())))
+
+	policy, err := ParseSettlementPolicy("3, 1")
+	require.NoError(t, err)
+	assert.Equal(t, "3,1", policy.String())
+	assert.Equal(t, []int{2, 4, 1, 3}, ids(policy.Order(debts())))
+
+	_, err = ParseSettlementPolicy("withdrawals")
+	require.Error(t, err)
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	// Given.
+	cursor := Cursor{EventDate: time.Date(2025, 10, 27, 12, 30, 0, 0, time.UTC), TransactionID: 42}
+
+	// When.
+	got, err := DecodeCursor(cursor.Encode())
+
+	// Then.
+	require.NoError(t, err)
+	assert.Equal(t, &cursor, got)
+
+	_, err = DecodeCursor("not a cursor")
+	require.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestNewTransactionPage(t *testing.T) {
+	eventDate := time.Date(2025, 10, 27, 0, 0, 0, 0, time.UTC)
+	transactions := Transactions{
+		*NewTransaction(IntToPtr(1), 1, 1, -100, -100, &eventDate),
+		*NewTransaction(IntToPtr(2), 1, 1, -100, -100, &eventDate),
+		*NewTransaction(IntToPtr(3), 1, 1, -100, -100, &eventDate),
+	}
+
+	page := NewTransactionPage(transactions, 2)
+	assert.Len(t, page.Transactions, 2)
+	assert.Equal(t, Cursor{EventDate: eventDate, TransactionID: 2}.Encode(), page.NextCursor)
+
+	page = NewTransactionPage(transactions, 3)
+	assert.Len(t, page.Transactions, 3)
+	assert.Empty(t, page.NextCursor)
+
+	page = NewTransactionPage(nil, 3)
+	assert.NotNil(t, page.Transactions)
+}
+
+func TestAccountImpl_CheckDebit(t *testing.T) {
+	debts := Transactions{
+		{TransactionID: IntToPtr(1), Balance: MustParseMoney("-30.00")},
+		{TransactionID: IntToPtr(2), Balance: MustParseMoney("-20.00")},
+	}
+	tests := []struct {
+		name      string
+		limit     *Money
+		held      Money
+		credit    Money
+		amount    Money
+		available *Money
+		wantErr   bool
+	}{
+		{name: "no limit", limit: nil, amount: MustParseMoney("-1000000.00")},
+		{name: "fits", limit: MoneyToPtr(MustParseMoney("100.00")), amount: MustParseMoney("-49.99"), available: MoneyToPtr(MustParseMoney("50.00"))},
+		{name: "exactly", limit: MoneyToPtr(MustParseMoney("100.00")), amount: MustParseMoney("-50.00"), available: MoneyToPtr(MustParseMoney("50.00"))},
+		{name: "exceeds", limit: MoneyToPtr(MustParseMoney("100.00")), amount: MustParseMoney("-50.01"), available: MoneyToPtr(MustParseMoney("50.00")), wantErr: true},
+		{name: "limit lowered below debt", limit: MoneyToPtr(MustParseMoney("40.00")), amount: MustParseMoney("-0.01"), available: MoneyToPtr(MustParseMoney("-10.00")), wantErr: true},
+		{name: "held", limit: MoneyToPtr(MustParseMoney("100.00")), held: MustParseMoney("20.00"), amount: MustParseMoney("-30.01"), available: MoneyToPtr(MustParseMoney("30.00")), wantErr: true},
+		{name: "payment credit", limit: MoneyToPtr(MustParseMoney("100.00")), credit: MustParseMoney("5.00"), amount: MustParseMoney("-55.00"), available: MoneyToPtr(MustParseMoney("50.00"))},
+		// The hold has a claim on the payment credit too.
+		{name: "held and payment credit", limit: MoneyToPtr(MustParseMoney("100.00")), held: MustParseMoney("5.00"), credit: MustParseMoney("5.00"), amount: MustParseMoney("-50.01"), available: MoneyToPtr(MustParseMoney("45.00")), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given.
+			account := AccountImpl{CreditLimit: tt.limit}
+			credits := Transactions{{TransactionID: IntToPtr(3), Balance: tt.credit}}
+
+			// When.
+			err := account.CheckDebit(debts, credits, tt.held, tt.amount)
+
+			// Then.
+			assert.Equal(t, tt.available, account.AvailableCredit(debts, tt.held))
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrCreditLimitExceeded)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+// Settling debts with ProcessNegativePayments restores the available
+// credit by the amount paid.
+func TestAccountImpl_AvailableCreditRestoredByPayments(t *testing.T) {
+	// Given.
+	account := AccountImpl{CreditLimit: MoneyToPtr(MustParseMoney("100.00"))}
+	debts := Transactions{
+		{TransactionID: IntToPtr(1), Currency: DefaultCurrency, Balance: MustParseMoney("-60.00")},
+		{TransactionID: IntToPtr(2), Currency: DefaultCurrency, Balance: MustParseMoney("-40.00")},
+	}
+	require.ErrorIs(t, account.CheckDebit(debts, nil, 0, -1), ErrCreditLimitExceeded)
+
+	// When.
+	debts, _, _, err := ProcessNegativePayments(debts, MustParseMoney("70.00"), DefaultCurrency)
+	require.NoError(t, err)
+
+	// Then.
+	assert.Equal(t, MustParseMoney("70.00"), *account.AvailableCredit(debts, 0))
+	assert.NoError(t, account.CheckDebit(debts, nil, 0, MustParseMoney("-70.00")))
+}
+
+func TestNewAccountBalance(t *testing.T) {
+	// Given.
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	dueDate, nextDueDate := now, now.AddDate(0, 1, 0)
+	account := &AccountImpl{AccountID: IntToPtr(1), CreditLimit: MoneyToPtr(MustParseMoney("100.00"))}
+	transactions := Transactions{
+		{TransactionID: IntToPtr(1), OperationTypeID: 3, Balance: MustParseMoney("-20.00")},
+		{TransactionID: IntToPtr(2), OperationTypeID: 1, Balance: MustParseMoney("-10.00")},
+		{TransactionID: IntToPtr(3), OperationTypeID: 1, Balance: MustParseMoney("-5.50")},
+		{TransactionID: IntToPtr(4), OperationTypeID: 4, Balance: MustParseMoney("2.00")},
+		{TransactionID: IntToPtr(5), OperationTypeID: 2, Balance: MustParseMoney("-1.00"), DueDate: &dueDate},
+		{TransactionID: IntToPtr(6), OperationTypeID: 2, Balance: MustParseMoney("-6.00"), DueDate: &nextDueDate},
+	}
+
+	// When.
+	balance := NewAccountBalance(account, transactions, MustParseMoney("4.00"), now)
+
+	// Then.
+	assert.Equal(t, &AccountBalance{
+		AccountID:   1,
+		Outstanding: MustParseMoney("36.50"),
+		Debts: []OperationDebt{
+			{OperationTypeID: 1, Outstanding: MustParseMoney("15.50")},
+			{OperationTypeID: 2, Outstanding: MustParseMoney("1.00")},
+			{OperationTypeID: 3, Outstanding: MustParseMoney("20.00")},
+		},
+		PaymentCredit:   MustParseMoney("2.00"),
+		Scheduled:       MustParseMoney("6.00"),
+		Held:            MustParseMoney("4.00"),
+		CreditLimit:     MoneyToPtr(MustParseMoney("100.00")),
+		AvailableCredit: MoneyToPtr(MustParseMoney("53.50")),
+	}, balance)
+}
+
+func TestNewStatement(t *testing.T) {
+	// Given.
+	transactions := Transactions{
+		{TransactionID: IntToPtr(1), Amount: MustParseMoney("-20.00")},
+		{TransactionID: IntToPtr(2), Amount: MustParseMoney("50.00")},
+	}
+
+	// When.
+	statement := NewStatement(1, nil, nil, MustParseMoney("-45.00"), transactions)
+	empty := NewStatement(1, nil, nil, MustParseMoney("-45.00"), nil)
+
+	// Then.
+	assert.Equal(t, MustParseMoney("-15.00"), statement.ClosingBalance)
+	assert.Equal(t, Transactions{}, empty.Transactions)
+	assert.Equal(t, empty.OpeningBalance, empty.ClosingBalance)
+}
+
+func TestNewReversal(t *testing.T) {
+	purchase := NewTransaction(IntToPtr(1), 7, OperationTypePurchase, MustParseMoney("-50.00"), 0, nil)
+	payment := NewTransaction(IntToPtr(2), 7, OperationTypePayment, MustParseMoney("80.00"), 0, nil)
+	refund := NewTransaction(IntToPtr(3), 7, OperationTypeRefund, MustParseMoney("10.00"), 0, nil)
+	refund.OriginalTransactionID = IntToPtr(1)
+	transferOut := NewTransaction(IntToPtr(4), 7, OperationTypeTransferOut, MustParseMoney("-10.00"), 0, nil)
+	transferOut.TransferID = IntToPtr(1)
+
+	tests := []struct {
+		name      string
+		original  *TransactionImpl
+		reversed  Money
+		amount    *Money
+		operation int
+		want      Money
+		wantErr   error
+	}{
+		{name: "full refund", original: purchase, operation: OperationTypeRefund, want: MustParseMoney("50.00")},
+		{name: "partial refund", original: purchase, amount: MoneyToPtr(MustParseMoney("20.00")), operation: OperationTypeRefund, want: MustParseMoney("20.00")},
+		{name: "rest of a refund", original: purchase, reversed: MustParseMoney("20.00"), operation: OperationTypeRefund, want: MustParseMoney("30.00")},
+		{name: "payment reversal", original: payment, reversed: MustParseMoney("-30.00"), operation: OperationTypePaymentReversal, want: MustParseMoney("-50.00")},
+		{name: "more than is left", original: purchase, reversed: MustParseMoney("20.00"), amount: MoneyToPtr(MustParseMoney("30.01")), wantErr: ErrReversalExceedsAmount},
+		{name: "fully reversed", original: payment, reversed: MustParseMoney("-80.00"), wantErr: ErrAlreadyReversed},
+		{name: "reversal", original: refund, wantErr: ErrNotReversible},
+		{name: "transfer", original: transferOut, wantErr: ErrNotReversible},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When.
+			reversal, err := NewReversal(tt.original, tt.reversed, tt.amount)
+
+			// Then.
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.operation, reversal.OperationTypeID)
+			assert.Equal(t, tt.want, reversal.Amount)
+			assert.Equal(t, tt.original.TransactionID, reversal.OriginalTransactionID)
+		})
+	}
+}
+
+func TestSettlementPolicy_SettleReversal(t *testing.T) {
+	t.Run("refund", func(t *testing.T) {
+		// Given.
+		// Payments 4 and 5 paid 20.00 of the purchase, another debt is
+		// open and an installment is not due yet.
+		original := NewTransaction(IntToPtr(1), 7, 1, MustParseMoney("-50.00"), MustParseMoney("-30.00"), nil)
+		allocations := Allocations{
+			{CreditTransactionID: 5, DebitTransactionID: 1, Amount: MustParseMoney("5.00")},
+			{CreditTransactionID: 4, DebitTransactionID: 1, Amount: MustParseMoney("15.00")},
+		}
+		allocated := Transactions{*NewTransaction(IntToPtr(4), 7, 4, MustParseMoney("15.00"), 0, nil), *NewTransaction(IntToPtr(5), 7, 4, MustParseMoney("5.00"), 0, nil)}
+		debts := Transactions{*original, *NewTransaction(IntToPtr(2), 7, 3, MustParseMoney("-15.00"), MustParseMoney("-15.00"), nil)}
+		scheduled := Transactions{*NewTransaction(IntToPtr(3), 7, 2, MustParseMoney("-3.00"), MustParseMoney("-3.00"), nil)}
+		refund := &TransactionImpl{Amount: MustParseMoney("50.00")}
+
+		// When.
+		got, recorded, err := OldestFirst.SettleReversal(original, refund, allocations, allocated, debts, scheduled, nil)
+
+		// Then.
+		// Payment 5 gets its 5.00 back and pays the other debt with it;
+		// payment 4 gets its 15.00 back, which pays off the rest of the
+		// debt and the installment early and keeps 2.00.
+		require.NoError(t, err)
+		balances := map[int]Money{}
+		for _, transaction := range got {
+			balances[*transaction.TransactionID] = transaction.Balance
+		}
+		assert.Equal(t, map[int]Money{1: 0, 2: 0, 3: 0, 4: MustParseMoney("2.00"), 5: 0}, balances)
+		assert.Equal(t, Allocations{
+			{CreditTransactionID: 5, DebitTransactionID: 1, Amount: MustParseMoney("-5.00")},
+			{CreditTransactionID: 5, DebitTransactionID: 2, Amount: MustParseMoney("5.00")},
+			{CreditTransactionID: 4, DebitTransactionID: 1, Amount: MustParseMoney("-15.00")},
+			{CreditTransactionID: 4, DebitTransactionID: 2, Amount: MustParseMoney("10.00")},
+			{CreditTransactionID: 4, DebitTransactionID: 3, Amount: MustParseMoney("3.00")},
+		}, recorded)
+		assert.Equal(t, Money(0), refund.Balance)
+	})
+
+	t.Run("payment reversal", func(t *testing.T) {
+		// Given.
+		// 60.00 of the payment settled a purchase, and another payment has
+		// credit.
+		original := NewTransaction(IntToPtr(1), 7, 4, MustParseMoney("80.00"), MustParseMoney("20.00"), nil)
+		allocations := Allocations{{CreditTransactionID: 1, DebitTransactionID: 3, Amount: MustParseMoney("60.00")}}
+		allocated := Transactions{*NewTransaction(IntToPtr(3), 7, 1, MustParseMoney("-60.00"), 0, nil)}
+		credits := Transactions{*original, *NewTransaction(IntToPtr(2), 7, 4, MustParseMoney("10.00"), MustParseMoney("10.00"), nil)}
+		reversal := &TransactionImpl{Amount: MustParseMoney("-80.00")}
+
+		// When.
+		got, recorded, err := OldestFirst.SettleReversal(original, reversal, allocations, allocated, nil, nil, credits)
+
+		// Then.
+		// The purchase is owed again, less the other payment's credit.
+		require.NoError(t, err)
+		balances := map[int]Money{}
+		for _, transaction := range got {
+			balances[*transaction.TransactionID] = transaction.Balance
+		}
+		assert.Equal(t, map[int]Money{1: 0, 2: 0, 3: MustParseMoney("-50.00")}, balances)
+		assert.Equal(t, Allocations{
+			{CreditTransactionID: 1, DebitTransactionID: 3, Amount: MustParseMoney("-60.00")},
+			{CreditTransactionID: 2, DebitTransactionID: 3, Amount: MustParseMoney("10.00")},
+		}, recorded)
+		assert.Equal(t, Money(0), reversal.Balance)
+	})
+
+	t.Run("unrecorded allocations", func(t *testing.T) {
+		// Given.
+		// The purchase was paid before allocations were recorded.
+		original := NewTransaction(IntToPtr(1), 7, 1, MustParseMoney("-50.00"), MustParseMoney("-30.00"), nil)
+		debts := Transactions{*original, *NewTransaction(IntToPtr(2), 7, 3, MustParseMoney("-15.00"), MustParseMoney("-15.00"), nil)}
+		refund := &TransactionImpl{Amount: MustParseMoney("50.00")}
+
+		// When.
+		got, recorded, err := OldestFirst.SettleReversal(original, refund, nil, nil, debts, nil, nil)
+
+		// Then.
+		// The refund pays the other debt itself and keeps the rest.
+		require.NoError(t, err)
+		assert.Equal(t, []Money{0, 0}, []Money{got[0].Balance, got[1].Balance})
+		assert.Equal(t, Allocations{{DebitTransactionID: 2, Amount: MustParseMoney("15.00")}}, recorded)
+		assert.Equal(t, MustParseMoney("5.00"), refund.Balance)
+	})
+}
+
+func TestAuthorizationImpl_CheckPending(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		status    AuthorizationStatus
+		expiresAt time.Time
+		wantErr   bool
+	}{
+		{name: "pending", status: AuthorizationPending, expiresAt: now.Add(time.Second)},
+		{name: "expiring now", status: AuthorizationPending, expiresAt: now, wantErr: true},
+		{name: "captured", status: AuthorizationCaptured, expiresAt: now.Add(time.Hour), wantErr: true},
+		{name: "voided", status: AuthorizationVoided, expiresAt: now.Add(time.Hour), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given.
+			authorization := AuthorizationImpl{AuthorizationID: IntToPtr(1), Status: tt.status, ExpiresAt: &tt.expiresAt}
+
+			// When.
+			err := authorization.CheckPending(now)
+
+			// Then.
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrAuthorizationClosed)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAuthorizationImpl_NewCapture(t *testing.T) {
+	authorization := AuthorizationImpl{AuthorizationID: IntToPtr(1), AccountID: 7, OperationTypeID: OperationTypePurchase, Amount: MustParseMoney("-50.00")}
+
+	full, err := authorization.NewCapture(nil)
+	require.NoError(t, err)
+	assert.Equal(t, NewTransaction(nil, 7, OperationTypePurchase, MustParseMoney("-50.00"), 0, nil), full)
+
+	partial, err := authorization.NewCapture(MoneyToPtr(MustParseMoney("20.00")))
+	require.NoError(t, err)
+	assert.Equal(t, MustParseMoney("-20.00"), partial.Amount)
+
+	_, err = authorization.NewCapture(MoneyToPtr(MustParseMoney("50.01")))
+	assert.ErrorIs(t, err, ErrCaptureExceedsHold)
+}
+
+func TestHeld(t *testing.T) {
+	// Given.
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	later, earlier := now.Add(time.Hour), now.Add(-time.Hour)
+	authorizations := []AuthorizationImpl{
+		{AuthorizationID: IntToPtr(1), Amount: MustParseMoney("-10.00"), Status: AuthorizationPending, ExpiresAt: &later},
+		{AuthorizationID: IntToPtr(2), Amount: MustParseMoney("-20.00"), Status: AuthorizationPending, ExpiresAt: &earlier},
+		{AuthorizationID: IntToPtr(3), Amount: MustParseMoney("-40.00"), Status: AuthorizationCaptured, ExpiresAt: &later},
+		{AuthorizationID: IntToPtr(4), Amount: MustParseMoney("-5.00"), Status: AuthorizationPending, ExpiresAt: &later},
+	}
+
+	// When.
+	held := Held(authorizations, now)
+
+	// Then.
+	assert.Equal(t, MustParseMoney("15.00"), held)
+}
+
+func TestNewInstallmentPlan(t *testing.T) {
+	now := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	purchase := NewTransaction(nil, 7, OperationTypeInstallmentPurchase, MustParseMoney("-100.00"), 0, nil)
+	tests := []struct {
+		name     string
+		rounding InstallmentRounding
+		amounts  []Money
+	}{
+		{name: "remainder on first", rounding: RemainderOnFirst, amounts: []Money{-3334, -3333, -3333}},
+		{name: "remainder on last", rounding: RemainderOnLast, amounts: []Money{-3333, -3333, -3334}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When.
+			plan, err := NewInstallmentPlan(*purchase, 3, tt.rounding, now)
+
+			// Then.
+			require.NoError(t, err)
+			assert.Equal(t, 3, plan.InstallmentCount)
+			assert.Equal(t, purchase.Amount, plan.Amount)
+			var amounts []Money
+			var dueDates []time.Time
+			for _, debit := range plan.Debits {
+				amounts = append(amounts, debit.Amount)
+				dueDates = append(dueDates, *debit.DueDate)
+				assert.Equal(t, OperationTypeInstallmentPurchase, debit.OperationTypeID)
+			}
+			assert.Equal(t, tt.amounts, amounts)
+			// Months without a 31st fall back to their last day.
+			assert.Equal(t, []time.Time{
+				now,
+				time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC),
+				time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC),
+			}, dueDates)
+		})
+	}
+
+	_, err := NewInstallmentPlan(*NewTransaction(nil, 7, 2, -2, 0, nil), 3, RemainderOnFirst, now)
+	assert.ErrorIs(t, err, ErrInvalidInstallments)
+}
+
+func TestNewInstallmentSchedule(t *testing.T) {
+	// Given.
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	plan, err := NewInstallmentPlan(*NewTransaction(nil, 7, OperationTypeInstallmentPurchase, MustParseMoney("-90.00"), 0, nil), 3, RemainderOnFirst, now.AddDate(0, -1, 0))
+	require.NoError(t, err)
+	plan.Debits[0].Balance = 0
+	plan.Debits[1].Balance = MustParseMoney("-10.00")
+	plan.Debits[2].Balance = MustParseMoney("-30.00")
+
+	// When.
+	schedule := NewInstallmentSchedule(plan, now)
+
+	// Then.
+	var statuses []InstallmentStatus
+	for _, installment := range schedule.Installments {
+		statuses = append(statuses, installment.Status)
+	}
+	assert.Equal(t, []InstallmentStatus{InstallmentPaid, InstallmentUnpaid, InstallmentScheduled}, statuses)
+	assert.Equal(t, []int{1, 2, 3}, []int{schedule.Installments[0].Number, schedule.Installments[1].Number, schedule.Installments[2].Number})
+	assert.Equal(t, MustParseMoney("50.00"), schedule.Paid)
+	assert.Equal(t, MustParseMoney("40.00"), schedule.Unpaid)
+}
+
+func TestNewTransfer(t *testing.T) {
+	// When.
+	transfer := NewTransfer(TransferRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: MustParseMoney("25.00")})
+
+	// Then.
+	assert.Equal(t, MustParseMoney("25.00"), transfer.Amount)
+	assert.Equal(t, NewTransaction(nil, 1, OperationTypeTransferOut, MustParseMoney("-25.00"), 0, nil), transfer.Debit)
+	assert.Equal(t, NewTransaction(nil, 2, OperationTypeTransferIn, MustParseMoney("25.00"), 0, nil), transfer.Credit)
+}
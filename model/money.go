@@ -0,0 +1,141 @@
+package model
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Money is an exact amount in cents. It matches the DECIMAL(18,2) columns
+// in the database, so amounts never drift the way floats do.
+type Money int64
+
+var ErrInvalidMoney = errors.New("invalid amount")
+
+// ParseMoney parses a decimal string with at most two fractional digits.
+func ParseMoney(s string) (Money, error) {
+	str := s
+	negative := false
+	if strings.HasPrefix(str, "-") {
+		negative = true
+		str = str[1:]
+	}
+
+	units, fraction, hasPoint := strings.Cut(str, ".")
+	if units == "" || !isDigits(units) || (hasPoint && !isDigits(fraction)) {
+		return 0, fmt.Errorf("%w %q", ErrInvalidMoney, s)
+	}
+	if len(fraction) > 2 {
+		return 0, fmt.Errorf("%w %q: more than two fractional digits", ErrInvalidMoney, s)
+	}
+
+	u, err := strconv.ParseInt(units, 10, 64)
+	if err != nil || u > math.MaxInt64/100-1 {
+		return 0, fmt.Errorf("%w %q: out of range", ErrInvalidMoney, s)
+	}
+	c, _ := strconv.ParseInt(fraction+strings.Repeat("0", 2-len(fraction)), 10, 64)
+
+	m := Money(u*100 + c)
+	if negative {
+		m = -m
+	}
+	return m, nil
+}
+
+// MustParseMoney is like ParseMoney but panics on error.
+// It is meant for constants and tests.
+func MustParseMoney(s string) Money {
+	m, err := ParseMoney(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// String formats the amount with exactly two fractional digits.
+func (m Money) String() string {
+	sign := ""
+	cents := int64(m)
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// MarshalJSON encodes the amount as a JSON number, e.g. 123.45.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or string with at most two
+// fractional digits.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	parsed, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Scan implements sql.Scanner for DECIMAL columns.
+func (m *Money) Scan(src any) error {
+	switch v := src.(type) {
+	case []byte:
+		return m.scanString(string(v))
+	case string:
+		return m.scanString(v)
+	case int64:
+		*m = Money(v * 100)
+		return nil
+	case float64:
+		*m = Money(math.Round(v * 100))
+		return nil
+	case nil:
+		*m = 0
+		return nil
+	default:
+		return fmt.Errorf("%w: cannot scan %T", ErrInvalidMoney, src)
+	}
+}
+
+func (m *Money) scanString(s string) error {
+	// DECIMAL(18,2) always has two digits, but be lenient about trailing
+	// zeros from other scales.
+	if units, fraction, ok := strings.Cut(s, "."); ok && len(fraction) > 2 {
+		fraction = strings.TrimRight(fraction, "0")
+		s = units
+		if fraction != "" {
+			s += "." + fraction
+		}
+	}
+	parsed, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value implements driver.Valuer. The amount is sent as a decimal string
+// so the database stores it exactly.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
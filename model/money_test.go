@@ -0,0 +1,107 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Money
+		wantErr bool
+	}{
+		{in: "0", want: 0},
+		{in: "123", want: 12300},
+		{in: "123.4", want: 12340},
+		{in: "123.45", want: 12345},
+		{in: "-0.05", want: -5},
+		{in: "-50.00", want: -5000},
+		{in: "0.001", wantErr: true},
+		{in: "1.234", wantErr: true},
+		{in: "", wantErr: true},
+		{in: "-", wantErr: true},
+		{in: ".5", wantErr: true},
+		{in: "1e2", wantErr: true},
+		{in: "+1", wantErr: true},
+		{in: "99999999999999999999", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseMoney(tt.in)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidMoney)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMoney_String(t *testing.T) {
+	assert.Equal(t, "0.00", Money(0).String())
+	assert.Equal(t, "0.05", Money(5).String())
+	assert.Equal(t, "-0.05", Money(-5).String())
+	assert.Equal(t, "-123.40", Money(-12340).String())
+}
+
+func TestMoney_JSON(t *testing.T) {
+	// Given.
+	var got struct {
+		Number Money `json:"number"`
+		String Money `json:"string"`
+	}
+
+	// When.
+	err := json.Unmarshal([]byte(`{"number": 123.45, "string": "-0.10"}`), &got)
+
+	// Then.
+	require.NoError(t, err)
+	assert.Equal(t, Money(12345), got.Number)
+	assert.Equal(t, Money(-10), got.String)
+
+	out, err := json.Marshal(got)
+	require.NoError(t, err)
+	assert.Equal(t, `{"number":123.45,"string":-0.10}`, string(out))
+}
+
+func TestMoney_JSONRejectsFractionalCents(t *testing.T) {
+	var m Money
+	err := json.Unmarshal([]byte(`0.001`), &m)
+	require.ErrorIs(t, err, ErrInvalidMoney)
+}
+
+func TestMoney_ScanValue(t *testing.T) {
+	tests := []struct {
+		src  any
+		want Money
+	}{
+		{src: []byte("-123.45"), want: -12345},
+		{src: "10.50", want: 1050},
+		{src: "10.5000", want: 1050},
+		{src: int64(7), want: 700},
+		{src: 0.1 + 0.2, want: 30},
+		{src: nil, want: 0},
+	}
+	for _, tt := range tests {
+		var got Money
+		require.NoError(t, got.Scan(tt.src))
+		assert.Equal(t, tt.want, got)
+	}
+
+	// Values round-trip through the decimal string sent to the database.
+	for _, m := range []Money{0, 1, -1, 12345, -12345, 1<<53 + 1} {
+		v, err := m.Value()
+		require.NoError(t, err)
+		var got Money
+		require.NoError(t, got.Scan([]byte(v.(string))))
+		assert.Equal(t, m, got)
+	}
+
+	var m Money
+	require.Error(t, m.Scan("1.234"))
+}
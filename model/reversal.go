@@ -0,0 +1,135 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var (
+	ErrNotReversible         = errors.New("transaction cannot be reversed")
+	ErrAlreadyReversed       = errors.New("transaction already reversed")
+	ErrReversalExceedsAmount = errors.New("reversal is more than the transaction's amount")
+)
+
+// ReversalRequest is the request to reverse a transaction.
+type ReversalRequest struct {
+	// Amount to reverse, as a positive amount. When left out, whatever
+	// earlier reversals left of the transaction is reversed.
+	Amount *Money `json:"amount,omitempty" swaggertype:"number"`
+}
+
+// NewReversal returns the transaction that reverses amount of the
+// original, or all that is left of it if amount is nil. reversed is the
+// sum of the amounts of the original's earlier reversals. A debit is
+// reversed by a refund and a credit by a payment reversal, each with the
+// opposite sign.
+func NewReversal(original *TransactionImpl, reversed Money, amount *Money) (*TransactionImpl, error) {
+	switch {
+	case original.OriginalTransactionID != nil:
+		return nil, fmt.Errorf("%w: transaction %d is itself a reversal", ErrNotReversible, *original.TransactionID)
+	case original.TransferID != nil:
+		// Reversing one side alone would leave the transfer unbalanced.
+		return nil, fmt.Errorf("%w: transaction %d is part of transfer %d", ErrNotReversible, *original.TransactionID, *original.TransferID)
+	}
+
+	// Reversals have the opposite sign, so the sum shrinks towards zero.
+	remaining := original.Amount + reversed
+	operationTypeId := OperationTypePaymentReversal
+	if original.Amount < 0 {
+		remaining = -remaining
+		operationTypeId = OperationTypeRefund
+	}
+	switch {
+	case remaining <= 0:
+		return nil, fmt.Errorf("%w: transaction %d is fully reversed", ErrAlreadyReversed, *original.TransactionID)
+	case amount == nil:
+		amount = &remaining
+	case *amount > remaining:
+		return nil, fmt.Errorf("%w: only %s of transaction %d is left to reverse", ErrReversalExceedsAmount, remaining, *original.TransactionID)
+	}
+
+	reversal := NewTransaction(nil, original.AccountID, operationTypeId, *amount, 0, nil)
+	if original.Amount > 0 {
+		reversal.Amount = -*amount
+	}
+	reversal.OriginalTransactionID = original.TransactionID
+	reversal.Currency = original.Currency
+	return reversal, nil
+}
+
+// SettleReversal sets the reversal's balance and returns the account's
+// transactions whose balances it changed and the allocations to record.
+// allocations are the original's, netted by Allocations.Net, and allocated
+// the transactions on their other side.
+//
+// The reversal first cancels what is still open on the original, then
+// releases what was allocated to it, latest first. A refund gives the
+// payments that paid the debit back what they paid, and a payment
+// reversal reopens the debts the payment settled. Like any payment or
+// debit, a payment given credit back then settles the account's other
+// debts in the policy's order, paying installments that are not due yet
+// early, soonest due first, and a reopened debt draws on other payments'
+// credit. What the original settled before allocations were recorded is
+// given back the same way by the reversal itself: a refund keeps the rest
+// as credit, and a payment reversal owes it, whatever the credit limit.
+func (p SettlementPolicy) SettleReversal(original *TransactionImpl, reversal *TransactionImpl, allocations Allocations, allocated Transactions, debts Transactions, scheduled Transactions, credits Transactions) (Transactions, Allocations, error) {
+	others := func(transactions Transactions) Transactions {
+		return slices.DeleteFunc(slices.Clone(transactions), func(t TransactionImpl) bool {
+			return *t.TransactionID == *original.TransactionID
+		})
+	}
+
+	// A refund settles debts like a payment; a payment reversal draws on
+	// credits like a debit.
+	process, open := ProcessPositivePayments, others(credits)
+	if reversal.Amount > 0 {
+		process, open = ProcessNegativePayments, slices.Concat(p.Order(others(debts)), others(scheduled))
+	}
+
+	changed, _, rest, err := process(Transactions{*original}, reversal.Amount, reversal.Currency)
+	if err != nil {
+		return nil, nil, err
+	}
+	var recorded Allocations
+	for _, allocation := range allocations {
+		if rest == 0 {
+			break
+		}
+		// Release as much of the allocation as is left to reverse, and give
+		// it back to the other side with the reversal's sign.
+		size := min(allocation.Amount, rest)
+		counterpartId, amount := allocation.CreditTransactionID, size
+		if reversal.Amount < 0 {
+			size = min(allocation.Amount, -rest)
+			counterpartId, amount = allocation.DebitTransactionID, -size
+		}
+		i := slices.IndexFunc(allocated, func(t TransactionImpl) bool {
+			return *t.TransactionID == counterpartId
+		})
+		if i < 0 {
+			return nil, nil, fmt.Errorf("transaction %d allocated to transaction %d is missing", counterpartId, *original.TransactionID)
+		}
+		counterpart := allocated[i]
+		released := allocation
+		released.Amount = -size
+
+		// Only what the other side cannot settle again stays on it.
+		settled, again, left, err := process(open, amount, reversal.Currency)
+		if err != nil {
+			return nil, nil, err
+		}
+		counterpart.Balance += left
+		open, rest = settled, rest-amount
+		changed = append(changed, counterpart)
+		recorded = append(recorded, released)
+		recorded = append(recorded, again.For(counterpartId)...)
+	}
+
+	settled, again, rest, err := process(open, rest, reversal.Currency)
+	if err != nil {
+		return nil, nil, err
+	}
+	reversal.Balance = rest
+	return append(changed, settled...), append(recorded, again...), nil
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SettlementPolicy decides in which order a payment settles an account's
+// outstanding debts before ProcessNegativePayments allocates it.
+type SettlementPolicy struct {
+	// OperationPriority lists operation type IDs to settle first, in order.
+	// Debts of unlisted types are settled last. Debts of the same priority
+	// are always settled oldest first.
+	OperationPriority []int
+}
+
+// OldestFirst settles debts strictly by EventDate, whatever their type.
+var OldestFirst = SettlementPolicy{}
+
+// ParseSettlementPolicy parses "oldest-first" or a comma separated list of
+// operation type IDs, e.g. "3,1,2" to settle withdrawals before purchases.
+func ParseSettlementPolicy(s string) (SettlementPolicy, error) {
+	if s == "" || s == "oldest-first" {
+		return OldestFirst, nil
+	}
+
+	var policy SettlementPolicy
+	for _, field := range strings.Split(s, ",") {
+		operationTypeId, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return OldestFirst, fmt.Errorf("invalid settlement order %q: %v", s, err)
+		}
+		policy.OperationPriority = append(policy.OperationPriority, operationTypeId)
+	}
+	return policy, nil
+}
+
+func (p SettlementPolicy) String() string {
+	if len(p.OperationPriority) == 0 {
+		return "oldest-first"
+	}
+	ids := make([]string, len(p.OperationPriority))
+	for i, id := range p.OperationPriority {
+		ids[i] = strconv.Itoa(id)
+	}
+	return strings.Join(ids, ",")
+}
+
+// Order sorts debts into settlement order. The debts must be passed in
+// oldest first, as returned by the store.
+func (p SettlementPolicy) Order(debts Transactions) Transactions {
+	rank := func(operationTypeId int) int {
+		for i, id := range p.OperationPriority {
+			if id == operationTypeId {
+				return i
+			}
+		}
+		return len(p.OperationPriority)
+	}
+
+	sort.SliceStable(debts, func(i, j int) bool {
+		return rank(debts[i].OperationTypeID) < rank(debts[j].OperationTypeID)
+	})
+	return debts
+}
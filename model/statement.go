@@ -0,0 +1,113 @@
+package model
+
+import (
+	"slices"
+	"time"
+)
+
+// OperationDebt is an account's unpaid debt of one operation type.
+type OperationDebt struct {
+	OperationTypeID int   `json:"operation_type_id"`
+	Outstanding     Money `json:"outstanding" swaggertype:"number"`
+}
+
+// AccountBalance summarises what an account owes and what it can still
+// spend. A debit is accepted while it fits in AvailableCredit plus
+// PaymentCredit.
+type AccountBalance struct {
+	AccountID int `json:"account_id"`
+	// Currency is the account's, which all the amounts are in.
+	Currency Currency `json:"currency,omitempty" swaggertype:"string"`
+	// Outstanding is the unpaid debt, as a positive amount.
+	Outstanding Money `json:"outstanding" swaggertype:"number"`
+	// Debts breaks Outstanding down by operation type.
+	Debts []OperationDebt `json:"debts"`
+	// PaymentCredit is what overpayments left to pay future debits.
+	PaymentCredit Money `json:"payment_credit" swaggertype:"number"`
+	// Scheduled is what installments that are not due yet will owe.
+	Scheduled Money `json:"scheduled" swaggertype:"number"`
+	// Held is what pending authorizations reserve for their captures.
+	Held        Money  `json:"held" swaggertype:"number"`
+	CreditLimit *Money `json:"credit_limit,omitempty" swaggertype:"number"`
+	// AvailableCredit is how much more the account may owe, scheduled
+	// installments and holds included. It is left out if the account has
+	// no credit limit.
+	AvailableCredit *Money `json:"available_credit,omitempty" swaggertype:"number"`
+}
+
+// NewAccountBalance builds the account's balance as of now from its
+// transactions with a non-zero balance, oldest first, and the amount held
+// by its pending authorizations.
+func NewAccountBalance(account *AccountImpl, transactions Transactions, held Money, now time.Time) *AccountBalance {
+	balance := &AccountBalance{
+		AccountID:   *account.AccountID,
+		Currency:    account.Currency,
+		Debts:       []OperationDebt{},
+		Held:        held,
+		CreditLimit: account.CreditLimit,
+	}
+
+	var debts, scheduled Transactions
+	for _, transaction := range transactions {
+		switch {
+		case transaction.Balance < 0 && !transaction.IsDue(now):
+			scheduled = append(scheduled, transaction)
+		case transaction.Balance < 0:
+			debts = append(debts, transaction)
+			i := slices.IndexFunc(balance.Debts, func(debt OperationDebt) bool {
+				return debt.OperationTypeID == transaction.OperationTypeID
+			})
+			if i < 0 {
+				i = len(balance.Debts)
+				balance.Debts = append(balance.Debts, OperationDebt{OperationTypeID: transaction.OperationTypeID})
+			}
+			balance.Debts[i].Outstanding -= transaction.Balance
+		case transaction.Balance > 0:
+			balance.PaymentCredit += transaction.Balance
+		}
+	}
+	slices.SortFunc(balance.Debts, func(a, b OperationDebt) int {
+		return a.OperationTypeID - b.OperationTypeID
+	})
+
+	balance.Outstanding = Outstanding(debts)
+	balance.Scheduled = Outstanding(scheduled)
+	balance.AvailableCredit = account.AvailableCredit(slices.Concat(debts, scheduled), held)
+	return balance
+}
+
+// Statement lists an account's transactions in a period. The balances are
+// the sum of the transaction amounts, so they are negative while the
+// account owes money.
+type Statement struct {
+	AccountID int `json:"account_id"`
+	// From and To bound the period like TransactionFilter. Either may be
+	// left out to leave the period open on that side.
+	From *time.Time `json:"from,omitempty"`
+	To   *time.Time `json:"to,omitempty"`
+	// OpeningBalance is the balance before From.
+	OpeningBalance Money        `json:"opening_balance" swaggertype:"number"`
+	Transactions   Transactions `json:"transactions"`
+	// ClosingBalance is OpeningBalance plus the period's transactions.
+	ClosingBalance Money `json:"closing_balance" swaggertype:"number"`
+}
+
+// NewStatement builds a statement from the balance before the period and
+// the transactions in it.
+func NewStatement(accountId int, from *time.Time, to *time.Time, opening Money, transactions Transactions) *Statement {
+	statement := &Statement{
+		AccountID:      accountId,
+		From:           from,
+		To:             to,
+		OpeningBalance: opening,
+		Transactions:   transactions,
+		ClosingBalance: opening,
+	}
+	if statement.Transactions == nil {
+		statement.Transactions = Transactions{}
+	}
+	for _, transaction := range transactions {
+		statement.ClosingBalance += transaction.Amount
+	}
+	return statement
+}
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+var (
+	ErrAccountBlocked          = errors.New("account is blocked")
+	ErrAccountClosed           = errors.New("account is closed")
+	ErrInvalidStatusTransition = errors.New("invalid account status transition")
+	ErrBalanceNotZero          = errors.New("account balance is not zero")
+	ErrAuthorizationsPending   = errors.New("account has pending authorizations")
+)
+
+// AccountStatus is where an account is in its life cycle. Accounts are
+// opened active; closed is final.
+type AccountStatus string
+
+const (
+	// AccountActive accounts accept every transaction.
+	AccountActive AccountStatus = "ACTIVE"
+	// AccountBlocked accounts accept credits, e.g. payments, but no debits.
+	AccountBlocked AccountStatus = "BLOCKED"
+	// AccountClosed accounts accept nothing.
+	AccountClosed AccountStatus = "CLOSED"
+)
+
+// accountTransitions lists the statuses an account in each status can be
+// moved to.
+var accountTransitions = map[AccountStatus][]AccountStatus{
+	AccountActive:  {AccountBlocked, AccountClosed},
+	AccountBlocked: {AccountActive, AccountClosed},
+}
+
+// AccountStatusChange records a change of an account's status.
+type AccountStatusChange struct {
+	ChangeID  *int          `json:"change_id" db:"Change_ID"`
+	AccountID int           `json:"account_id" db:"Account_ID"`
+	OldStatus AccountStatus `json:"old_status" db:"Old_Status" swaggertype:"string"`
+	NewStatus AccountStatus `json:"new_status" db:"New_Status" swaggertype:"string"`
+	// Reason says why the status was changed, e.g. a fraud report.
+	Reason    string     `json:"reason" db:"Reason"`
+	ChangedAt *time.Time `json:"changed_at,omitempty" db:"Changed_At"`
+}
+
+// AccountStatusHistory lists an account's status changes.
+type AccountStatusHistory struct {
+	Changes []AccountStatusChange `json:"changes"`
+}
+
+// AccountStatusUpdate is the request to block, unblock or close an account.
+type AccountStatusUpdate struct {
+	Reason string `json:"reason"`
+}
+
+// CheckTransition returns ErrInvalidStatusTransition unless the account
+// can be moved to the status, and ErrBalanceNotZero if it is to be closed
+// with a balance, the sum of its transactions' amounts, other than zero.
+// Closing an account that still holds credit for pending authorizations
+// returns ErrAuthorizationsPending rather than voiding them: they could
+// not be captured once it is closed, so they must be captured or voided,
+// or left to expire, first.
+func (a *AccountImpl) CheckTransition(status AccountStatus, balance Money, held Money) error {
+	if !slices.Contains(accountTransitions[a.Status], status) {
+		return fmt.Errorf("%w: account %d is %s and cannot become %s", ErrInvalidStatusTransition, *a.AccountID, a.Status, status)
+	}
+	if status == AccountClosed && balance != 0 {
+		return fmt.Errorf("%w: account %d has a balance of %s", ErrBalanceNotZero, *a.AccountID, balance)
+	}
+	if status == AccountClosed && held != 0 {
+		return fmt.Errorf("%w: account %d holds %s for pending authorizations", ErrAuthorizationsPending, *a.AccountID, held)
+	}
+	return nil
+}
+
+// CheckPosting returns ErrAccountClosed if the account is closed and
+// ErrAccountBlocked if it is blocked and amount, stored with its
+// operation's sign, is a debit.
+func (a *AccountImpl) CheckPosting(amount Money) error {
+	switch {
+	case a.Status == AccountClosed:
+		return fmt.Errorf("%w: account %d accepts no transactions", ErrAccountClosed, *a.AccountID)
+	case a.Status == AccountBlocked && amount < 0:
+		return fmt.Errorf("%w: account %d accepts only credits", ErrAccountBlocked, *a.AccountID)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountImpl_CheckTransition(t *testing.T) {
+	tests := []struct {
+		from    AccountStatus
+		to      AccountStatus
+		balance Money
+		held    Money
+		wantErr error
+	}{
+		{from: AccountActive, to: AccountBlocked},
+		{from: AccountActive, to: AccountClosed},
+		{from: AccountBlocked, to: AccountActive},
+		{from: AccountBlocked, to: AccountClosed},
+		{from: AccountActive, to: AccountActive, wantErr: ErrInvalidStatusTransition},
+		{from: AccountBlocked, to: AccountBlocked, wantErr: ErrInvalidStatusTransition},
+		{from: AccountClosed, to: AccountActive, wantErr: ErrInvalidStatusTransition},
+		{from: AccountClosed, to: AccountBlocked, wantErr: ErrInvalidStatusTransition},
+		{from: AccountActive, to: AccountClosed, balance: MustParseMoney("-0.01"), wantErr: ErrBalanceNotZero},
+		{from: AccountBlocked, to: AccountClosed, balance: MustParseMoney("5.00"), wantErr: ErrBalanceNotZero},
+		{from: AccountActive, to: AccountBlocked, balance: MustParseMoney("-5.00")},
+		{from: AccountActive, to: AccountClosed, held: MustParseMoney("5.00"), wantErr: ErrAuthorizationsPending},
+		{from: AccountBlocked, to: AccountClosed, held: MustParseMoney("5.00"), wantErr: ErrAuthorizationsPending},
+		{from: AccountActive, to: AccountBlocked, held: MustParseMoney("5.00")},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			// Given.
+			account := NewAccount(IntToPtr(1), "12345678900", DefaultCurrency)
+			account.Status = tt.from
+
+			// When.
+			err := account.CheckTransition(tt.to, tt.balance, tt.held)
+
+			// Then.
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestAccountImpl_CheckPosting(t *testing.T) {
+	account := NewAccount(IntToPtr(1), "12345678900", DefaultCurrency)
+	assert.Equal(t, AccountActive, account.Status)
+	assert.NoError(t, account.CheckPosting(MustParseMoney("-10.00")))
+	assert.NoError(t, account.CheckPosting(MustParseMoney("10.00")))
+
+	account.Status = AccountBlocked
+	assert.ErrorIs(t, account.CheckPosting(MustParseMoney("-10.00")), ErrAccountBlocked)
+	assert.NoError(t, account.CheckPosting(MustParseMoney("10.00")))
+
+	account.Status = AccountClosed
+	assert.ErrorIs(t, account.CheckPosting(MustParseMoney("-10.00")), ErrAccountClosed)
+	assert.ErrorIs(t, account.CheckPosting(MustParseMoney("10.00")), ErrAccountClosed)
+}
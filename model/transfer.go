@@ -0,0 +1,44 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrSameAccount = errors.New("cannot transfer to the same account")
+
+// TransferRequest is the request to move funds between two accounts.
+type TransferRequest struct {
+	SourceAccountID      int `json:"source_account_id"`
+	DestinationAccountID int `json:"destination_account_id"`
+	// Amount to move, as a positive amount.
+	Amount Money `json:"amount" swaggertype:"number"`
+}
+
+// Transfer moves funds from one account to another. It is posted as a
+// debit on the source account and a credit on the destination, which
+// both reference it.
+type Transfer struct {
+	TransferID           *int `json:"transfer_id" db:"Transfer_ID"`
+	SourceAccountID      int  `json:"source_account_id" db:"Source_Account_ID"`
+	DestinationAccountID int  `json:"destination_account_id" db:"Destination_Account_ID"`
+	// Amount is the amount moved, as a positive amount.
+	Amount    Money      `json:"amount" db:"Amount" swaggertype:"number"`
+	CreatedAt *time.Time `json:"created_at,omitempty" db:"Created_At"`
+	// Debit and Credit are the transactions posted on the source and the
+	// destination account.
+	Debit  *TransactionImpl `json:"debit,omitempty" db:"-"`
+	Credit *TransactionImpl `json:"credit,omitempty" db:"-"`
+}
+
+// NewTransfer returns the transfer the request asks for, with its debit
+// and credit yet to be posted.
+func NewTransfer(request TransferRequest) *Transfer {
+	return &Transfer{
+		SourceAccountID:      request.SourceAccountID,
+		DestinationAccountID: request.DestinationAccountID,
+		Amount:               request.Amount,
+		Debit:                NewTransaction(nil, request.SourceAccountID, OperationTypeTransferOut, -request.Amount, 0, nil),
+		Credit:               NewTransaction(nil, request.DestinationAccountID, OperationTypeTransferIn, request.Amount, 0, nil),
+	}
+}
@@ -3,3 +3,7 @@ package model
 func IntToPtr(v int) *int {
 	return &v
 }
+
+func MoneyToPtr(v Money) *Money {
+	return &v
+}
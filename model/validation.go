@@ -0,0 +1,381 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// MaxDocumentNumberLength matches the BIGINT(16) Document_Number column.
+	MaxDocumentNumberLength = 16
+	// MaxReasonLength matches the VARCHAR(255) Reason columns.
+	MaxReasonLength = 255
+)
+
+// MaxAmount is the largest amount, in either direction, of one transaction.
+var MaxAmount = MustParseMoney("1000000.00")
+
+// FieldError is a problem with one field of a request.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// ValidationError lists every invalid field of a request.
+type ValidationError []FieldError
+
+func (e ValidationError) Error() string {
+	msgs := make([]string, len(e))
+	for i, f := range e {
+		msgs[i] = fmt.Sprintf("%s %s", f.Field, f.Message)
+	}
+	return "invalid request: " + strings.Join(msgs, ", ")
+}
+
+// fieldRule checks one field of T and returns a message if it is invalid.
+type fieldRule[T any] struct {
+	field string
+	check func(T) string
+}
+
+// validate runs every rule and collects the failures.
+func validate[T any](v T, rules []fieldRule[T]) error {
+	var errs ValidationError
+	for _, rule := range rules {
+		if msg := rule.check(v); msg != "" {
+			errs = append(errs, FieldError{Field: rule.field, Message: msg})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// accountRules validate a request to create an account.
+var accountRules = []fieldRule[*AccountImpl]{
+	{"account_id", func(a *AccountImpl) string {
+		return unset(a.AccountID != nil)
+	}},
+	{"credit_limit", func(a *AccountImpl) string {
+		if a.CreditLimit != nil {
+			return "is set through the credit limit endpoint and must be left out"
+		}
+		return ""
+	}},
+	{"document_number", func(a *AccountImpl) string {
+		switch n := a.DocumentNumber; {
+		case n == "":
+			return "is required"
+		case !isDigits(n):
+			return "must only contain digits"
+		case len(n) > MaxDocumentNumberLength:
+			return fmt.Sprintf("must be at most %d digits", MaxDocumentNumberLength)
+		case n[0] == '0':
+			return "must not start with 0"
+		}
+		return ""
+	}},
+	{"status", func(a *AccountImpl) string {
+		if a.Status != "" {
+			return "is set through the status endpoints and must be left out"
+		}
+		return ""
+	}},
+	{"currency", func(a *AccountImpl) string {
+		return optionalCurrency(a.Currency)
+	}},
+}
+
+// transactionRules validate a request to create a transaction.
+var transactionRules = []fieldRule[*TransactionImpl]{
+	{"transaction_id", func(t *TransactionImpl) string {
+		return unset(t.TransactionID != nil)
+	}},
+	{"account_id", func(t *TransactionImpl) string {
+		return positive(t.AccountID)
+	}},
+	{"operation_type_id", func(t *TransactionImpl) string {
+		operation := OperationImpl{OperationTypeID: t.OperationTypeID}
+		switch {
+		case operation.IsReversal():
+			return "is a reversal, which must be made through the reversal endpoint"
+		case operation.IsTransfer():
+			return "is a transfer, which must be made through the transfers endpoint"
+		}
+		return positive(t.OperationTypeID)
+	}},
+	{"amount", func(t *TransactionImpl) string {
+		switch {
+		case t.Amount == 0:
+			return "must not be zero"
+		case t.Amount > MaxAmount || t.Amount < -MaxAmount:
+			return fmt.Sprintf("must be at most %s", MaxAmount)
+		}
+		return ""
+	}},
+	{"balance", func(t *TransactionImpl) string {
+		return unset(t.Balance != 0)
+	}},
+	{"event_date", func(t *TransactionImpl) string {
+		return unset(t.EventDate != nil)
+	}},
+	{"original_transaction_id", func(t *TransactionImpl) string {
+		return unset(t.OriginalTransactionID != nil)
+	}},
+	{"transfer_id", func(t *TransactionImpl) string {
+		return unset(t.TransferID != nil)
+	}},
+	{"plan_id", func(t *TransactionImpl) string {
+		return unset(t.PlanID != nil)
+	}},
+	{"due_date", func(t *TransactionImpl) string {
+		return unset(t.DueDate != nil)
+	}},
+	{"currency", func(t *TransactionImpl) string {
+		return optionalCurrency(t.Currency)
+	}},
+	{"original_amount", func(t *TransactionImpl) string {
+		return unset(t.OriginalAmount != nil)
+	}},
+	{"original_currency", func(t *TransactionImpl) string {
+		return unset(t.OriginalCurrency != nil)
+	}},
+	{"fx_rate", func(t *TransactionImpl) string {
+		return unset(t.FXRate != nil)
+	}},
+	{"installments", func(t *TransactionImpl) string {
+		switch n := t.Installments; {
+		case n == nil:
+		case t.OperationTypeID != OperationTypeInstallmentPurchase:
+			return "is only accepted for installment purchases"
+		case *n < 1 || *n > MaxInstallments:
+			return fmt.Sprintf("must be between 1 and %d", MaxInstallments)
+		case t.Amount != 0 && max(t.Amount, -t.Amount) < Money(*n):
+			return "must not split the amount into installments of less than 0.01"
+		}
+		return ""
+	}},
+}
+
+// reversalRules validate a request to reverse a transaction.
+var reversalRules = []fieldRule[*ReversalRequest]{
+	{"amount", func(r *ReversalRequest) string {
+		return optionalAmount(r.Amount)
+	}},
+}
+
+// authorizationRules validate a request to authorize a debit.
+var authorizationRules = []fieldRule[*AuthorizationRequest]{
+	{"account_id", func(a *AuthorizationRequest) string {
+		return positive(a.AccountID)
+	}},
+	{"operation_type_id", func(a *AuthorizationRequest) string {
+		operation := OperationImpl{OperationTypeID: a.OperationTypeID}
+		switch {
+		case operation.IsReversal():
+			return "is a reversal, which cannot be authorized"
+		case operation.IsTransfer():
+			return "is a transfer, which cannot be authorized"
+		}
+		return positive(a.OperationTypeID)
+	}},
+	{"amount", func(a *AuthorizationRequest) string {
+		switch {
+		case a.Amount == 0:
+			return "must not be zero"
+		case a.Amount > MaxAmount || a.Amount < -MaxAmount:
+			return fmt.Sprintf("must be at most %s", MaxAmount)
+		}
+		return ""
+	}},
+}
+
+// captureRules validate a request to capture an authorization.
+var captureRules = []fieldRule[*CaptureRequest]{
+	{"amount", func(c *CaptureRequest) string {
+		return optionalAmount(c.Amount)
+	}},
+}
+
+// transferRules validate a request to move funds between accounts.
+var transferRules = []fieldRule[*TransferRequest]{
+	{"source_account_id", func(t *TransferRequest) string {
+		return positive(t.SourceAccountID)
+	}},
+	{"destination_account_id", func(t *TransferRequest) string {
+		if t.DestinationAccountID > 0 && t.DestinationAccountID == t.SourceAccountID {
+			return "must differ from source_account_id"
+		}
+		return positive(t.DestinationAccountID)
+	}},
+	{"amount", func(t *TransferRequest) string {
+		if t.Amount == 0 {
+			return "is required"
+		}
+		return optionalAmount(&t.Amount)
+	}},
+}
+
+// fxRateRules validate one rate of a request to load exchange rates.
+var fxRateRules = []fieldRule[*FXRate]{
+	{"base_currency", func(r *FXRate) string {
+		if r.Base == "" {
+			return "is required"
+		}
+		return optionalCurrency(r.Base)
+	}},
+	{"quote_currency", func(r *FXRate) string {
+		switch {
+		case r.Quote == "":
+			return "is required"
+		case r.Quote == r.Base:
+			return "must differ from base_currency"
+		}
+		return optionalCurrency(r.Quote)
+	}},
+	{"rate", func(r *FXRate) string {
+		switch {
+		case r.Rate <= 0:
+			return "must be positive"
+		case r.Rate > MaxRate:
+			return fmt.Sprintf("must be at most %s", MaxRate)
+		}
+		return ""
+	}},
+	{"updated_at", func(r *FXRate) string {
+		return unset(r.UpdatedAt != nil)
+	}},
+}
+
+// creditLimitRules validate a request to change a credit limit.
+var creditLimitRules = []fieldRule[*CreditLimitUpdate]{
+	{"credit_limit", func(u *CreditLimitUpdate) string {
+		switch {
+		case u.CreditLimit == nil:
+			return "is required"
+		case *u.CreditLimit < 0:
+			return "must not be negative"
+		case *u.CreditLimit > MaxCreditLimit:
+			return fmt.Sprintf("must be at most %s", MaxCreditLimit)
+		}
+		return ""
+	}},
+	{"reason", func(u *CreditLimitUpdate) string {
+		return reason(u.Reason)
+	}},
+}
+
+// accountStatusRules validate a request to change an account's status.
+var accountStatusRules = []fieldRule[*AccountStatusUpdate]{
+	{"reason", func(u *AccountStatusUpdate) string {
+		return reason(u.Reason)
+	}},
+}
+
+// Validate checks the account as a request to create it.
+func (a *AccountImpl) Validate() error {
+	return validate(a, accountRules)
+}
+
+// Validate checks the request to change a credit limit.
+func (u *CreditLimitUpdate) Validate() error {
+	return validate(u, creditLimitRules)
+}
+
+// Validate checks the request to change an account's status.
+func (u *AccountStatusUpdate) Validate() error {
+	return validate(u, accountStatusRules)
+}
+
+// Validate checks the request to reverse a transaction.
+func (r *ReversalRequest) Validate() error {
+	return validate(r, reversalRules)
+}
+
+// Validate checks the request to authorize a debit.
+func (a *AuthorizationRequest) Validate() error {
+	return validate(a, authorizationRules)
+}
+
+// Validate checks the request to capture an authorization.
+func (c *CaptureRequest) Validate() error {
+	return validate(c, captureRules)
+}
+
+// Validate checks the request to move funds between accounts.
+func (t *TransferRequest) Validate() error {
+	return validate(t, transferRules)
+}
+
+// Validate checks the request to load exchange rates. Fields are reported
+// by their index in the list, e.g. rates[0].rate.
+func (l *FXRateList) Validate() error {
+	if len(l.Rates) == 0 {
+		return ValidationError{{Field: "rates", Message: "is required"}}
+	}
+	var errs ValidationError
+	for i := range l.Rates {
+		if err := validate(&l.Rates[i], fxRateRules); err != nil {
+			for _, f := range err.(ValidationError) {
+				errs = append(errs, FieldError{Field: fmt.Sprintf("rates[%d].%s", i, f.Field), Message: f.Message})
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Validate checks the transaction as a request to create it. Fields set by
+// the server must be left out.
+func (t *TransactionImpl) Validate() error {
+	return validate(t, transactionRules)
+}
+
+func unset(set bool) string {
+	if set {
+		return "is set by the server and must be left out"
+	}
+	return ""
+}
+
+func positive(id int) string {
+	if id <= 0 {
+		return "is required"
+	}
+	return ""
+}
+
+// reason checks the reason given for an admin change.
+func reason(reason string) string {
+	switch {
+	case strings.TrimSpace(reason) == "":
+		return "is required"
+	case len(reason) > MaxReasonLength:
+		return fmt.Sprintf("must be at most %d characters", MaxReasonLength)
+	}
+	return ""
+}
+
+// optionalCurrency checks a currency that may be left out.
+func optionalCurrency(currency Currency) string {
+	if currency != "" && !currency.Valid() {
+		return "must be an ISO 4217 code of three upper case letters"
+	}
+	return ""
+}
+
+// optionalAmount checks an amount that may be left out but must otherwise
+// be positive.
+func optionalAmount(amount *Money) string {
+	switch {
+	case amount == nil:
+	case *amount <= 0:
+		return "must be positive"
+	case *amount > MaxAmount:
+		return fmt.Sprintf("must be at most %s", MaxAmount)
+	}
+	return ""
+}
@@ -0,0 +1,212 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountImpl_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		account AccountImpl
+		fields  []string
+	}{
+		{name: "valid", account: AccountImpl{DocumentNumber: "12345678900"}},
+		{name: "empty", account: AccountImpl{}, fields: []string{"document_number"}},
+		{name: "letters", account: AccountImpl{DocumentNumber: "12a"}, fields: []string{"document_number"}},
+		{name: "too long", account: AccountImpl{DocumentNumber: strings.Repeat("1", MaxDocumentNumberLength+1)}, fields: []string{"document_number"}},
+		{name: "leading zero", account: AccountImpl{DocumentNumber: "0123"}, fields: []string{"document_number"}},
+		{name: "account id set", account: AccountImpl{AccountID: IntToPtr(1), DocumentNumber: "1"}, fields: []string{"account_id"}},
+		{name: "credit limit set", account: AccountImpl{DocumentNumber: "1", CreditLimit: MoneyToPtr(100)}, fields: []string{"credit_limit"}},
+		{name: "status set", account: AccountImpl{DocumentNumber: "1", Status: AccountBlocked}, fields: []string{"status"}},
+		{name: "currency", account: AccountImpl{DocumentNumber: "1", Currency: "EUR"}},
+		{name: "lower case currency", account: AccountImpl{DocumentNumber: "1", Currency: "eur"}, fields: []string{"currency"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertInvalidFields(t, tt.account.Validate(), tt.fields)
+		})
+	}
+}
+
+func TestTransactionImpl_Validate(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name        string
+		transaction TransactionImpl
+		fields      []string
+	}{
+		{name: "valid", transaction: TransactionImpl{AccountID: 1, OperationTypeID: 1, Amount: 100}},
+		{name: "empty", transaction: TransactionImpl{}, fields: []string{"account_id", "operation_type_id", "amount"}},
+		{name: "too large", transaction: TransactionImpl{AccountID: 1, OperationTypeID: 1, Amount: MaxAmount + 1}, fields: []string{"amount"}},
+		{name: "too large negative", transaction: TransactionImpl{AccountID: 1, OperationTypeID: 1, Amount: -MaxAmount - 1}, fields: []string{"amount"}},
+		{name: "server fields", transaction: TransactionImpl{TransactionID: IntToPtr(1), AccountID: 1, OperationTypeID: 1, Amount: 100, Balance: 100, EventDate: &now, OriginalTransactionID: IntToPtr(1)}, fields: []string{"transaction_id", "balance", "event_date", "original_transaction_id"}},
+		{name: "refund", transaction: TransactionImpl{AccountID: 1, OperationTypeID: OperationTypeRefund, Amount: 100}, fields: []string{"operation_type_id"}},
+		{name: "payment reversal", transaction: TransactionImpl{AccountID: 1, OperationTypeID: OperationTypePaymentReversal, Amount: 100}, fields: []string{"operation_type_id"}},
+		{name: "transfer", transaction: TransactionImpl{AccountID: 1, OperationTypeID: OperationTypeTransferIn, Amount: 100, TransferID: IntToPtr(1)}, fields: []string{"operation_type_id", "transfer_id"}},
+		{name: "installments", transaction: TransactionImpl{AccountID: 1, OperationTypeID: OperationTypeInstallmentPurchase, Amount: 100, Installments: IntToPtr(3)}},
+		{name: "installments on a purchase", transaction: TransactionImpl{AccountID: 1, OperationTypeID: OperationTypePurchase, Amount: 100, Installments: IntToPtr(3)}, fields: []string{"installments"}},
+		{name: "too many installments", transaction: TransactionImpl{AccountID: 1, OperationTypeID: OperationTypeInstallmentPurchase, Amount: 10000, Installments: IntToPtr(MaxInstallments + 1)}, fields: []string{"installments"}},
+		{name: "installments below a cent", transaction: TransactionImpl{AccountID: 1, OperationTypeID: OperationTypeInstallmentPurchase, Amount: 2, Installments: IntToPtr(3)}, fields: []string{"installments"}},
+		{name: "installment fields", transaction: TransactionImpl{AccountID: 1, OperationTypeID: 1, Amount: 100, PlanID: IntToPtr(1), DueDate: &now}, fields: []string{"plan_id", "due_date"}},
+		{name: "currency", transaction: TransactionImpl{AccountID: 1, OperationTypeID: 1, Amount: 100, Currency: "EUR"}},
+		{name: "invalid currency", transaction: TransactionImpl{AccountID: 1, OperationTypeID: 1, Amount: 100, Currency: "EURO"}, fields: []string{"currency"}},
+		{name: "conversion fields", transaction: TransactionImpl{AccountID: 1, OperationTypeID: 1, Amount: 100, OriginalAmount: MoneyToPtr(100), OriginalCurrency: &[]Currency{"EUR"}[0], FXRate: &[]Rate{MustParseRate("1.1")}[0]}, fields: []string{"original_amount", "original_currency", "fx_rate"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertInvalidFields(t, tt.transaction.Validate(), tt.fields)
+		})
+	}
+}
+
+func TestCreditLimitUpdate_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		update CreditLimitUpdate
+		fields []string
+	}{
+		{name: "valid", update: CreditLimitUpdate{CreditLimit: MoneyToPtr(MustParseMoney("500.00")), Reason: "credit review"}},
+		{name: "zero", update: CreditLimitUpdate{CreditLimit: MoneyToPtr(0), Reason: "frozen"}},
+		{name: "empty", update: CreditLimitUpdate{Reason: " "}, fields: []string{"credit_limit", "reason"}},
+		{name: "negative", update: CreditLimitUpdate{CreditLimit: MoneyToPtr(-1), Reason: "r"}, fields: []string{"credit_limit"}},
+		{name: "too large", update: CreditLimitUpdate{CreditLimit: MoneyToPtr(MaxCreditLimit + 1), Reason: "r"}, fields: []string{"credit_limit"}},
+		{name: "long reason", update: CreditLimitUpdate{CreditLimit: MoneyToPtr(1), Reason: strings.Repeat("r", MaxReasonLength+1)}, fields: []string{"reason"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertInvalidFields(t, tt.update.Validate(), tt.fields)
+		})
+	}
+}
+
+func TestAccountStatusUpdate_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		update AccountStatusUpdate
+		fields []string
+	}{
+		{name: "valid", update: AccountStatusUpdate{Reason: "fraud report"}},
+		{name: "empty", update: AccountStatusUpdate{Reason: " "}, fields: []string{"reason"}},
+		{name: "long reason", update: AccountStatusUpdate{Reason: strings.Repeat("r", MaxReasonLength+1)}, fields: []string{"reason"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertInvalidFields(t, tt.update.Validate(), tt.fields)
+		})
+	}
+}
+
+func TestReversalRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		reversal ReversalRequest
+		fields   []string
+	}{
+		{name: "full", reversal: ReversalRequest{}},
+		{name: "partial", reversal: ReversalRequest{Amount: MoneyToPtr(MustParseMoney("0.01"))}},
+		{name: "zero", reversal: ReversalRequest{Amount: MoneyToPtr(0)}, fields: []string{"amount"}},
+		{name: "negative", reversal: ReversalRequest{Amount: MoneyToPtr(-100)}, fields: []string{"amount"}},
+		{name: "too large", reversal: ReversalRequest{Amount: MoneyToPtr(MaxAmount + 1)}, fields: []string{"amount"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertInvalidFields(t, tt.reversal.Validate(), tt.fields)
+		})
+	}
+}
+
+func TestAuthorizationRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization AuthorizationRequest
+		fields        []string
+	}{
+		{name: "valid", authorization: AuthorizationRequest{AccountID: 1, OperationTypeID: OperationTypePurchase, Amount: MustParseMoney("50.00")}},
+		{name: "missing", authorization: AuthorizationRequest{}, fields: []string{"account_id", "operation_type_id", "amount"}},
+		{name: "reversal", authorization: AuthorizationRequest{AccountID: 1, OperationTypeID: OperationTypePaymentReversal, Amount: 100}, fields: []string{"operation_type_id"}},
+		{name: "too large", authorization: AuthorizationRequest{AccountID: 1, OperationTypeID: OperationTypePurchase, Amount: MaxAmount + 1}, fields: []string{"amount"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertInvalidFields(t, tt.authorization.Validate(), tt.fields)
+		})
+	}
+}
+
+func TestTransferRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		transfer TransferRequest
+		fields   []string
+	}{
+		{name: "valid", transfer: TransferRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: MustParseMoney("50.00")}},
+		{name: "missing", transfer: TransferRequest{}, fields: []string{"source_account_id", "destination_account_id", "amount"}},
+		{name: "same account", transfer: TransferRequest{SourceAccountID: 1, DestinationAccountID: 1, Amount: 100}, fields: []string{"destination_account_id"}},
+		{name: "negative", transfer: TransferRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: -100}, fields: []string{"amount"}},
+		{name: "too large", transfer: TransferRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: MaxAmount + 1}, fields: []string{"amount"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertInvalidFields(t, tt.transfer.Validate(), tt.fields)
+		})
+	}
+}
+
+func TestFXRateList_Validate(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name   string
+		list   FXRateList
+		fields []string
+	}{
+		{name: "valid", list: FXRateList{Rates: []FXRate{{Base: "EUR", Quote: "USD", Rate: MustParseRate("1.0825")}, {Base: "USD", Quote: "EUR", Rate: MustParseRate("0.92")}}}},
+		{name: "empty", list: FXRateList{}, fields: []string{"rates"}},
+		{name: "missing", list: FXRateList{Rates: []FXRate{{}}}, fields: []string{"rates[0].base_currency", "rates[0].quote_currency", "rates[0].rate"}},
+		{name: "same currency", list: FXRateList{Rates: []FXRate{{Base: "EUR", Quote: "EUR", Rate: 1}}}, fields: []string{"rates[0].quote_currency"}},
+		{name: "invalid currency", list: FXRateList{Rates: []FXRate{{Base: "EUR", Quote: "USD", Rate: 1}, {Base: "eur", Quote: "US", Rate: 1}}}, fields: []string{"rates[1].base_currency", "rates[1].quote_currency"}},
+		{name: "too large", list: FXRateList{Rates: []FXRate{{Base: "EUR", Quote: "USD", Rate: MaxRate + 1}}}, fields: []string{"rates[0].rate"}},
+		{name: "updated at set", list: FXRateList{Rates: []FXRate{{Base: "EUR", Quote: "USD", Rate: 1, UpdatedAt: &now}}}, fields: []string{"rates[0].updated_at"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertInvalidFields(t, tt.list.Validate(), tt.fields)
+		})
+	}
+}
+
+func TestCaptureRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		capture CaptureRequest
+		fields  []string
+	}{
+		{name: "full", capture: CaptureRequest{}},
+		{name: "partial", capture: CaptureRequest{Amount: MoneyToPtr(MustParseMoney("0.01"))}},
+		{name: "negative", capture: CaptureRequest{Amount: MoneyToPtr(-100)}, fields: []string{"amount"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertInvalidFields(t, tt.capture.Validate(), tt.fields)
+		})
+	}
+}
+
+func assertInvalidFields(t *testing.T, err error, fields []string) {
+	t.Helper()
+	if len(fields) == 0 {
+		require.NoError(t, err)
+		return
+	}
+	var validationErr ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	var got []string
+	for _, f := range validationErr {
+		got = append(got, f.Field)
+	}
+	assert.Equal(t, fields, got)
+}
@@ -0,0 +1,27 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// maxBodyBytes bounds the size of request bodies.
+const maxBodyBytes = 1 << 20
+
+var errTrailingData = errors.New("unexpected data after the JSON object")
+
+// decodeJSON strictly decodes the request body into v. Unknown fields and
+// anything after the JSON value are rejected.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if err := dec.Decode(&struct{}{}); err != io.EOF {
+		return errTrailingData
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package server
+
+import (
+	"account-transactions/model"
+	"account-transactions/store"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Error codes returned in ErrorResponse.Code. Clients can branch on these.
+const (
+	CodeBadRequest               = "bad_request"
+	CodeInvalidJSON              = "invalid_json"
+	CodeAccountNotFound          = "account_not_found"
+	CodeOperationNotFound        = "operation_not_found"
+	CodeTransactionNotFound      = "transaction_not_found"
+	CodeAuthorizationNotFound    = "authorization_not_found"
+	CodeInstallmentPlanNotFound  = "installment_plan_not_found"
+	CodeTransferNotFound         = "transfer_not_found"
+	CodeIdempotencyKeyReused     = "idempotency_key_reused"
+	CodeIdempotencyKeyInProgress = "idempotency_key_in_progress"
+	CodeInvalidAmount            = "invalid_amount"
+	CodeCreditLimitExceeded      = "credit_limit_exceeded"
+	CodeNotReversible            = "not_reversible"
+	CodeAlreadyReversed          = "already_reversed"
+	CodeNotDebit                 = "not_debit"
+	CodeAuthorizationClosed      = "authorization_closed"
+	CodeSameAccount              = "same_account"
+	CodeCurrencyMismatch         = "currency_mismatch"
+	CodeFXRateNotFound           = "fx_rate_not_found"
+	CodeAccountBlocked           = "account_blocked"
+	CodeAccountClosed            = "account_closed"
+	CodeInvalidStatusTransition  = "invalid_status_transition"
+	CodeBalanceNotZero           = "balance_not_zero"
+	CodeAuthorizationsPending    = "authorizations_pending"
+	CodeValidationFailed         = "validation_failed"
+	CodeTimeout                  = "timeout"
+	CodeRequestCancelled         = "request_cancelled"
+	CodeInternal                 = "internal_error"
+)
+
+const problemContentType = "application/problem+json"
+
+// StatusClientClosedRequest is reported, mostly for logs, when the client
+// goes away before the response is written.
+const StatusClientClosedRequest = 499
+
+// ErrorResponse is the body of every error response.
+//
+// Clients that accept application/problem+json get an RFC 7807 problem
+// document instead, which carries the same fields as extension members.
+type ErrorResponse struct {
+	// RFC 7807 members, only set for application/problem+json.
+	Type   string `json:"type,omitempty"`
+	Title  string `json:"title,omitempty"`
+	Status int    `json:"status,omitempty"`
+	Detail string `json:"detail,omitempty"`
+
+	Code      string        `json:"code"`
+	Message   string        `json:"message"`
+	Details   []ErrorDetail `json:"details,omitempty"`
+	RequestID string        `json:"request_id,omitempty"`
+}
+
+// ErrorDetail describes one problem with the request, e.g. an invalid field.
+type ErrorDetail struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// errorStatuses maps sentinel errors to the status and code they are
+// reported with.
+var errorStatuses = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{store.ErrAccountNotFound, http.StatusNotFound, CodeAccountNotFound},
+	{store.ErrOperationNotFound, http.StatusNotFound, CodeOperationNotFound},
+	{store.ErrTransactionNotFound, http.StatusNotFound, CodeTransactionNotFound},
+	{store.ErrAuthorizationNotFound, http.StatusNotFound, CodeAuthorizationNotFound},
+	{store.ErrInstallmentPlanNotFound, http.StatusNotFound, CodeInstallmentPlanNotFound},
+	{store.ErrTransferNotFound, http.StatusNotFound, CodeTransferNotFound},
+	{model.ErrInvalidMoney, http.StatusBadRequest, CodeInvalidAmount},
+	{model.ErrInvalidCursor, http.StatusBadRequest, CodeBadRequest},
+	{model.ErrZeroAmount, http.StatusUnprocessableEntity, CodeInvalidAmount},
+	{model.ErrInvalidAmountSign, http.StatusUnprocessableEntity, CodeInvalidAmount},
+	{model.ErrCreditLimitExceeded, http.StatusUnprocessableEntity, CodeCreditLimitExceeded},
+	{model.ErrNotReversible, http.StatusUnprocessableEntity, CodeNotReversible},
+	{model.ErrReversalExceedsAmount, http.StatusUnprocessableEntity, CodeInvalidAmount},
+	{model.ErrAlreadyReversed, http.StatusConflict, CodeAlreadyReversed},
+	{model.ErrNotDebit, http.StatusUnprocessableEntity, CodeNotDebit},
+	{model.ErrCaptureExceedsHold, http.StatusUnprocessableEntity, CodeInvalidAmount},
+	{model.ErrAuthorizationClosed, http.StatusConflict, CodeAuthorizationClosed},
+	{model.ErrInvalidInstallments, http.StatusUnprocessableEntity, CodeInvalidAmount},
+	{model.ErrSameAccount, http.StatusUnprocessableEntity, CodeSameAccount},
+	{model.ErrCurrencyMismatch, http.StatusUnprocessableEntity, CodeCurrencyMismatch},
+	{store.ErrFXRateNotFound, http.StatusUnprocessableEntity, CodeFXRateNotFound},
+	{model.ErrInvalidFXRate, http.StatusBadRequest, CodeBadRequest},
+	{model.ErrAccountBlocked, http.StatusUnprocessableEntity, CodeAccountBlocked},
+	{model.ErrAccountClosed, http.StatusUnprocessableEntity, CodeAccountClosed},
+	{model.ErrInvalidStatusTransition, http.StatusConflict, CodeInvalidStatusTransition},
+	{model.ErrBalanceNotZero, http.StatusUnprocessableEntity, CodeBalanceNotZero},
+	{model.ErrAuthorizationsPending, http.StatusUnprocessableEntity, CodeAuthorizationsPending},
+	{context.DeadlineExceeded, http.StatusGatewayTimeout, CodeTimeout},
+	{context.Canceled, StatusClientClosedRequest, CodeRequestCancelled},
+}
+
+// writeError writes an error response.
+func writeError(w http.ResponseWriter, r *http.Request, status int, code string, message string, details ...ErrorDetail) {
+	body := ErrorResponse{
+		Code:      code,
+		Message:   message,
+		Details:   details,
+		RequestID: middleware.GetReqID(r.Context()),
+	}
+
+	contentType := "application/json"
+	if strings.Contains(r.Header.Get("Accept"), problemContentType) {
+		contentType = problemContentType
+		body.Type = "about:blank"
+		body.Title = http.StatusText(status)
+		body.Status = status
+		body.Detail = message
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeStoreError writes the response for an error returned by the store or
+// the model. Unknown errors are logged and reported as 500s without their
+// text, which may contain internals.
+func writeStoreError(w http.ResponseWriter, r *http.Request, err error) {
+	var validationErr model.ValidationError
+	if errors.As(err, &validationErr) {
+		details := make([]ErrorDetail, len(validationErr))
+		for i, f := range validationErr {
+			details[i] = ErrorDetail{Field: f.Field, Message: f.Message}
+		}
+		writeError(w, r, http.StatusUnprocessableEntity, CodeValidationFailed, "the request has invalid fields", details...)
+		return
+	}
+	for _, e := range errorStatuses {
+		if errors.Is(err, e.err) {
+			writeError(w, r, e.status, e.code, err.Error())
+			return
+		}
+	}
+	slog.Error("request failed", "request_id", middleware.GetReqID(r.Context()), "error", err)
+	writeError(w, r, http.StatusInternalServerError, CodeInternal, "internal server error")
+}
+
+// writeDecodeError writes the response for a request body that could not
+// be decoded.
+func writeDecodeError(w http.ResponseWriter, r *http.Request, err error) {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.Is(err, model.ErrInvalidMoney):
+		writeError(w, r, http.StatusBadRequest, CodeInvalidAmount, err.Error())
+		return
+	case errors.Is(err, model.ErrInvalidFXRate):
+		writeError(w, r, http.StatusBadRequest, CodeBadRequest, err.Error())
+		return
+	case errors.As(err, &tooLarge):
+		writeError(w, r, http.StatusRequestEntityTooLarge, CodeBadRequest, fmt.Sprintf("request body is larger than %d bytes", tooLarge.Limit))
+		return
+	}
+	writeError(w, r, http.StatusBadRequest, CodeInvalidJSON, fmt.Sprintf("invalid request body: %v", err))
+}
@@ -3,11 +3,14 @@ package server
 import (
 	"account-transactions/model"
 	"account-transactions/store"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
+	"mime"
 	"net/http"
+	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -20,8 +23,9 @@ import (
 //	@Accept			json
 //	@Produce		json
 //
-//	@Failure		400	{string}	string	"Bad Request"
-//	@Failure		500	{string}	string	"Internal Server Error"
+//	@Failure		400	{object}	ErrorResponse	"Bad Request"
+//	@Failure		404	{object}	ErrorResponse	"Not Found"
+//	@Failure		500	{object}	ErrorResponse	"Internal Server Error"
 //	@Success		200	{object}	model.AccountImpl
 //
 //	@Router			/accounts/{accountId} [get]
@@ -33,15 +37,13 @@ func HandleGetAccount(db store.Store) http.HandlerFunc {
 		// Convert string to int.
 		accountIdInt, err := strconv.Atoi(accountId)
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write(fmt.Appendf(nil, "invalid account ID %s: %v", accountId, err))
+			writeError(w, r, http.StatusBadRequest, CodeBadRequest, fmt.Sprintf("invalid account ID %s", accountId))
 			return
 		}
 
-		gotAccount, err := db.GetAccount(accountIdInt)
+		gotAccount, err := db.GetAccount(r.Context(), accountIdInt)
 		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write(fmt.Appendf(nil, "account not found with ID %s: %v", accountId, err))
+			writeStoreError(w, r, err)
 			return
 		}
 
@@ -61,8 +63,11 @@ func HandleGetAccount(db store.Store) http.HandlerFunc {
 //	@Accept			json
 //	@Produce		json
 //	@Body			{object} model.AccountImpl
+//	@Param			Idempotency-Key	header	string	false	"Replays the original response when the request is retried"
 //
-//	@Failure		500	{string}	string	"Internal Server Error"
+//	@Failure		400	{object}	ErrorResponse	"Bad Request"
+//	@Failure		409	{object}	ErrorResponse	"Conflict"
+//	@Failure		500	{object}	ErrorResponse	"Internal Server Error"
 //	@Success		201	{object}	model.AccountImpl
 //
 //	@Router			/accounts [post]
@@ -70,21 +75,21 @@ func HandleAccountPost(db store.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		account := model.AccountImpl{}
 
-		body, err := io.ReadAll(r.Body)
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write(fmt.Appendf(nil, "err %v", err))
+		if err := decodeJSON(w, r, &account); err != nil {
+			writeDecodeError(w, r, err)
 			return
 		}
-		if err := json.Unmarshal([]byte(body), &account); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write(fmt.Appendf(nil, "err %v", err))
+		if err := account.Validate(); err != nil {
+			writeStoreError(w, r, err)
 			return
 		}
-		newAccount, err := db.CreateAccount(account.DocumentNumber)
+
+		if account.Currency == "" {
+			account.Currency = model.DefaultCurrency
+		}
+		newAccount, err := db.CreateAccount(r.Context(), account.DocumentNumber, account.Currency)
 		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write(fmt.Appendf(nil, "err %v", err))
+			writeStoreError(w, r, err)
 			return
 		}
 
@@ -99,13 +104,23 @@ func HandleAccountPost(db store.Store) http.HandlerFunc {
 //
 //	@Summary		Create a new transaction
 //	@Description	Creates a transaction with the provided account ID, operation type ID, and amount.
+//	@Description	Debit operations are stored with a negative amount and credits with a positive one.
+//	@Description	Debits are first paid from the unapplied balance of earlier overpayments, oldest first.
+//	@Description	Debits that exceed the account's available credit are rejected with 422.
+//	@Description	An installment purchase (operation type 2) sent with installments is split into that many monthly debits, the first due at once, and the installment schedule is returned instead of a transaction.
+//	@Description	Blocked accounts reject debits and closed accounts reject every transaction with 422.
+//	@Description	A transaction sent in another currency than the account's is converted with the stored exchange rate and keeps its original amount, currency and rate; it is rejected with 422 if there is no rate.
 //	@Tags			transaction
 //	@Accept			json
 //	@Produce		json
 //	@Body			model.TransactionImpl	true				"Transaction to create"
+//	@Param			Idempotency-Key			header		string	false	"Replays the original response when the request is retried"
 //
-//	@Failure		404						{string}	string	"Not Found"
-//	@Failure		500						{string}	string	"Internal Server Error"
+//	@Failure		400						{object}	ErrorResponse	"Bad Request"
+//	@Failure		404						{object}	ErrorResponse	"Not Found"
+//	@Failure		409						{object}	ErrorResponse	"Conflict"
+//	@Failure		422						{object}	ErrorResponse	"Unprocessable Entity"
+//	@Failure		500						{object}	ErrorResponse	"Internal Server Error"
 //	@Success		201						{object}	model.TransactionImpl
 //
 //	@Router			/transactions [post]
@@ -114,68 +129,506 @@ func HandleTransactionPost(db store.Store) http.HandlerFunc {
 
 		transaction := model.TransactionImpl{}
 
-		body, err := io.ReadAll(r.Body)
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write(fmt.Appendf(nil, "err %v", err))
+		if err := decodeJSON(w, r, &transaction); err != nil {
+			writeDecodeError(w, r, err)
 			return
 		}
-		if err := json.Unmarshal([]byte(body), &transaction); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write(fmt.Appendf(nil, "err %v", err))
+		if err := transaction.Validate(); err != nil {
+			writeStoreError(w, r, err)
 			return
 		}
 
 		// Validate account id.
-		_, err = db.GetAccount(transaction.AccountID)
+		account, err := db.GetAccount(r.Context(), transaction.AccountID)
 		if err != nil {
-			w.WriteHeader(http.StatusNotFound)
-			w.Write(fmt.Appendf(nil, "err account doesn't exist %v", err))
+			writeStoreError(w, r, err)
 			return
 		}
 
 		// Validate operation id.
-		operation, err := db.GetOperation(transaction.OperationTypeID)
+		operation, err := db.GetOperation(r.Context(), transaction.OperationTypeID)
 		if err != nil {
-			w.WriteHeader(http.StatusNotFound)
-			w.Write(fmt.Appendf(nil, "err operation doesn't exist %v", err))
+			writeStoreError(w, r, err)
 			return
 		}
 
-		// Check if purchase.
-		if operation.IsPurchase() {
-			balance := transaction.Amount
-			transaction.Balance = balance
-		} else if operation.IsPayment() {
-			// get all transactions:	 gets transactions
-			var operationTypePurchase = 1
-			transactions, err := db.GetNegativeTransactions(transaction.AccountID, operationTypePurchase)
-			if err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
-				w.Write(fmt.Appendf(nil, "err %v", err))
+		// Store the amount with the operation's sign.
+		transaction.Amount, err = operation.NormaliseAmount(transaction.Amount)
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+
+		// Convert amounts sent in another currency to the account's.
+		if transaction.Currency != "" && transaction.Currency != account.Currency {
+			if err := convert(r.Context(), db, &transaction, account.Currency); err != nil {
+				writeStoreError(w, r, err)
 				return
 			}
+		}
 
-			// process payments:	 	list of transactions
-			processNegativeTransactions, amount, err := model.ProcessNegativePayments(transactions, transaction.Amount)
+		if transaction.Installments != nil {
+			// Check the whole purchase against the credit limit and store
+			// its installments atomically.
+			plan, err := db.CreateInstallmentPlan(r.Context(), transaction, *transaction.Installments)
 			if err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
-				w.Write(fmt.Appendf(nil, "err %v", err))
+				writeStoreError(w, r, err)
 				return
 			}
 
-			// set transactions with payments: set transactions
-			err = db.UpdateNegativeTransactions(processNegativeTransactions)
-			transaction.Balance = amount
+			// Success.
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(model.NewInstallmentSchedule(plan, time.Now()))
+			return
+		}
+
+		var result *model.TransactionImpl
+		if operation.IsDebit() {
+			// Apply any payment credit, check the credit limit and store the
+			// debit atomically.
+			result, err = db.CreateDebit(r.Context(), transaction)
 		} else {
-			transaction.Balance = 0
+			// Settle outstanding debts and store the payment atomically.
+			result, err = db.SettlePayment(r.Context(), transaction)
+		}
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+
+		// Success.
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+// HandleCreditLimitPut changes an account's credit limit.
+//
+//	@Summary		Change an account's credit limit
+//	@Description	Sets the account's credit limit and records the change in its history. Debits that would take the outstanding debt past the limit are rejected.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			accountId		path		int						true	"Account ID"
+//	@Param			update			body		model.CreditLimitUpdate	true	"New credit limit and the reason for the change"
+//	@Param			Idempotency-Key	header		string					false	"Replays the original response when the request is retried"
+//
+//	@Failure		400				{object}	ErrorResponse			"Bad Request"
+//	@Failure		404				{object}	ErrorResponse			"Not Found"
+//	@Failure		422				{object}	ErrorResponse			"Unprocessable Entity"
+//	@Failure		500				{object}	ErrorResponse			"Internal Server Error"
+//	@Success		200				{object}	model.CreditLimitChange
+//
+//	@Router			/admin/accounts/{accountId}/credit-limit [put]
+func HandleCreditLimitPut(db store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Get account ID from URL params.
+		accountId := chi.URLParam(r, "accountId")
+		// Convert string to int.
+		accountIdInt, err := strconv.Atoi(accountId)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, CodeBadRequest, fmt.Sprintf("invalid account ID %s", accountId))
+			return
+		}
+
+		update := model.CreditLimitUpdate{}
+		if err := decodeJSON(w, r, &update); err != nil {
+			writeDecodeError(w, r, err)
+			return
+		}
+		if err := update.Validate(); err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+
+		change, err := db.UpdateCreditLimit(r.Context(), model.CreditLimitChange{
+			AccountID: accountIdInt,
+			NewLimit:  *update.CreditLimit,
+			Reason:    update.Reason,
+		})
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+
+		// Success.
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(change)
+	}
+}
+
+// HandleListCreditLimitChanges lists an account's credit limit changes.
+//
+//	@Summary		Lists an account's credit limit history
+//	@Description	List every change of the account's credit limit, oldest first.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			accountId	path		int		true	"Account ID"
+//
+//	@Failure		400			{object}	ErrorResponse	"Bad Request"
+//	@Failure		404			{object}	ErrorResponse	"Not Found"
+//	@Failure		500			{object}	ErrorResponse	"Internal Server Error"
+//	@Success		200			{object}	model.CreditLimitHistory
+//
+//	@Router			/admin/accounts/{accountId}/credit-limit/history [get]
+func HandleListCreditLimitChanges(db store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Get account ID from URL params.
+		accountId := chi.URLParam(r, "accountId")
+		// Convert string to int.
+		accountIdInt, err := strconv.Atoi(accountId)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, CodeBadRequest, fmt.Sprintf("invalid account ID %s", accountId))
+			return
+		}
+
+		// Validate account id.
+		_, err = db.GetAccount(r.Context(), accountIdInt)
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+
+		changes, err := db.ListCreditLimitChanges(r.Context(), accountIdInt)
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+
+		// Success.
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(model.CreditLimitHistory{Changes: changes})
+	}
+}
+
+// HandleAccountBlock blocks an account.
+//
+//	@Summary		Block an account
+//	@Description	Blocks an active account and records the change in its history. Blocked accounts accept payments and other credits but reject purchases and other debits with 422.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			accountId		path		int							true	"Account ID"
+//	@Param			update			body		model.AccountStatusUpdate	true	"Reason for the change"
+//	@Param			Idempotency-Key	header		string						false	"Replays the original response when the request is retried"
+//
+//	@Failure		400				{object}	ErrorResponse				"Bad Request"
+//	@Failure		404				{object}	ErrorResponse				"Not Found"
+//	@Failure		409				{object}	ErrorResponse				"Conflict"
+//	@Failure		422				{object}	ErrorResponse				"Unprocessable Entity"
+//	@Failure		500				{object}	ErrorResponse				"Internal Server Error"
+//	@Success		200				{object}	model.AccountStatusChange
+//
+//	@Router			/admin/accounts/{accountId}/block [post]
+func HandleAccountBlock(db store.Store) http.HandlerFunc {
+	return handleAccountStatus(db, model.AccountBlocked)
+}
+
+// HandleAccountUnblock makes a blocked account active again.
+//
+//	@Summary		Unblock an account
+//	@Description	Makes a blocked account active again and records the change in its history.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			accountId		path		int							true	"Account ID"
+//	@Param			update			body		model.AccountStatusUpdate	true	"Reason for the change"
+//	@Param			Idempotency-Key	header		string						false	"Replays the original response when the request is retried"
+//
+//	@Failure		400				{object}	ErrorResponse				"Bad Request"
+//	@Failure		404				{object}	ErrorResponse				"Not Found"
+//	@Failure		409				{object}	ErrorResponse				"Conflict"
+//	@Failure		422				{object}	ErrorResponse				"Unprocessable Entity"
+//	@Failure		500				{object}	ErrorResponse				"Internal Server Error"
+//	@Success		200				{object}	model.AccountStatusChange
+//
+//	@Router			/admin/accounts/{accountId}/unblock [post]
+func HandleAccountUnblock(db store.Store) http.HandlerFunc {
+	return handleAccountStatus(db, model.AccountActive)
+}
+
+// HandleAccountClose closes an account.
+//
+//	@Summary		Close an account
+//	@Description	Closes an active or blocked account whose balance, the sum of its transactions, is zero and that has no pending authorizations, and records the change in its history. Closed accounts accept no transactions and cannot be reopened.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			accountId		path		int							true	"Account ID"
+//	@Param			update			body		model.AccountStatusUpdate	true	"Reason for the change"
+//	@Param			Idempotency-Key	header		string						false	"Replays the original response when the request is retried"
+//
+//	@Failure		400				{object}	ErrorResponse				"Bad Request"
+//	@Failure		404				{object}	ErrorResponse				"Not Found"
+//	@Failure		409				{object}	ErrorResponse				"Conflict"
+//	@Failure		422				{object}	ErrorResponse				"Unprocessable Entity"
+//	@Failure		500				{object}	ErrorResponse				"Internal Server Error"
+//	@Success		200				{object}	model.AccountStatusChange
+//
+//	@Router			/admin/accounts/{accountId}/close [post]
+func HandleAccountClose(db store.Store) http.HandlerFunc {
+	return handleAccountStatus(db, model.AccountClosed)
+}
+
+// handleAccountStatus moves an account to the status.
+func handleAccountStatus(db store.Store, status model.AccountStatus) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Get account ID from URL params.
+		accountId := chi.URLParam(r, "accountId")
+		// Convert string to int.
+		accountIdInt, err := strconv.Atoi(accountId)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, CodeBadRequest, fmt.Sprintf("invalid account ID %s", accountId))
+			return
+		}
+
+		update := model.AccountStatusUpdate{}
+		if err := decodeJSON(w, r, &update); err != nil {
+			writeDecodeError(w, r, err)
+			return
+		}
+		if err := update.Validate(); err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+
+		change, err := db.UpdateAccountStatus(r.Context(), model.AccountStatusChange{
+			AccountID: accountIdInt,
+			NewStatus: status,
+			Reason:    update.Reason,
+		})
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+
+		// Success.
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(change)
+	}
+}
+
+// HandleListAccountStatusChanges lists an account's status changes.
+//
+//	@Summary		Lists an account's status history
+//	@Description	List every block, unblock and close of the account, oldest first.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			accountId	path		int		true	"Account ID"
+//
+//	@Failure		400			{object}	ErrorResponse	"Bad Request"
+//	@Failure		404			{object}	ErrorResponse	"Not Found"
+//	@Failure		500			{object}	ErrorResponse	"Internal Server Error"
+//	@Success		200			{object}	model.AccountStatusHistory
+//
+//	@Router			/admin/accounts/{accountId}/status/history [get]
+func HandleListAccountStatusChanges(db store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Get account ID from URL params.
+		accountId := chi.URLParam(r, "accountId")
+		// Convert string to int.
+		accountIdInt, err := strconv.Atoi(accountId)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, CodeBadRequest, fmt.Sprintf("invalid account ID %s", accountId))
+			return
+		}
+
+		// Validate account id.
+		_, err = db.GetAccount(r.Context(), accountIdInt)
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+
+		changes, err := db.ListAccountStatusChanges(r.Context(), accountIdInt)
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+
+		// Success.
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(model.AccountStatusHistory{Changes: changes})
+	}
+}
+
+// HandleFXRatesPut loads exchange rates.
+//
+//	@Summary		Load exchange rates
+//	@Description	Stores the given exchange rates, replacing any already stored for the same currencies. Rates only convert from their base to their quote currency.
+//	@Description	Send JSON, or CSV with the header base_currency,quote_currency,rate and Content-Type text/csv.
+//	@Tags			admin
+//	@Accept			json,text/csv
+//	@Produce		json
+//	@Param			rates			body		model.FXRateList	true	"Exchange rates to store"
+//	@Param			Idempotency-Key	header		string				false	"Replays the original response when the request is retried"
+//
+//	@Failure		400				{object}	ErrorResponse		"Bad Request"
+//	@Failure		422				{object}	ErrorResponse		"Unprocessable Entity"
+//	@Failure		500				{object}	ErrorResponse		"Internal Server Error"
+//	@Success		200				{object}	model.FXRateList
+//
+//	@Router			/admin/fx-rates [put]
+func HandleFXRatesPut(db store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		list := &model.FXRateList{}
+		if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "text/csv" {
+			var err error
+			list, err = model.ParseFXRatesCSV(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+			if err != nil {
+				writeDecodeError(w, r, err)
+				return
+			}
+		} else if err := decodeJSON(w, r, list); err != nil {
+			writeDecodeError(w, r, err)
+			return
+		}
+		if err := list.Validate(); err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+
+		rates, err := db.UpdateFXRates(r.Context(), list.Rates)
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+
+		// Success.
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(model.FXRateList{Rates: rates})
+	}
+}
+
+// HandleListFXRates lists the exchange rates.
+//
+//	@Summary		List exchange rates
+//	@Description	List every stored exchange rate, by base and then quote currency.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//
+//	@Failure		500	{object}	ErrorResponse	"Internal Server Error"
+//	@Success		200	{object}	model.FXRateList
+//
+//	@Router			/admin/fx-rates [get]
+func HandleListFXRates(db store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		rates, err := db.ListFXRates(r.Context())
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+
+		// Success.
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(model.FXRateList{Rates: rates})
+	}
+}
+
+// HandleGetTransaction retrieves a transaction.
+//
+//	@Summary		Retrieves a transaction by ID
+//	@Description	Retrieve a transaction with the provided transaction ID.
+//	@Tags			transaction
+//	@Accept			json
+//	@Produce		json
+//
+//	@Param			transactionId	path		int		true	"Transaction ID"
+//
+//	@Failure		400				{object}	ErrorResponse	"Bad Request"
+//	@Failure		404				{object}	ErrorResponse	"Not Found"
+//	@Failure		500				{object}	ErrorResponse	"Internal Server Error"
+//	@Success		200				{object}	model.TransactionImpl
+//
+//	@Router			/transactions/{transactionId} [get]
+func HandleGetTransaction(db store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Get transaction ID from URL params.
+		transactionId := chi.URLParam(r, "transactionId")
+		// Convert string to int.
+		transactionIdInt, err := strconv.Atoi(transactionId)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, CodeBadRequest, fmt.Sprintf("invalid transaction ID %s", transactionId))
+			return
+		}
+
+		gotTransaction, err := db.GetTransaction(r.Context(), transactionIdInt)
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+
+		// Success.
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(gotTransaction)
+	}
+}
+
+// HandleTransactionReversal reverses a transaction.
+//
+//	@Summary		Reverse a transaction
+//	@Description	Creates a refund for a debit or a payment reversal for a credit, linked to the original by original_transaction_id.
+//	@Description	Leave out the amount to reverse all that is left of the original; a transaction can be reversed in parts until nothing is left.
+//	@Description	The reversal first cancels the original's open balance. A refund then settles other debts like a payment, and a payment reversal draws on other payments' credit like a debit.
+//	@Tags			transaction
+//	@Accept			json
+//	@Produce		json
+//	@Param			transactionId	path		int						true	"Transaction ID"
+//	@Param			reversal		body		model.ReversalRequest	true	"Amount to reverse"
+//	@Param			Idempotency-Key	header		string					false	"Replays the original response when the request is retried"
+//
+//	@Failure		400				{object}	ErrorResponse			"Bad Request"
+//	@Failure		404				{object}	ErrorResponse			"Not Found"
+//	@Failure		409				{object}	ErrorResponse			"Conflict"
+//	@Failure		422				{object}	ErrorResponse			"Unprocessable Entity"
+//	@Failure		500				{object}	ErrorResponse			"Internal Server Error"
+//	@Success		201				{object}	model.TransactionImpl
+//
+//	@Router			/transactions/{transactionId}/reversal [post]
+func HandleTransactionReversal(db store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Get transaction ID from URL params.
+		transactionId := chi.URLParam(r, "transactionId")
+		// Convert string to int.
+		transactionIdInt, err := strconv.Atoi(transactionId)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, CodeBadRequest, fmt.Sprintf("invalid transaction ID %s", transactionId))
+			return
+		}
+
+		reversal := model.ReversalRequest{}
+		if err := decodeJSON(w, r, &reversal); err != nil {
+			writeDecodeError(w, r, err)
+			return
+		}
+		if err := reversal.Validate(); err != nil {
+			writeStoreError(w, r, err)
+			return
 		}
 
-		// Store.
-		result, err := db.CreateTransaction(transaction)
+		result, err := db.ReverseTransaction(r.Context(), transactionIdInt, reversal.Amount)
 		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write(fmt.Appendf(nil, "err %v", err))
+			writeStoreError(w, r, err)
 			return
 		}
 
@@ -185,3 +638,594 @@ func HandleTransactionPost(db store.Store) http.HandlerFunc {
 		json.NewEncoder(w).Encode(result)
 	}
 }
+
+// HandleAuthorizationPost authorizes a debit.
+//
+//	@Summary		Authorize a debit
+//	@Description	Holds the amount of a debit against the account's available credit until it is captured, voided or expires after the configured TTL.
+//	@Description	The hold counts against the credit limit like a posted debit; only debit operations can be authorized.
+//	@Tags			authorization
+//	@Accept			json
+//	@Produce		json
+//	@Param			authorization	body		model.AuthorizationRequest	true	"Debit to authorize"
+//	@Param			Idempotency-Key	header		string						false	"Replays the original response when the request is retried"
+//
+//	@Failure		400				{object}	ErrorResponse				"Bad Request"
+//	@Failure		404				{object}	ErrorResponse				"Not Found"
+//	@Failure		409				{object}	ErrorResponse				"Conflict"
+//	@Failure		422				{object}	ErrorResponse				"Unprocessable Entity"
+//	@Failure		500				{object}	ErrorResponse				"Internal Server Error"
+//	@Success		201				{object}	model.AuthorizationImpl
+//
+//	@Router			/authorizations [post]
+func HandleAuthorizationPost(db store.Store, ttl time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		request := model.AuthorizationRequest{}
+		if err := decodeJSON(w, r, &request); err != nil {
+			writeDecodeError(w, r, err)
+			return
+		}
+		if err := request.Validate(); err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+
+		// Validate account id.
+		_, err := db.GetAccount(r.Context(), request.AccountID)
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+
+		// Validate operation id.
+		operation, err := db.GetOperation(r.Context(), request.OperationTypeID)
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+		if !operation.IsDebit() {
+			writeStoreError(w, r, fmt.Errorf("%w: %s is a credit", model.ErrNotDebit, operation.Description))
+			return
+		}
+
+		// Hold the amount with the debit's sign.
+		amount, err := operation.NormaliseAmount(request.Amount)
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+
+		expiresAt := time.Now().Add(ttl)
+		authorization, err := db.CreateAuthorization(r.Context(), model.AuthorizationImpl{
+			AccountID:       request.AccountID,
+			OperationTypeID: request.OperationTypeID,
+			Amount:          amount,
+			ExpiresAt:       &expiresAt,
+		})
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+
+		// Success.
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(authorization)
+	}
+}
+
+// HandleGetAuthorization retrieves an authorization.
+//
+//	@Summary		Retrieves an authorization by ID
+//	@Description	Retrieve an authorization with the provided authorization ID.
+//	@Tags			authorization
+//	@Accept			json
+//	@Produce		json
+//	@Param			authorizationId	path		int		true	"Authorization ID"
+//
+//	@Failure		400				{object}	ErrorResponse	"Bad Request"
+//	@Failure		404				{object}	ErrorResponse	"Not Found"
+//	@Failure		500				{object}	ErrorResponse	"Internal Server Error"
+//	@Success		200				{object}	model.AuthorizationImpl
+//
+//	@Router			/authorizations/{authorizationId} [get]
+func HandleGetAuthorization(db store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Get authorization ID from URL params.
+		authorizationId := chi.URLParam(r, "authorizationId")
+		// Convert string to int.
+		authorizationIdInt, err := strconv.Atoi(authorizationId)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, CodeBadRequest, fmt.Sprintf("invalid authorization ID %s", authorizationId))
+			return
+		}
+
+		authorization, err := db.GetAuthorization(r.Context(), authorizationIdInt)
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+
+		// Success.
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(authorization)
+	}
+}
+
+// HandleAuthorizationCapture captures an authorization.
+//
+//	@Summary		Capture an authorization
+//	@Description	Posts the authorized debit, or part of it, as a transaction and releases the hold. Leave out the amount to capture all of it; the rest of a partial capture is released.
+//	@Description	The capture is paid from unapplied payment credit like any debit, but is not checked against the credit limit again.
+//	@Tags			authorization
+//	@Accept			json
+//	@Produce		json
+//	@Param			authorizationId	path		int						true	"Authorization ID"
+//	@Param			capture			body		model.CaptureRequest	true	"Amount to capture"
+//	@Param			Idempotency-Key	header		string					false	"Replays the original response when the request is retried"
+//
+//	@Failure		400				{object}	ErrorResponse			"Bad Request"
+//	@Failure		404				{object}	ErrorResponse			"Not Found"
+//	@Failure		409				{object}	ErrorResponse			"Conflict"
+//	@Failure		422				{object}	ErrorResponse			"Unprocessable Entity"
+//	@Failure		500				{object}	ErrorResponse			"Internal Server Error"
+//	@Success		201				{object}	model.TransactionImpl
+//
+//	@Router			/authorizations/{authorizationId}/capture [post]
+func HandleAuthorizationCapture(db store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Get authorization ID from URL params.
+		authorizationId := chi.URLParam(r, "authorizationId")
+		// Convert string to int.
+		authorizationIdInt, err := strconv.Atoi(authorizationId)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, CodeBadRequest, fmt.Sprintf("invalid authorization ID %s", authorizationId))
+			return
+		}
+
+		capture := model.CaptureRequest{}
+		if err := decodeJSON(w, r, &capture); err != nil {
+			writeDecodeError(w, r, err)
+			return
+		}
+		if err := capture.Validate(); err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+
+		result, err := db.CaptureAuthorization(r.Context(), authorizationIdInt, capture.Amount)
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+
+		// Success.
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+// HandleAuthorizationVoid voids an authorization.
+//
+//	@Summary		Void an authorization
+//	@Description	Releases the authorization's hold without posting anything.
+//	@Tags			authorization
+//	@Accept			json
+//	@Produce		json
+//	@Param			authorizationId	path		int		true	"Authorization ID"
+//	@Param			Idempotency-Key	header		string	false	"Replays the original response when the request is retried"
+//
+//	@Failure		400				{object}	ErrorResponse	"Bad Request"
+//	@Failure		404				{object}	ErrorResponse	"Not Found"
+//	@Failure		409				{object}	ErrorResponse	"Conflict"
+//	@Failure		500				{object}	ErrorResponse	"Internal Server Error"
+//	@Success		200				{object}	model.AuthorizationImpl
+//
+//	@Router			/authorizations/{authorizationId}/void [post]
+func HandleAuthorizationVoid(db store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Get authorization ID from URL params.
+		authorizationId := chi.URLParam(r, "authorizationId")
+		// Convert string to int.
+		authorizationIdInt, err := strconv.Atoi(authorizationId)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, CodeBadRequest, fmt.Sprintf("invalid authorization ID %s", authorizationId))
+			return
+		}
+
+		authorization, err := db.VoidAuthorization(r.Context(), authorizationIdInt)
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+
+		// Success.
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(authorization)
+	}
+}
+
+// HandleGetInstallmentSchedule retrieves an installment plan's schedule.
+//
+//	@Summary		Retrieves an installment plan's schedule
+//	@Description	Lists the plan's installments, soonest due first, with whether each is paid, unpaid or not due yet, and how much of the plan is paid and still owed.
+//	@Tags			transaction
+//	@Accept			json
+//	@Produce		json
+//	@Param			planId	path		int		true	"Installment plan ID"
+//
+//	@Failure		400		{object}	ErrorResponse	"Bad Request"
+//	@Failure		404		{object}	ErrorResponse	"Not Found"
+//	@Failure		500		{object}	ErrorResponse	"Internal Server Error"
+//	@Success		200		{object}	model.InstallmentSchedule
+//
+//	@Router			/installment-plans/{planId}/schedule [get]
+func HandleGetInstallmentSchedule(db store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Get plan ID from URL params.
+		planId := chi.URLParam(r, "planId")
+		// Convert string to int.
+		planIdInt, err := strconv.Atoi(planId)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, CodeBadRequest, fmt.Sprintf("invalid installment plan ID %s", planId))
+			return
+		}
+
+		plan, err := db.GetInstallmentPlan(r.Context(), planIdInt)
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+
+		// Success.
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(model.NewInstallmentSchedule(plan, time.Now()))
+	}
+}
+
+// HandleTransferPost moves funds between two accounts.
+//
+//	@Summary		Transfer funds between accounts
+//	@Description	Posts a TRANSFER OUT debit on the source account and a TRANSFER IN credit on the destination at once, both linked to the transfer.
+//	@Description	The debit is paid from the source's unapplied payment credit first and is rejected with 422 if the rest exceeds its available credit.
+//	@Description	The credit settles the destination's debts like a payment. Transfers to the same account are rejected.
+//	@Description	The source must be active and the destination must not be closed, or the transfer is rejected with 422.
+//	@Description	The amount is in the source account's currency. It is converted with the stored exchange rate when the destination's currency differs, and rejected with 422 if there is no rate.
+//	@Tags			transfer
+//	@Accept			json
+//	@Produce		json
+//	@Param			transfer		body		model.TransferRequest	true	"Accounts and amount to transfer"
+//	@Param			Idempotency-Key	header		string					false	"Replays the original response when the request is retried"
+//
+//	@Failure		400				{object}	ErrorResponse			"Bad Request"
+//	@Failure		404				{object}	ErrorResponse			"Not Found"
+//	@Failure		409				{object}	ErrorResponse			"Conflict"
+//	@Failure		422				{object}	ErrorResponse			"Unprocessable Entity"
+//	@Failure		500				{object}	ErrorResponse			"Internal Server Error"
+//	@Success		201				{object}	model.Transfer
+//
+//	@Router			/transfers [post]
+func HandleTransferPost(db store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		request := model.TransferRequest{}
+
+		if err := decodeJSON(w, r, &request); err != nil {
+			writeDecodeError(w, r, err)
+			return
+		}
+		if err := request.Validate(); err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+
+		// Validate account ids.
+		source, err := db.GetAccount(r.Context(), request.SourceAccountID)
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+		destination, err := db.GetAccount(r.Context(), request.DestinationAccountID)
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+
+		// Move the amount in the source's currency and convert the credit
+		// to the destination's.
+		newTransfer := model.NewTransfer(request)
+		newTransfer.Debit.Currency = source.Currency
+		newTransfer.Credit.Currency = source.Currency
+		if destination.Currency != source.Currency {
+			if err := convert(r.Context(), db, newTransfer.Credit, destination.Currency); err != nil {
+				writeStoreError(w, r, err)
+				return
+			}
+		}
+
+		// Post the debit and the credit atomically.
+		transfer, err := db.CreateTransfer(r.Context(), *newTransfer)
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+
+		// Success.
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(transfer)
+	}
+}
+
+// HandleGetTransfer retrieves a transfer.
+//
+//	@Summary		Retrieves a transfer by ID
+//	@Description	Retrieve a transfer with its debit and credit.
+//	@Tags			transfer
+//	@Accept			json
+//	@Produce		json
+//	@Param			transferId	path		int		true	"Transfer ID"
+//
+//	@Failure		400			{object}	ErrorResponse	"Bad Request"
+//	@Failure		404			{object}	ErrorResponse	"Not Found"
+//	@Failure		500			{object}	ErrorResponse	"Internal Server Error"
+//	@Success		200			{object}	model.Transfer
+//
+//	@Router			/transfers/{transferId} [get]
+func HandleGetTransfer(db store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Get transfer ID from URL params.
+		transferId := chi.URLParam(r, "transferId")
+		// Convert string to int.
+		transferIdInt, err := strconv.Atoi(transferId)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, CodeBadRequest, fmt.Sprintf("invalid transfer ID %s", transferId))
+			return
+		}
+
+		transfer, err := db.GetTransfer(r.Context(), transferIdInt)
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+
+		// Success.
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(transfer)
+	}
+}
+
+// HandleListAccountTransactions lists an account's transactions.
+//
+//	@Summary		Lists an account's transactions
+//	@Description	List the transactions of an account, one page at a time. Pass the returned next_cursor as cursor to get the next page.
+//	@Tags			transaction
+//	@Accept			json
+//	@Produce		json
+//
+//	@Param			accountId			path		int		true	"Account ID"
+//	@Param			operation_type_id	query		int		false	"Only list this operation type"
+//	@Param			from				query		string	false	"Only list transactions at or after this RFC 3339 time"
+//	@Param			to					query		string	false	"Only list transactions before this RFC 3339 time"
+//	@Param			sort				query		string	false	"Sort order by event date: asc (default) or desc"
+//	@Param			limit				query		int		false	"Page size, at most 200 (default 50)"
+//	@Param			cursor				query		string	false	"Cursor from the previous page"
+//
+//	@Failure		400					{object}	ErrorResponse	"Bad Request"
+//	@Failure		404					{object}	ErrorResponse	"Not Found"
+//	@Failure		500					{object}	ErrorResponse	"Internal Server Error"
+//	@Success		200					{object}	model.TransactionPage
+//
+//	@Router			/accounts/{accountId}/transactions [get]
+func HandleListAccountTransactions(db store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Get account ID from URL params.
+		accountId := chi.URLParam(r, "accountId")
+		// Convert string to int.
+		accountIdInt, err := strconv.Atoi(accountId)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, CodeBadRequest, fmt.Sprintf("invalid account ID %s", accountId))
+			return
+		}
+
+		filter, err := parseTransactionFilter(r.URL.Query())
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, CodeBadRequest, err.Error())
+			return
+		}
+
+		// Validate account id.
+		_, err = db.GetAccount(r.Context(), accountIdInt)
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+
+		// Ask for one extra transaction to know if there is a next page.
+		limit := filter.Limit
+		filter.Limit++
+		transactions, err := db.ListTransactions(r.Context(), accountIdInt, filter)
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+
+		// Success.
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(model.NewTransactionPage(transactions, limit))
+	}
+}
+
+// HandleGetAccountBalance summarises what an account owes.
+//
+//	@Summary		Retrieves an account's balance
+//	@Description	Sum up the account's outstanding debt per operation type, its available credit and the unapplied credit of overpayments.
+//	@Tags			account
+//	@Accept			json
+//	@Produce		json
+//	@Param			accountId	path		int		true	"Account ID"
+//
+//	@Failure		400			{object}	ErrorResponse	"Bad Request"
+//	@Failure		404			{object}	ErrorResponse	"Not Found"
+//	@Failure		500			{object}	ErrorResponse	"Internal Server Error"
+//	@Success		200			{object}	model.AccountBalance
+//
+//	@Router			/accounts/{accountId}/balance [get]
+func HandleGetAccountBalance(db store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Get account ID from URL params.
+		accountId := chi.URLParam(r, "accountId")
+		// Convert string to int.
+		accountIdInt, err := strconv.Atoi(accountId)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, CodeBadRequest, fmt.Sprintf("invalid account ID %s", accountId))
+			return
+		}
+
+		balance, err := db.GetBalance(r.Context(), accountIdInt)
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+
+		// Success.
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(balance)
+	}
+}
+
+// HandleGetAccountStatement lists an account's transactions in a period.
+//
+//	@Summary		Retrieves an account's statement
+//	@Description	List the account's transactions in a period, oldest first, with the balance before and after it. Balances are the sum of the amounts, so they are negative while the account owes money.
+//	@Tags			account
+//	@Accept			json
+//	@Produce		json
+//
+//	@Param			accountId	path		int		true	"Account ID"
+//	@Param			from		query		string	false	"Start of the period as an RFC 3339 time, inclusive (default: the first transaction)"
+//	@Param			to			query		string	false	"End of the period as an RFC 3339 time, exclusive (default: now)"
+//
+//	@Failure		400			{object}	ErrorResponse	"Bad Request"
+//	@Failure		404			{object}	ErrorResponse	"Not Found"
+//	@Failure		500			{object}	ErrorResponse	"Internal Server Error"
+//	@Success		200			{object}	model.Statement
+//
+//	@Router			/accounts/{accountId}/statement [get]
+func HandleGetAccountStatement(db store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Get account ID from URL params.
+		accountId := chi.URLParam(r, "accountId")
+		// Convert string to int.
+		accountIdInt, err := strconv.Atoi(accountId)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, CodeBadRequest, fmt.Sprintf("invalid account ID %s", accountId))
+			return
+		}
+
+		from, to, err := parsePeriod(r.URL.Query())
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, CodeBadRequest, err.Error())
+			return
+		}
+
+		statement, err := db.GetStatement(r.Context(), accountIdInt, from, to)
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+
+		// Success.
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(statement)
+	}
+}
+
+// parsePeriod reads the from and to query parameters of a statement.
+func parsePeriod(query url.Values) (*time.Time, *time.Time, error) {
+	var from, to *time.Time
+	for name, date := range map[string]**time.Time{"from": &from, "to": &to} {
+		if v := query.Get(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid %s %s: %v", name, v, err)
+			}
+			*date = &t
+		}
+	}
+	if from != nil && to != nil && !from.Before(*to) {
+		return nil, nil, fmt.Errorf("invalid period: from %s is not before to %s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+	return from, to, nil
+}
+
+// parseTransactionFilter reads the listing query parameters.
+func parseTransactionFilter(query url.Values) (model.TransactionFilter, error) {
+	filter := model.TransactionFilter{Limit: model.DefaultPageSize}
+
+	if v := query.Get("operation_type_id"); v != "" {
+		operationTypeId, err := strconv.Atoi(v)
+		if err != nil {
+			return filter, fmt.Errorf("invalid operation_type_id %s: %v", v, err)
+		}
+		filter.OperationTypeID = &operationTypeId
+	}
+	for name, date := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		if v := query.Get(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return filter, fmt.Errorf("invalid %s %s: %v", name, v, err)
+			}
+			*date = &t
+		}
+	}
+	switch v := query.Get("sort"); v {
+	case "", "asc":
+	case "desc":
+		filter.Descending = true
+	default:
+		return filter, fmt.Errorf("invalid sort %s: must be asc or desc", v)
+	}
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > model.MaxPageSize {
+			return filter, fmt.Errorf("invalid limit %s: must be between 1 and %d", v, model.MaxPageSize)
+		}
+		filter.Limit = limit
+	}
+	if v := query.Get("cursor"); v != "" {
+		cursor, err := model.DecodeCursor(v)
+		if err != nil {
+			return filter, err
+		}
+		filter.After = cursor
+	}
+	return filter, nil
+}
+
+// convert converts the transaction from its currency to the given one
+// with the stored exchange rate.
+func convert(ctx context.Context, db store.Store, transaction *model.TransactionImpl, currency model.Currency) error {
+	rate, err := db.GetFXRate(ctx, transaction.Currency, currency)
+	if err != nil {
+		return err
+	}
+	return transaction.Convert(*rate)
+}
@@ -1,8 +1,10 @@
 package server
 
 import (
+	"account-transactions/config"
 	mock_store "account-transactions/mocks"
 	"account-transactions/model"
+	"account-transactions/store"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -10,6 +12,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
@@ -41,10 +44,12 @@ func TestHandleGetAccount(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	m := mock_store.NewMockStore(ctrl)
 	m.EXPECT().
-		GetAccount(accountIdInt).
+		GetAccount(gomock.Any(), accountIdInt).
 		Return(&model.AccountImpl{
 			AccountID:      model.IntToPtr(accountIdInt),
 			DocumentNumber: documentNumber,
+			Currency:       model.DefaultCurrency,
+			Status:         model.AccountActive,
 		}, nil)
 
 	// When.
@@ -60,7 +65,7 @@ func TestHandleGetAccount(t *testing.T) {
 	}
 
 	// Check the response body is correct
-	expected := fmt.Sprintf("{\"account_id\":%d,\"document_number\":\"%s\"}\n", accountIdInt, documentNumber)
+	expected := fmt.Sprintf("{\"account_id\":%d,\"document_number\":\"%s\",\"currency\":\"USD\",\"status\":\"ACTIVE\"}\n", accountIdInt, documentNumber)
 	got := recorder.Body.String()
 	assert.Equal(t, expected, got)
 }
@@ -76,10 +81,12 @@ func TestHandleAccountPost(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	m := mock_store.NewMockStore(ctrl)
 	m.EXPECT().
-		CreateAccount(documentNumber).
+		CreateAccount(gomock.Any(), documentNumber, model.DefaultCurrency).
 		Return(&model.AccountImpl{
 			AccountID:      model.IntToPtr(accountIdInt),
 			DocumentNumber: documentNumber,
+			Currency:       model.DefaultCurrency,
+			Status:         model.AccountActive,
 		}, nil)
 
 	// When.
@@ -95,18 +102,20 @@ func TestHandleAccountPost(t *testing.T) {
 	}
 
 	// Check the response body is correct
-	expected := fmt.Sprintf("{\"account_id\":%d,\"document_number\":\"%s\"}\n", accountIdInt, documentNumber)
+	expected := fmt.Sprintf("{\"account_id\":%d,\"document_number\":\"%s\",\"currency\":\"USD\",\"status\":\"ACTIVE\"}\n", accountIdInt, documentNumber)
 	got := recorder.Body.String()
 	assert.Equal(t, expected, got)
 }
 
 func TestHandleTransactionPost(t *testing.T) {
 	// Given.
-	transaction := model.NewTransaction(&transactionID, accountIdInt, 4, 5000.00, nil)
-
-	marshalledTransaction, err := json.Marshal(transaction)
+	body := fmt.Sprintf("{\"account_id\":%d,\"operation_type_id\":4,\"amount\":5000.00}", accountIdInt)
+	req, err := http.NewRequest("POST", "/", strings.NewReader(body))
 	require.NoError(t, err)
-	req, err := http.NewRequest("POST", "/", strings.NewReader(string(marshalledTransaction)))
+
+	transaction := model.NewTransaction(nil, accountIdInt, 4, model.MustParseMoney("5000.00"), 0, nil)
+	result := model.NewTransaction(&transactionID, accountIdInt, 4, model.MustParseMoney("5000.00"), model.MustParseMoney("5000.00"), nil)
+	marshalledTransaction, err := json.Marshal(result)
 	require.NoError(t, err)
 
 	recorder := httptest.NewRecorder()
@@ -114,25 +123,23 @@ func TestHandleTransactionPost(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	m := mock_store.NewMockStore(ctrl)
 	m.EXPECT().
-		GetAccount(accountIdInt).
+		GetAccount(gomock.Any(), accountIdInt).
 		Return(&model.AccountImpl{
 			AccountID:      &accountIdInt,
 			DocumentNumber: documentNumber,
+			Currency:       model.DefaultCurrency,
+			Status:         model.AccountActive,
 		}, nil)
 	m.EXPECT().
-		GetOperation(4).
+		GetOperation(gomock.Any(), 4).
 		Return(&model.OperationImpl{
 			OperationTypeID: 4,
 			Description:     "PAYMENT",
+			Direction:       model.DirectionCredit,
 		}, nil)
 	m.EXPECT().
-		CreateTransaction(*transaction).
-		Return(&model.TransactionImpl{
-			TransactionID:   &transactionID,
-			AccountID:       accountIdInt,
-			OperationTypeID: 4,
-			Amount:          5000.00,
-		}, nil)
+		SettlePayment(gomock.Any(), *transaction).
+		Return(result, nil)
 
 	// When.
 	// This is the handler func we want to test
@@ -151,3 +158,1442 @@ func TestHandleTransactionPost(t *testing.T) {
 	got := recorder.Body.String()
 	assert.Equal(t, expected, got)
 }
+
+func TestHandleTransactionPost_PurchaseIsStoredNegative(t *testing.T) {
+	// Given.
+	body := fmt.Sprintf("{\"account_id\":%d,\"operation_type_id\":1,\"amount\":50.00}", accountIdInt)
+	req, err := http.NewRequest("POST", "/", strings.NewReader(body))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+
+	ctrl := gomock.NewController(t)
+	m := mock_store.NewMockStore(ctrl)
+	m.EXPECT().
+		GetAccount(gomock.Any(), accountIdInt).
+		Return(model.NewAccount(&accountIdInt, documentNumber, model.DefaultCurrency), nil)
+	m.EXPECT().
+		GetOperation(gomock.Any(), 1).
+		Return(&model.OperationImpl{
+			OperationTypeID: 1,
+			Description:     "PURCHASE",
+			Direction:       model.DirectionDebit,
+		}, nil)
+	purchase := model.NewTransaction(nil, accountIdInt, 1, model.MustParseMoney("-50.00"), 0, nil)
+	m.EXPECT().
+		CreateDebit(gomock.Any(), *purchase).
+		Return(model.NewTransaction(&transactionID, accountIdInt, 1, model.MustParseMoney("-50.00"), model.MustParseMoney("-50.00"), nil), nil)
+
+	// When.
+	hf := http.HandlerFunc(HandleTransactionPost(m))
+	hf.ServeHTTP(recorder, req)
+
+	// Then.
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	expected := fmt.Sprintf("{\"transaction_id\":%d,\"account_id\":%d,\"operation_type_id\":1,\"amount\":-50.00,\"balance\":-50.00}\n", transactionID, accountIdInt)
+	assert.Equal(t, expected, recorder.Body.String())
+}
+
+func TestHandleTransactionPost_NegativePaymentIsRejected(t *testing.T) {
+	// Given.
+	body := fmt.Sprintf("{\"account_id\":%d,\"operation_type_id\":4,\"amount\":-50.00}", accountIdInt)
+	req, err := http.NewRequest("POST", "/", strings.NewReader(body))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+
+	ctrl := gomock.NewController(t)
+	m := mock_store.NewMockStore(ctrl)
+	m.EXPECT().
+		GetAccount(gomock.Any(), accountIdInt).
+		Return(model.NewAccount(&accountIdInt, documentNumber, model.DefaultCurrency), nil)
+	m.EXPECT().
+		GetOperation(gomock.Any(), 4).
+		Return(&model.OperationImpl{
+			OperationTypeID: 4,
+			Description:     "PAYMENT",
+			Direction:       model.DirectionCredit,
+		}, nil)
+
+	// When.
+	hf := http.HandlerFunc(HandleTransactionPost(m))
+	hf.ServeHTTP(recorder, req)
+
+	// Then.
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	var got ErrorResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Equal(t, CodeInvalidAmount, got.Code)
+	assert.Contains(t, got.Message, "wrong sign")
+}
+
+func TestHandleTransactionPost_ForeignCurrencyIsConverted(t *testing.T) {
+	// Given.
+	body := fmt.Sprintf("{\"account_id\":%d,\"operation_type_id\":1,\"amount\":100.00,\"currency\":\"EUR\"}", accountIdInt)
+	req, err := http.NewRequest("POST", "/", strings.NewReader(body))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+
+	ctrl := gomock.NewController(t)
+	m := mock_store.NewMockStore(ctrl)
+	m.EXPECT().
+		GetAccount(gomock.Any(), accountIdInt).
+		Return(model.NewAccount(&accountIdInt, documentNumber, model.DefaultCurrency), nil)
+	m.EXPECT().
+		GetOperation(gomock.Any(), 1).
+		Return(&model.OperationImpl{
+			OperationTypeID: 1,
+			Description:     "PURCHASE",
+			Direction:       model.DirectionDebit,
+		}, nil)
+	rate := model.FXRate{Base: "EUR", Quote: model.DefaultCurrency, Rate: model.MustParseRate("1.0825")}
+	m.EXPECT().
+		GetFXRate(gomock.Any(), model.Currency("EUR"), model.DefaultCurrency).
+		Return(&rate, nil)
+	purchase := model.NewTransaction(nil, accountIdInt, 1, model.MustParseMoney("-100.00"), 0, nil)
+	purchase.Currency = "EUR"
+	require.NoError(t, purchase.Convert(rate))
+	m.EXPECT().
+		CreateDebit(gomock.Any(), *purchase).
+		DoAndReturn(func(_ context.Context, transaction model.TransactionImpl) (*model.TransactionImpl, error) {
+			transaction.TransactionID, transaction.Balance = &transactionID, transaction.Amount
+			return &transaction, nil
+		})
+
+	// When.
+	hf := http.HandlerFunc(HandleTransactionPost(m))
+	hf.ServeHTTP(recorder, req)
+
+	// Then.
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	expected := fmt.Sprintf("{\"transaction_id\":%d,\"account_id\":%d,\"operation_type_id\":1,\"amount\":-108.25,\"balance\":-108.25,"+
+		"\"currency\":\"USD\",\"original_amount\":-100.00,\"original_currency\":\"EUR\",\"fx_rate\":1.0825}\n", transactionID, accountIdInt)
+	assert.Equal(t, expected, recorder.Body.String())
+}
+
+func TestHandleTransactionPost_NoFXRate(t *testing.T) {
+	// Given.
+	body := fmt.Sprintf("{\"account_id\":%d,\"operation_type_id\":1,\"amount\":100.00,\"currency\":\"JPY\"}", accountIdInt)
+	req, err := http.NewRequest("POST", "/", strings.NewReader(body))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+
+	ctrl := gomock.NewController(t)
+	m := mock_store.NewMockStore(ctrl)
+	m.EXPECT().
+		GetAccount(gomock.Any(), accountIdInt).
+		Return(model.NewAccount(&accountIdInt, documentNumber, model.DefaultCurrency), nil)
+	m.EXPECT().
+		GetOperation(gomock.Any(), 1).
+		Return(&model.OperationImpl{
+			OperationTypeID: 1,
+			Description:     "PURCHASE",
+			Direction:       model.DirectionDebit,
+		}, nil)
+	m.EXPECT().
+		GetFXRate(gomock.Any(), model.Currency("JPY"), model.DefaultCurrency).
+		Return(nil, fmt.Errorf("%w: no rate from JPY to USD", store.ErrFXRateNotFound))
+
+	// When.
+	hf := http.HandlerFunc(HandleTransactionPost(m))
+	hf.ServeHTTP(recorder, req)
+
+	// Then.
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	var got ErrorResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Equal(t, CodeFXRateNotFound, got.Code)
+}
+
+func TestHandleTransactionPost_BlockedAccount(t *testing.T) {
+	// Given.
+	body := fmt.Sprintf("{\"account_id\":%d,\"operation_type_id\":1,\"amount\":50.00}", accountIdInt)
+	req, err := http.NewRequest("POST", "/", strings.NewReader(body))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+
+	ctrl := gomock.NewController(t)
+	m := mock_store.NewMockStore(ctrl)
+	account := model.NewAccount(&accountIdInt, documentNumber, model.DefaultCurrency)
+	account.Status = model.AccountBlocked
+	m.EXPECT().
+		GetAccount(gomock.Any(), accountIdInt).
+		Return(account, nil)
+	m.EXPECT().
+		GetOperation(gomock.Any(), 1).
+		Return(&model.OperationImpl{
+			OperationTypeID: 1,
+			Description:     "PURCHASE",
+			Direction:       model.DirectionDebit,
+		}, nil)
+	m.EXPECT().
+		CreateDebit(gomock.Any(), gomock.Any()).
+		Return(nil, fmt.Errorf("%w: account %d accepts only credits", model.ErrAccountBlocked, accountIdInt))
+
+	// When.
+	hf := http.HandlerFunc(HandleTransactionPost(m))
+	hf.ServeHTTP(recorder, req)
+
+	// Then.
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	var got ErrorResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Equal(t, CodeAccountBlocked, got.Code)
+}
+
+func TestHandleGetTransaction(t *testing.T) {
+	// Given.
+	req, err := http.NewRequest("GET", "/", nil)
+	require.NoError(t, err)
+
+	chiCtx := chi.NewRouteContext()
+	reqWithCtx := req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+	chiCtx.URLParams.Add("transactionId", fmt.Sprint(transactionID))
+
+	recorder := httptest.NewRecorder()
+
+	eventDate := time.Date(2025, 10, 27, 9, 0, 0, 0, time.UTC)
+	ctrl := gomock.NewController(t)
+	m := mock_store.NewMockStore(ctrl)
+	m.EXPECT().
+		GetTransaction(gomock.Any(), transactionID).
+		Return(model.NewTransaction(&transactionID, accountIdInt, 1, model.MustParseMoney("-50.00"), model.MustParseMoney("-50.00"), &eventDate), nil)
+
+	// When.
+	hf := http.HandlerFunc(HandleGetTransaction(m))
+	hf.ServeHTTP(recorder, reqWithCtx)
+
+	// Then.
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	expected := fmt.Sprintf("{\"transaction_id\":%d,\"account_id\":%d,\"operation_type_id\":1,\"amount\":-50.00,\"balance\":-50.00,\"event_date\":\"2025-10-27T09:00:00Z\"}\n", transactionID, accountIdInt)
+	assert.Equal(t, expected, recorder.Body.String())
+}
+
+func TestHandleListAccountTransactions(t *testing.T) {
+	// Given.
+	req, err := http.NewRequest("GET", "/?operation_type_id=1&from=2025-10-01T00:00:00Z&sort=desc&limit=1", nil)
+	require.NoError(t, err)
+
+	chiCtx := chi.NewRouteContext()
+	reqWithCtx := req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+	chiCtx.URLParams.Add("accountId", accountId)
+
+	recorder := httptest.NewRecorder()
+
+	from := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	eventDate := time.Date(2025, 10, 27, 9, 0, 0, 0, time.UTC)
+	ctrl := gomock.NewController(t)
+	m := mock_store.NewMockStore(ctrl)
+	m.EXPECT().
+		GetAccount(gomock.Any(), accountIdInt).
+		Return(model.NewAccount(&accountIdInt, documentNumber, model.DefaultCurrency), nil)
+	m.EXPECT().
+		ListTransactions(gomock.Any(), accountIdInt, model.TransactionFilter{
+			OperationTypeID: model.IntToPtr(1),
+			From:            &from,
+			Descending:      true,
+			Limit:           2,
+		}).
+		Return(model.Transactions{
+			*model.NewTransaction(model.IntToPtr(2), accountIdInt, 1, -100, -100, &eventDate),
+			*model.NewTransaction(model.IntToPtr(1), accountIdInt, 1, -100, -100, &eventDate),
+		}, nil)
+
+	// When.
+	hf := http.HandlerFunc(HandleListAccountTransactions(m))
+	hf.ServeHTTP(recorder, reqWithCtx)
+
+	// Then.
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var page model.TransactionPage
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &page))
+	require.Len(t, page.Transactions, 1)
+	assert.Equal(t, 2, *page.Transactions[0].TransactionID)
+	assert.Equal(t, model.Cursor{EventDate: eventDate, TransactionID: 2}.Encode(), page.NextCursor)
+}
+
+func TestHandleListAccountTransactions_BadQuery(t *testing.T) {
+	for _, query := range []string{"limit=0", "limit=201", "sort=sideways", "from=yesterday", "cursor=abc", "operation_type_id=x"} {
+		t.Run(query, func(t *testing.T) {
+			// Given.
+			req, err := http.NewRequest("GET", "/?"+query, nil)
+			require.NoError(t, err)
+
+			chiCtx := chi.NewRouteContext()
+			reqWithCtx := req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+			chiCtx.URLParams.Add("accountId", accountId)
+
+			recorder := httptest.NewRecorder()
+
+			ctrl := gomock.NewController(t)
+			m := mock_store.NewMockStore(ctrl)
+
+			// When.
+			hf := http.HandlerFunc(HandleListAccountTransactions(m))
+			hf.ServeHTTP(recorder, reqWithCtx)
+
+			// Then.
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		})
+	}
+}
+
+func TestHandleGetAccount_NotFound(t *testing.T) {
+	for _, tt := range []struct {
+		accept      string
+		contentType string
+		problem     bool
+	}{
+		{accept: "", contentType: "application/json"},
+		{accept: "application/problem+json", contentType: "application/problem+json", problem: true},
+	} {
+		t.Run(tt.contentType, func(t *testing.T) {
+			// Given.
+			req, err := http.NewRequest("GET", "/", nil)
+			require.NoError(t, err)
+			req.Header.Set("Accept", tt.accept)
+
+			chiCtx := chi.NewRouteContext()
+			reqWithCtx := req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+			chiCtx.URLParams.Add("accountId", accountId)
+
+			recorder := httptest.NewRecorder()
+
+			ctrl := gomock.NewController(t)
+			m := mock_store.NewMockStore(ctrl)
+			m.EXPECT().
+				GetAccount(gomock.Any(), accountIdInt).
+				Return(&model.AccountImpl{}, fmt.Errorf("%w: no account with id %d", store.ErrAccountNotFound, accountIdInt))
+
+			// When.
+			hf := http.HandlerFunc(HandleGetAccount(m))
+			hf.ServeHTTP(recorder, reqWithCtx)
+
+			// Then.
+			assert.Equal(t, http.StatusNotFound, recorder.Code)
+			assert.Equal(t, tt.contentType, recorder.Header().Get("Content-Type"))
+			var got ErrorResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+			assert.Equal(t, CodeAccountNotFound, got.Code)
+			assert.Contains(t, got.Message, "no account with id 123")
+			if tt.problem {
+				assert.Equal(t, http.StatusNotFound, got.Status)
+				assert.Equal(t, "Not Found", got.Title)
+			} else {
+				assert.Zero(t, got.Status)
+			}
+		})
+	}
+}
+
+func TestHandleGetAccount_InternalErrorHidesDetails(t *testing.T) {
+	// Given.
+	req, err := http.NewRequest("GET", "/", nil)
+	require.NoError(t, err)
+
+	chiCtx := chi.NewRouteContext()
+	reqWithCtx := req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+	chiCtx.URLParams.Add("accountId", accountId)
+
+	recorder := httptest.NewRecorder()
+
+	ctrl := gomock.NewController(t)
+	m := mock_store.NewMockStore(ctrl)
+	m.EXPECT().
+		GetAccount(gomock.Any(), accountIdInt).
+		Return(nil, fmt.Errorf("query error: dial tcp 10.0.0.1:3306: connection refused"))
+
+	// When.
+	hf := http.HandlerFunc(HandleGetAccount(m))
+	hf.ServeHTTP(recorder, reqWithCtx)
+
+	// Then.
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	var got ErrorResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Equal(t, CodeInternal, got.Code)
+	assert.NotContains(t, got.Message, "10.0.0.1")
+}
+
+func TestHandleGetAccount_Timeout(t *testing.T) {
+	for name, test := range map[string]struct {
+		timeout time.Duration
+		cancel  bool
+		status  int
+		code    string
+	}{
+		"deadline exceeded": {timeout: 10 * time.Millisecond, status: http.StatusGatewayTimeout, code: CodeTimeout},
+		"client cancelled":  {cancel: true, status: StatusClientClosedRequest, code: CodeRequestCancelled},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Given.
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			req, err := http.NewRequestWithContext(ctx, "GET", "/accounts/"+accountId, nil)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+
+			ctrl := gomock.NewController(t)
+			m := mock_store.NewMockStore(ctrl)
+			m.EXPECT().
+				GetAccount(gomock.Any(), accountIdInt).
+				DoAndReturn(func(ctx context.Context, _ int) (*model.AccountImpl, error) {
+					// A slow query that only ends when the request does.
+					if test.cancel {
+						cancel()
+					}
+					<-ctx.Done()
+					return nil, fmt.Errorf("query error: %w", ctx.Err())
+				})
+
+			// When.
+			r := NewRouter(m, config.ServerConfig{RequestTimeout: test.timeout})
+			r.ServeHTTP(recorder, req)
+
+			// Then.
+			assert.Equal(t, test.status, recorder.Code)
+			var got ErrorResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+			assert.Equal(t, test.code, got.Code)
+		})
+	}
+}
+
+func TestHandleTransactionPost_InvalidBody(t *testing.T) {
+	for body, code := range map[string]string{
+		`{"account_id":`:       CodeInvalidJSON,
+		`{"amount":"12.345"}`:  CodeInvalidAmount,
+		`{"account_id":"abc"}`: CodeInvalidJSON,
+	} {
+		t.Run(body, func(t *testing.T) {
+			// Given.
+			req, err := http.NewRequest("POST", "/", strings.NewReader(body))
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+
+			ctrl := gomock.NewController(t)
+			m := mock_store.NewMockStore(ctrl)
+
+			// When.
+			hf := http.HandlerFunc(HandleTransactionPost(m))
+			hf.ServeHTTP(recorder, req)
+
+			// Then.
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			var got ErrorResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+			assert.Equal(t, code, got.Code)
+		})
+	}
+}
+
+func TestHandleAccountPost_Invalid(t *testing.T) {
+	for body, want := range map[string]struct {
+		status int
+		code   string
+	}{
+		`{"document_number":""}`:                        {http.StatusUnprocessableEntity, CodeValidationFailed},
+		`{"document_number":"12a"}`:                     {http.StatusUnprocessableEntity, CodeValidationFailed},
+		`{"document_number":"123","nickname":"x"}`:      {http.StatusBadRequest, CodeInvalidJSON},
+		`{"document_number":"123"} {"document_number"}`: {http.StatusBadRequest, CodeInvalidJSON},
+	} {
+		t.Run(body, func(t *testing.T) {
+			// Given.
+			req, err := http.NewRequest("POST", "/", strings.NewReader(body))
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+
+			ctrl := gomock.NewController(t)
+			m := mock_store.NewMockStore(ctrl)
+
+			// When.
+			hf := http.HandlerFunc(HandleAccountPost(m))
+			hf.ServeHTTP(recorder, req)
+
+			// Then.
+			assert.Equal(t, want.status, recorder.Code)
+			var got ErrorResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+			assert.Equal(t, want.code, got.Code)
+		})
+	}
+}
+
+func TestHandleTransactionPost_ValidationDetails(t *testing.T) {
+	// Given.
+	body := `{"transaction_id":1,"account_id":123,"operation_type_id":1,"amount":0,"balance":5}`
+	req, err := http.NewRequest("POST", "/", strings.NewReader(body))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+
+	ctrl := gomock.NewController(t)
+	m := mock_store.NewMockStore(ctrl)
+
+	// When.
+	hf := http.HandlerFunc(HandleTransactionPost(m))
+	hf.ServeHTTP(recorder, req)
+
+	// Then.
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	var got ErrorResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Equal(t, CodeValidationFailed, got.Code)
+	assert.Equal(t, []ErrorDetail{
+		{Field: "transaction_id", Message: "is set by the server and must be left out"},
+		{Field: "amount", Message: "must not be zero"},
+		{Field: "balance", Message: "is set by the server and must be left out"},
+	}, got.Details)
+}
+
+func TestHandleTransactionPost_CreditLimitExceeded(t *testing.T) {
+	// Given.
+	body := fmt.Sprintf("{\"account_id\":%d,\"operation_type_id\":1,\"amount\":50.00}", accountIdInt)
+	req, err := http.NewRequest("POST", "/", strings.NewReader(body))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+
+	ctrl := gomock.NewController(t)
+	m := mock_store.NewMockStore(ctrl)
+	m.EXPECT().
+		GetAccount(gomock.Any(), accountIdInt).
+		Return(model.NewAccount(&accountIdInt, documentNumber, model.DefaultCurrency), nil)
+	m.EXPECT().
+		GetOperation(gomock.Any(), 1).
+		Return(&model.OperationImpl{
+			OperationTypeID: 1,
+			Description:     "PURCHASE",
+			Direction:       model.DirectionDebit,
+		}, nil)
+	m.EXPECT().
+		CreateDebit(gomock.Any(), gomock.Any()).
+		Return(nil, fmt.Errorf("%w: 50.00 is more than the available credit of 20.00", model.ErrCreditLimitExceeded))
+
+	// When.
+	hf := http.HandlerFunc(HandleTransactionPost(m))
+	hf.ServeHTTP(recorder, req)
+
+	// Then.
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	var got ErrorResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Equal(t, CodeCreditLimitExceeded, got.Code)
+	assert.Contains(t, got.Message, "available credit of 20.00")
+}
+
+func TestHandleCreditLimitPut(t *testing.T) {
+	// Given.
+	req, err := http.NewRequest("PUT", "/", strings.NewReader(`{"credit_limit":1500.00,"reason":"annual review"}`))
+	require.NoError(t, err)
+
+	chiCtx := chi.NewRouteContext()
+	reqWithCtx := req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+	chiCtx.URLParams.Add("accountId", accountId)
+
+	recorder := httptest.NewRecorder()
+
+	ctrl := gomock.NewController(t)
+	m := mock_store.NewMockStore(ctrl)
+	changedAt := time.Date(2025, 10, 27, 12, 0, 0, 0, time.UTC)
+	m.EXPECT().
+		UpdateCreditLimit(gomock.Any(), model.CreditLimitChange{
+			AccountID: accountIdInt,
+			NewLimit:  model.MustParseMoney("1500.00"),
+			Reason:    "annual review",
+		}).
+		Return(&model.CreditLimitChange{
+			ChangeID:  model.IntToPtr(1),
+			AccountID: accountIdInt,
+			OldLimit:  model.MoneyToPtr(model.MustParseMoney("1000.00")),
+			NewLimit:  model.MustParseMoney("1500.00"),
+			Reason:    "annual review",
+			ChangedAt: &changedAt,
+		}, nil)
+
+	// When.
+	hf := http.HandlerFunc(HandleCreditLimitPut(m))
+	hf.ServeHTTP(recorder, reqWithCtx)
+
+	// Then.
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	expected := fmt.Sprintf("{\"change_id\":1,\"account_id\":%d,\"old_limit\":1000.00,\"new_limit\":1500.00,\"reason\":\"annual review\",\"changed_at\":\"2025-10-27T12:00:00Z\"}\n", accountIdInt)
+	assert.Equal(t, expected, recorder.Body.String())
+}
+
+func TestHandleCreditLimitPut_Invalid(t *testing.T) {
+	// Given.
+	req, err := http.NewRequest("PUT", "/", strings.NewReader(`{"credit_limit":-1.00,"reason":" "}`))
+	require.NoError(t, err)
+
+	chiCtx := chi.NewRouteContext()
+	reqWithCtx := req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+	chiCtx.URLParams.Add("accountId", accountId)
+
+	recorder := httptest.NewRecorder()
+
+	ctrl := gomock.NewController(t)
+	m := mock_store.NewMockStore(ctrl)
+
+	// When.
+	hf := http.HandlerFunc(HandleCreditLimitPut(m))
+	hf.ServeHTTP(recorder, reqWithCtx)
+
+	// Then.
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	var got ErrorResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Equal(t, CodeValidationFailed, got.Code)
+	assert.Equal(t, []string{"credit_limit", "reason"}, []string{got.Details[0].Field, got.Details[1].Field})
+}
+
+func TestHandleListCreditLimitChanges(t *testing.T) {
+	// Given.
+	req, err := http.NewRequest("GET", "/", nil)
+	require.NoError(t, err)
+
+	chiCtx := chi.NewRouteContext()
+	reqWithCtx := req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+	chiCtx.URLParams.Add("accountId", accountId)
+
+	recorder := httptest.NewRecorder()
+
+	ctrl := gomock.NewController(t)
+	m := mock_store.NewMockStore(ctrl)
+	m.EXPECT().
+		GetAccount(gomock.Any(), accountIdInt).
+		Return(model.NewAccount(&accountIdInt, documentNumber, model.DefaultCurrency), nil)
+	m.EXPECT().
+		ListCreditLimitChanges(gomock.Any(), accountIdInt).
+		Return([]model.CreditLimitChange{}, nil)
+
+	// When.
+	hf := http.HandlerFunc(HandleListCreditLimitChanges(m))
+	hf.ServeHTTP(recorder, reqWithCtx)
+
+	// Then.
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "{\"changes\":[]}\n", recorder.Body.String())
+}
+
+func TestHandleAccountBlock(t *testing.T) {
+	// Given.
+	req, err := http.NewRequest("POST", "/", strings.NewReader(`{"reason":"fraud report"}`))
+	require.NoError(t, err)
+
+	chiCtx := chi.NewRouteContext()
+	reqWithCtx := req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+	chiCtx.URLParams.Add("accountId", accountId)
+
+	recorder := httptest.NewRecorder()
+
+	ctrl := gomock.NewController(t)
+	m := mock_store.NewMockStore(ctrl)
+	changedAt := time.Date(2025, 10, 27, 12, 0, 0, 0, time.UTC)
+	m.EXPECT().
+		UpdateAccountStatus(gomock.Any(), model.AccountStatusChange{
+			AccountID: accountIdInt,
+			NewStatus: model.AccountBlocked,
+			Reason:    "fraud report",
+		}).
+		Return(&model.AccountStatusChange{
+			ChangeID:  model.IntToPtr(1),
+			AccountID: accountIdInt,
+			OldStatus: model.AccountActive,
+			NewStatus: model.AccountBlocked,
+			Reason:    "fraud report",
+			ChangedAt: &changedAt,
+		}, nil)
+
+	// When.
+	hf := http.HandlerFunc(HandleAccountBlock(m))
+	hf.ServeHTTP(recorder, reqWithCtx)
+
+	// Then.
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	expected := fmt.Sprintf("{\"change_id\":1,\"account_id\":%d,\"old_status\":\"ACTIVE\",\"new_status\":\"BLOCKED\",\"reason\":\"fraud report\",\"changed_at\":\"2025-10-27T12:00:00Z\"}\n", accountIdInt)
+	assert.Equal(t, expected, recorder.Body.String())
+}
+
+func TestHandleAccountStatus_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(store.Store) http.HandlerFunc
+		status  model.AccountStatus
+		body    string
+		err     error
+		code    int
+		errCode string
+	}{
+		{name: "no reason", handler: HandleAccountBlock, body: `{"reason":""}`, code: http.StatusUnprocessableEntity, errCode: CodeValidationFailed},
+		{name: "balance", handler: HandleAccountClose, status: model.AccountClosed, body: `{"reason":"customer request"}`,
+			err: fmt.Errorf("%w: account 123 has a balance of -5.00", model.ErrBalanceNotZero), code: http.StatusUnprocessableEntity, errCode: CodeBalanceNotZero},
+		{name: "pending authorizations", handler: HandleAccountClose, status: model.AccountClosed, body: `{"reason":"customer request"}`,
+			err: fmt.Errorf("%w: account 123 holds 25.00 for pending authorizations", model.ErrAuthorizationsPending), code: http.StatusUnprocessableEntity, errCode: CodeAuthorizationsPending},
+		{name: "transition", handler: HandleAccountUnblock, status: model.AccountActive, body: `{"reason":"cleared"}`,
+			err: fmt.Errorf("%w: account 123 is CLOSED and cannot become ACTIVE", model.ErrInvalidStatusTransition), code: http.StatusConflict, errCode: CodeInvalidStatusTransition},
+		{name: "not found", handler: HandleAccountUnblock, status: model.AccountActive, body: `{"reason":"cleared"}`,
+			err: fmt.Errorf("%w: no account with id 123", store.ErrAccountNotFound), code: http.StatusNotFound, errCode: CodeAccountNotFound},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Given.
+			req, err := http.NewRequest("POST", "/", strings.NewReader(test.body))
+			require.NoError(t, err)
+
+			chiCtx := chi.NewRouteContext()
+			reqWithCtx := req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+			chiCtx.URLParams.Add("accountId", accountId)
+
+			recorder := httptest.NewRecorder()
+
+			ctrl := gomock.NewController(t)
+			m := mock_store.NewMockStore(ctrl)
+			if test.err != nil {
+				m.EXPECT().
+					UpdateAccountStatus(gomock.Any(), gomock.Cond(func(change model.AccountStatusChange) bool {
+						return change.NewStatus == test.status
+					})).
+					Return(nil, test.err)
+			}
+
+			// When.
+			hf := http.HandlerFunc(test.handler(m))
+			hf.ServeHTTP(recorder, reqWithCtx)
+
+			// Then.
+			assert.Equal(t, test.code, recorder.Code)
+			var got ErrorResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+			assert.Equal(t, test.errCode, got.Code)
+		})
+	}
+}
+
+func TestHandleListAccountStatusChanges(t *testing.T) {
+	// Given.
+	req, err := http.NewRequest("GET", "/", nil)
+	require.NoError(t, err)
+
+	chiCtx := chi.NewRouteContext()
+	reqWithCtx := req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+	chiCtx.URLParams.Add("accountId", accountId)
+
+	recorder := httptest.NewRecorder()
+
+	ctrl := gomock.NewController(t)
+	m := mock_store.NewMockStore(ctrl)
+	m.EXPECT().
+		GetAccount(gomock.Any(), accountIdInt).
+		Return(model.NewAccount(&accountIdInt, documentNumber, model.DefaultCurrency), nil)
+	m.EXPECT().
+		ListAccountStatusChanges(gomock.Any(), accountIdInt).
+		Return([]model.AccountStatusChange{}, nil)
+
+	// When.
+	hf := http.HandlerFunc(HandleListAccountStatusChanges(m))
+	hf.ServeHTTP(recorder, reqWithCtx)
+
+	// Then.
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "{\"changes\":[]}\n", recorder.Body.String())
+}
+
+func TestHandleFXRatesPut(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{name: "json", contentType: "application/json", body: `{"rates":[{"base_currency":"EUR","quote_currency":"USD","rate":1.0825}]}`},
+		{name: "csv", contentType: "text/csv; charset=utf-8", body: "base_currency,quote_currency,rate\nEUR,USD,1.0825\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Given.
+			req, err := http.NewRequest("PUT", "/", strings.NewReader(test.body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", test.contentType)
+
+			recorder := httptest.NewRecorder()
+
+			ctrl := gomock.NewController(t)
+			m := mock_store.NewMockStore(ctrl)
+			updatedAt := time.Date(2025, 10, 27, 12, 0, 0, 0, time.UTC)
+			m.EXPECT().
+				UpdateFXRates(gomock.Any(), []model.FXRate{{Base: "EUR", Quote: "USD", Rate: model.MustParseRate("1.0825")}}).
+				Return([]model.FXRate{{Base: "EUR", Quote: "USD", Rate: model.MustParseRate("1.0825"), UpdatedAt: &updatedAt}}, nil)
+
+			// When.
+			hf := http.HandlerFunc(HandleFXRatesPut(m))
+			hf.ServeHTTP(recorder, req)
+
+			// Then.
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			expected := "{\"rates\":[{\"base_currency\":\"EUR\",\"quote_currency\":\"USD\",\"rate\":1.0825,\"updated_at\":\"2025-10-27T12:00:00Z\"}]}\n"
+			assert.Equal(t, expected, recorder.Body.String())
+		})
+	}
+}
+
+func TestHandleFXRatesPut_Invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+		code        string
+	}{
+		{name: "invalid csv", contentType: "text/csv", body: "EUR,USD,1.0825\n", status: http.StatusBadRequest, code: CodeBadRequest},
+		{name: "invalid rate", contentType: "application/json", body: `{"rates":[{"base_currency":"EUR","quote_currency":"USD","rate":"one"}]}`, status: http.StatusBadRequest, code: CodeBadRequest},
+		{name: "same currency", contentType: "application/json", body: `{"rates":[{"base_currency":"EUR","quote_currency":"EUR","rate":1}]}`, status: http.StatusUnprocessableEntity, code: CodeValidationFailed},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Given.
+			req, err := http.NewRequest("PUT", "/", strings.NewReader(test.body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", test.contentType)
+
+			recorder := httptest.NewRecorder()
+
+			ctrl := gomock.NewController(t)
+			m := mock_store.NewMockStore(ctrl)
+
+			// When.
+			hf := http.HandlerFunc(HandleFXRatesPut(m))
+			hf.ServeHTTP(recorder, req)
+
+			// Then.
+			assert.Equal(t, test.status, recorder.Code)
+			var got ErrorResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+			assert.Equal(t, test.code, got.Code)
+		})
+	}
+}
+
+func TestHandleListFXRates(t *testing.T) {
+	// Given.
+	req, err := http.NewRequest("GET", "/", nil)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+
+	ctrl := gomock.NewController(t)
+	m := mock_store.NewMockStore(ctrl)
+	m.EXPECT().
+		ListFXRates(gomock.Any()).
+		Return([]model.FXRate{}, nil)
+
+	// When.
+	hf := http.HandlerFunc(HandleListFXRates(m))
+	hf.ServeHTTP(recorder, req)
+
+	// Then.
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "{\"rates\":[]}\n", recorder.Body.String())
+}
+
+func TestHandleGetAccountBalance(t *testing.T) {
+	// Given.
+	req, err := http.NewRequest("GET", "/", nil)
+	require.NoError(t, err)
+
+	chiCtx := chi.NewRouteContext()
+	reqWithCtx := req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+	chiCtx.URLParams.Add("accountId", accountId)
+
+	recorder := httptest.NewRecorder()
+
+	ctrl := gomock.NewController(t)
+	m := mock_store.NewMockStore(ctrl)
+	m.EXPECT().
+		GetBalance(gomock.Any(), accountIdInt).
+		Return(&model.AccountBalance{
+			AccountID:       accountIdInt,
+			Outstanding:     model.MustParseMoney("35.50"),
+			Debts:           []model.OperationDebt{{OperationTypeID: 1, Outstanding: model.MustParseMoney("35.50")}},
+			CreditLimit:     model.MoneyToPtr(model.MustParseMoney("100.00")),
+			AvailableCredit: model.MoneyToPtr(model.MustParseMoney("64.50")),
+		}, nil)
+
+	// When.
+	hf := http.HandlerFunc(HandleGetAccountBalance(m))
+	hf.ServeHTTP(recorder, reqWithCtx)
+
+	// Then.
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	expected := fmt.Sprintf("{\"account_id\":%d,\"outstanding\":35.50,\"debts\":[{\"operation_type_id\":1,\"outstanding\":35.50}],\"payment_credit\":0.00,\"scheduled\":0.00,\"held\":0.00,\"credit_limit\":100.00,\"available_credit\":64.50}\n", accountIdInt)
+	assert.Equal(t, expected, recorder.Body.String())
+}
+
+func TestHandleGetAccountStatement(t *testing.T) {
+	// Given.
+	req, err := http.NewRequest("GET", "/?from=2025-10-01T00:00:00Z&to=2025-11-01T00:00:00Z", nil)
+	require.NoError(t, err)
+
+	chiCtx := chi.NewRouteContext()
+	reqWithCtx := req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+	chiCtx.URLParams.Add("accountId", accountId)
+
+	recorder := httptest.NewRecorder()
+
+	ctrl := gomock.NewController(t)
+	m := mock_store.NewMockStore(ctrl)
+	from := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+	eventDate := time.Date(2025, 10, 27, 12, 0, 0, 0, time.UTC)
+	transactions := model.Transactions{*model.NewTransaction(&transactionID, accountIdInt, 4, model.MustParseMoney("60.00"), 0, &eventDate)}
+	m.EXPECT().
+		GetStatement(gomock.Any(), accountIdInt, &from, &to).
+		Return(model.NewStatement(accountIdInt, &from, &to, model.MustParseMoney("-50.00"), transactions), nil)
+
+	// When.
+	hf := http.HandlerFunc(HandleGetAccountStatement(m))
+	hf.ServeHTTP(recorder, reqWithCtx)
+
+	// Then.
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var got model.Statement
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Equal(t, model.MustParseMoney("-50.00"), got.OpeningBalance)
+	assert.Equal(t, model.MustParseMoney("10.00"), got.ClosingBalance)
+	assert.Len(t, got.Transactions, 1)
+}
+
+func TestHandleGetAccountStatement_BadPeriod(t *testing.T) {
+	for _, query := range []string{
+		"from=yesterday",
+		"from=2025-11-01T00:00:00Z&to=2025-10-01T00:00:00Z",
+		"from=2025-11-01T00:00:00Z&to=2025-11-01T00:00:00Z",
+	} {
+		t.Run(query, func(t *testing.T) {
+			// Given.
+			req, err := http.NewRequest("GET", "/?"+query, nil)
+			require.NoError(t, err)
+
+			chiCtx := chi.NewRouteContext()
+			reqWithCtx := req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+			chiCtx.URLParams.Add("accountId", accountId)
+
+			recorder := httptest.NewRecorder()
+
+			ctrl := gomock.NewController(t)
+			m := mock_store.NewMockStore(ctrl)
+
+			// When.
+			hf := http.HandlerFunc(HandleGetAccountStatement(m))
+			hf.ServeHTTP(recorder, reqWithCtx)
+
+			// Then.
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			var got ErrorResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+			assert.Equal(t, CodeBadRequest, got.Code)
+		})
+	}
+}
+
+func TestHandleTransactionReversal(t *testing.T) {
+	// Given.
+	req, err := http.NewRequest("POST", "/", strings.NewReader(`{"amount":20.00}`))
+	require.NoError(t, err)
+
+	chiCtx := chi.NewRouteContext()
+	reqWithCtx := req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+	chiCtx.URLParams.Add("transactionId", fmt.Sprint(transactionID))
+
+	recorder := httptest.NewRecorder()
+
+	ctrl := gomock.NewController(t)
+	m := mock_store.NewMockStore(ctrl)
+	refund := model.NewTransaction(model.IntToPtr(112), accountIdInt, model.OperationTypeRefund, model.MustParseMoney("20.00"), 0, nil)
+	refund.OriginalTransactionID = &transactionID
+	m.EXPECT().
+		ReverseTransaction(gomock.Any(), transactionID, model.MoneyToPtr(model.MustParseMoney("20.00"))).
+		Return(refund, nil)
+
+	// When.
+	hf := http.HandlerFunc(HandleTransactionReversal(m))
+	hf.ServeHTTP(recorder, reqWithCtx)
+
+	// Then.
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	expected := fmt.Sprintf("{\"transaction_id\":112,\"account_id\":%d,\"operation_type_id\":5,\"amount\":20.00,\"balance\":0.00,\"original_transaction_id\":%d}\n", accountIdInt, transactionID)
+	assert.Equal(t, expected, recorder.Body.String())
+}
+
+func TestHandleTransactionReversal_Errors(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		body   string
+		err    error
+		status int
+		code   string
+	}{
+		{name: "already reversed", body: `{}`, err: fmt.Errorf("%w: transaction 111 is fully reversed", model.ErrAlreadyReversed), status: http.StatusConflict, code: CodeAlreadyReversed},
+		{name: "reversal of a reversal", body: `{}`, err: fmt.Errorf("%w: transaction 111 is itself a reversal", model.ErrNotReversible), status: http.StatusUnprocessableEntity, code: CodeNotReversible},
+		{name: "more than is left", body: `{"amount":5.01}`, err: fmt.Errorf("%w: only 5.00 of transaction 111 is left to reverse", model.ErrReversalExceedsAmount), status: http.StatusUnprocessableEntity, code: CodeInvalidAmount},
+		{name: "negative amount", body: `{"amount":-5.00}`, status: http.StatusUnprocessableEntity, code: CodeValidationFailed},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given.
+			req, err := http.NewRequest("POST", "/", strings.NewReader(tt.body))
+			require.NoError(t, err)
+
+			chiCtx := chi.NewRouteContext()
+			reqWithCtx := req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+			chiCtx.URLParams.Add("transactionId", fmt.Sprint(transactionID))
+
+			recorder := httptest.NewRecorder()
+
+			ctrl := gomock.NewController(t)
+			m := mock_store.NewMockStore(ctrl)
+			if tt.err != nil {
+				m.EXPECT().
+					ReverseTransaction(gomock.Any(), transactionID, gomock.Any()).
+					Return(nil, tt.err)
+			}
+
+			// When.
+			hf := http.HandlerFunc(HandleTransactionReversal(m))
+			hf.ServeHTTP(recorder, reqWithCtx)
+
+			// Then.
+			assert.Equal(t, tt.status, recorder.Code)
+			var got ErrorResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+			assert.Equal(t, tt.code, got.Code)
+		})
+	}
+}
+
+func TestHandleAuthorizationPost(t *testing.T) {
+	// Given.
+	body := fmt.Sprintf("{\"account_id\":%d,\"operation_type_id\":1,\"amount\":50.00}", accountIdInt)
+	req, err := http.NewRequest("POST", "/", strings.NewReader(body))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+
+	ctrl := gomock.NewController(t)
+	m := mock_store.NewMockStore(ctrl)
+	m.EXPECT().
+		GetAccount(gomock.Any(), accountIdInt).
+		Return(model.NewAccount(&accountIdInt, documentNumber, model.DefaultCurrency), nil)
+	m.EXPECT().
+		GetOperation(gomock.Any(), 1).
+		Return(&model.OperationImpl{
+			OperationTypeID: 1,
+			Description:     "PURCHASE",
+			Direction:       model.DirectionDebit,
+		}, nil)
+	m.EXPECT().
+		CreateAuthorization(gomock.Any(), gomock.Cond(func(a model.AuthorizationImpl) bool {
+			// The amount is held with the debit's sign until the TTL runs out.
+			return a.Amount == model.MustParseMoney("-50.00") && time.Until(*a.ExpiresAt) > 59*time.Minute
+		})).
+		DoAndReturn(func(_ context.Context, a model.AuthorizationImpl) (*model.AuthorizationImpl, error) {
+			a.AuthorizationID = model.IntToPtr(7)
+			a.Status = model.AuthorizationPending
+			a.ExpiresAt = nil
+			return &a, nil
+		})
+
+	// When.
+	hf := http.HandlerFunc(HandleAuthorizationPost(m, time.Hour))
+	hf.ServeHTTP(recorder, req)
+
+	// Then.
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	expected := fmt.Sprintf("{\"authorization_id\":7,\"account_id\":%d,\"operation_type_id\":1,\"amount\":-50.00,\"status\":\"PENDING\"}\n", accountIdInt)
+	assert.Equal(t, expected, recorder.Body.String())
+}
+
+func TestHandleAuthorizationPost_NotDebit(t *testing.T) {
+	// Given.
+	body := fmt.Sprintf("{\"account_id\":%d,\"operation_type_id\":4,\"amount\":50.00}", accountIdInt)
+	req, err := http.NewRequest("POST", "/", strings.NewReader(body))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+
+	ctrl := gomock.NewController(t)
+	m := mock_store.NewMockStore(ctrl)
+	m.EXPECT().
+		GetAccount(gomock.Any(), accountIdInt).
+		Return(model.NewAccount(&accountIdInt, documentNumber, model.DefaultCurrency), nil)
+	m.EXPECT().
+		GetOperation(gomock.Any(), 4).
+		Return(&model.OperationImpl{
+			OperationTypeID: 4,
+			Description:     "PAYMENT",
+			Direction:       model.DirectionCredit,
+		}, nil)
+
+	// When.
+	hf := http.HandlerFunc(HandleAuthorizationPost(m, time.Hour))
+	hf.ServeHTTP(recorder, req)
+
+	// Then.
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	var got ErrorResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Equal(t, CodeNotDebit, got.Code)
+}
+
+func TestHandleAuthorizationCapture(t *testing.T) {
+	// Given.
+	req, err := http.NewRequest("POST", "/", strings.NewReader(`{"amount":45.00}`))
+	require.NoError(t, err)
+
+	chiCtx := chi.NewRouteContext()
+	reqWithCtx := req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+	chiCtx.URLParams.Add("authorizationId", "7")
+
+	recorder := httptest.NewRecorder()
+
+	ctrl := gomock.NewController(t)
+	m := mock_store.NewMockStore(ctrl)
+	m.EXPECT().
+		CaptureAuthorization(gomock.Any(), 7, model.MoneyToPtr(model.MustParseMoney("45.00"))).
+		Return(model.NewTransaction(model.IntToPtr(112), accountIdInt, 1, model.MustParseMoney("-45.00"), model.MustParseMoney("-45.00"), nil), nil)
+
+	// When.
+	hf := http.HandlerFunc(HandleAuthorizationCapture(m))
+	hf.ServeHTTP(recorder, reqWithCtx)
+
+	// Then.
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	expected := fmt.Sprintf("{\"transaction_id\":112,\"account_id\":%d,\"operation_type_id\":1,\"amount\":-45.00,\"balance\":-45.00}\n", accountIdInt)
+	assert.Equal(t, expected, recorder.Body.String())
+}
+
+func TestHandleAuthorization_Errors(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		handler func(store.Store) http.HandlerFunc
+		body    string
+		expect  func(m *mock_store.MockStore)
+		status  int
+		code    string
+	}{
+		{
+			name:    "capture more than held",
+			handler: HandleAuthorizationCapture,
+			body:    `{"amount":50.01}`,
+			expect: func(m *mock_store.MockStore) {
+				m.EXPECT().CaptureAuthorization(gomock.Any(), 7, gomock.Any()).
+					Return(nil, fmt.Errorf("%w: authorization 7 holds 50.00", model.ErrCaptureExceedsHold))
+			},
+			status: http.StatusUnprocessableEntity,
+			code:   CodeInvalidAmount,
+		},
+		{
+			name:    "capture voided",
+			handler: HandleAuthorizationCapture,
+			body:    `{}`,
+			expect: func(m *mock_store.MockStore) {
+				m.EXPECT().CaptureAuthorization(gomock.Any(), 7, nil).
+					Return(nil, fmt.Errorf("%w: authorization 7 is VOIDED", model.ErrAuthorizationClosed))
+			},
+			status: http.StatusConflict,
+			code:   CodeAuthorizationClosed,
+		},
+		{
+			name:    "void unknown",
+			handler: HandleAuthorizationVoid,
+			expect: func(m *mock_store.MockStore) {
+				m.EXPECT().VoidAuthorization(gomock.Any(), 7).
+					Return(nil, fmt.Errorf("%w: no authorization with id 7", store.ErrAuthorizationNotFound))
+			},
+			status: http.StatusNotFound,
+			code:   CodeAuthorizationNotFound,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given.
+			req, err := http.NewRequest("POST", "/", strings.NewReader(tt.body))
+			require.NoError(t, err)
+
+			chiCtx := chi.NewRouteContext()
+			reqWithCtx := req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+			chiCtx.URLParams.Add("authorizationId", "7")
+
+			recorder := httptest.NewRecorder()
+
+			ctrl := gomock.NewController(t)
+			m := mock_store.NewMockStore(ctrl)
+			tt.expect(m)
+
+			// When.
+			hf := tt.handler(m)
+			hf.ServeHTTP(recorder, reqWithCtx)
+
+			// Then.
+			assert.Equal(t, tt.status, recorder.Code)
+			var got ErrorResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+			assert.Equal(t, tt.code, got.Code)
+		})
+	}
+}
+
+func TestHandleTransactionPost_Installments(t *testing.T) {
+	// Given.
+	body := fmt.Sprintf("{\"account_id\":%d,\"operation_type_id\":2,\"amount\":100.00,\"installments\":3}", accountIdInt)
+	req, err := http.NewRequest("POST", "/", strings.NewReader(body))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+
+	ctrl := gomock.NewController(t)
+	m := mock_store.NewMockStore(ctrl)
+	m.EXPECT().
+		GetAccount(gomock.Any(), accountIdInt).
+		Return(model.NewAccount(&accountIdInt, documentNumber, model.DefaultCurrency), nil)
+	m.EXPECT().
+		GetOperation(gomock.Any(), 2).
+		Return(&model.OperationImpl{
+			OperationTypeID: 2,
+			Description:     "INSTALLMENT PURCHASE",
+			Direction:       model.DirectionDebit,
+		}, nil)
+	m.EXPECT().
+		CreateInstallmentPlan(gomock.Any(), gomock.Cond(func(purchase model.TransactionImpl) bool {
+			return purchase.Amount == model.MustParseMoney("-100.00")
+		}), 3).
+		DoAndReturn(func(_ context.Context, purchase model.TransactionImpl, count int) (*model.InstallmentPlan, error) {
+			plan, err := model.NewInstallmentPlan(purchase, count, model.RemainderOnFirst, time.Now())
+			plan.PlanID = model.IntToPtr(9)
+			for i := range plan.Debits {
+				plan.Debits[i].TransactionID = model.IntToPtr(transactionID + i)
+				plan.Debits[i].Balance = plan.Debits[i].Amount
+			}
+			return plan, err
+		})
+
+	// When.
+	hf := http.HandlerFunc(HandleTransactionPost(m))
+	hf.ServeHTTP(recorder, req)
+
+	// Then.
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	var got model.InstallmentSchedule
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Equal(t, model.IntToPtr(9), got.PlanID)
+	assert.Equal(t, 3, got.InstallmentCount)
+	require.Len(t, got.Installments, 3)
+	assert.Equal(t, model.MustParseMoney("-33.34"), got.Installments[0].Amount)
+	assert.Equal(t, model.InstallmentUnpaid, got.Installments[0].Status)
+	assert.Equal(t, model.InstallmentScheduled, got.Installments[1].Status)
+	assert.Equal(t, model.MustParseMoney("100.00"), got.Unpaid)
+}
+
+func TestHandleGetInstallmentSchedule(t *testing.T) {
+	// Given.
+	req, err := http.NewRequest("GET", "/", nil)
+	require.NoError(t, err)
+
+	chiCtx := chi.NewRouteContext()
+	reqWithCtx := req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+	chiCtx.URLParams.Add("planId", "9")
+
+	recorder := httptest.NewRecorder()
+
+	dueDate := time.Date(2025, 10, 27, 9, 0, 0, 0, time.UTC)
+	installment := model.NewTransaction(&transactionID, accountIdInt, 2, model.MustParseMoney("-50.00"), 0, nil)
+	installment.DueDate = &dueDate
+	ctrl := gomock.NewController(t)
+	m := mock_store.NewMockStore(ctrl)
+	m.EXPECT().
+		GetInstallmentPlan(gomock.Any(), 9).
+		Return(&model.InstallmentPlan{
+			PlanID:           model.IntToPtr(9),
+			AccountID:        accountIdInt,
+			OperationTypeID:  2,
+			Amount:           model.MustParseMoney("-50.00"),
+			InstallmentCount: 1,
+			Debits:           model.Transactions{*installment},
+		}, nil)
+
+	// When.
+	hf := http.HandlerFunc(HandleGetInstallmentSchedule(m))
+	hf.ServeHTTP(recorder, reqWithCtx)
+
+	// Then.
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	expected := fmt.Sprintf("{\"plan_id\":9,\"account_id\":%d,\"operation_type_id\":2,\"amount\":-50.00,\"installment_count\":1,\"installments\":[{\"number\":1,\"transaction_id\":%d,\"due_date\":\"2025-10-27T09:00:00Z\",\"amount\":-50.00,\"balance\":0.00,\"status\":\"PAID\"}],\"paid\":50.00,\"unpaid\":0.00}\n", accountIdInt, transactionID)
+	assert.Equal(t, expected, recorder.Body.String())
+}
+
+func TestHandleGetInstallmentSchedule_NotFound(t *testing.T) {
+	// Given.
+	req, err := http.NewRequest("GET", "/", nil)
+	require.NoError(t, err)
+
+	chiCtx := chi.NewRouteContext()
+	reqWithCtx := req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+	chiCtx.URLParams.Add("planId", "9")
+
+	recorder := httptest.NewRecorder()
+
+	ctrl := gomock.NewController(t)
+	m := mock_store.NewMockStore(ctrl)
+	m.EXPECT().
+		GetInstallmentPlan(gomock.Any(), 9).
+		Return(nil, fmt.Errorf("%w: no installment plan with id 9", store.ErrInstallmentPlanNotFound))
+
+	// When.
+	hf := http.HandlerFunc(HandleGetInstallmentSchedule(m))
+	hf.ServeHTTP(recorder, reqWithCtx)
+
+	// Then.
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	var got ErrorResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Equal(t, CodeInstallmentPlanNotFound, got.Code)
+}
+
+func TestHandleTransferPost(t *testing.T) {
+	// Given.
+	body := fmt.Sprintf("{\"source_account_id\":%d,\"destination_account_id\":124,\"amount\":25.00}", accountIdInt)
+	req, err := http.NewRequest("POST", "/", strings.NewReader(body))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+
+	ctrl := gomock.NewController(t)
+	m := mock_store.NewMockStore(ctrl)
+	m.EXPECT().
+		GetAccount(gomock.Any(), accountIdInt).
+		Return(model.NewAccount(&accountIdInt, documentNumber, model.DefaultCurrency), nil)
+	m.EXPECT().
+		GetAccount(gomock.Any(), 124).
+		Return(model.NewAccount(model.IntToPtr(124), documentNumber, model.DefaultCurrency), nil)
+	transfer := model.NewTransfer(model.TransferRequest{SourceAccountID: accountIdInt, DestinationAccountID: 124, Amount: model.MustParseMoney("25.00")})
+	transfer.Debit.Currency, transfer.Credit.Currency = model.DefaultCurrency, model.DefaultCurrency
+	m.EXPECT().
+		CreateTransfer(gomock.Any(), *transfer).
+		DoAndReturn(func(_ context.Context, transfer model.Transfer) (*model.Transfer, error) {
+			transfer.TransferID = model.IntToPtr(5)
+			transfer.Debit.TransactionID, transfer.Debit.TransferID = &transactionID, transfer.TransferID
+			transfer.Debit.Balance = transfer.Debit.Amount
+			transfer.Credit.TransactionID, transfer.Credit.TransferID = model.IntToPtr(transactionID+1), transfer.TransferID
+			return &transfer, nil
+		})
+
+	// When.
+	hf := http.HandlerFunc(HandleTransferPost(m))
+	hf.ServeHTTP(recorder, req)
+
+	// Then.
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	expected := fmt.Sprintf("{\"transfer_id\":5,\"source_account_id\":%d,\"destination_account_id\":124,\"amount\":25.00,"+
+		"\"debit\":{\"transaction_id\":%d,\"account_id\":%d,\"operation_type_id\":7,\"amount\":-25.00,\"balance\":-25.00,\"transfer_id\":5,\"currency\":\"USD\"},"+
+		"\"credit\":{\"transaction_id\":%d,\"account_id\":124,\"operation_type_id\":8,\"amount\":25.00,\"balance\":0.00,\"transfer_id\":5,\"currency\":\"USD\"}}\n",
+		accountIdInt, transactionID, accountIdInt, transactionID+1)
+	assert.Equal(t, expected, recorder.Body.String())
+}
+
+func TestHandleTransferPost_ConvertsTheCredit(t *testing.T) {
+	// Given.
+	body := fmt.Sprintf("{\"source_account_id\":%d,\"destination_account_id\":124,\"amount\":25.00}", accountIdInt)
+	req, err := http.NewRequest("POST", "/", strings.NewReader(body))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+
+	ctrl := gomock.NewController(t)
+	m := mock_store.NewMockStore(ctrl)
+	m.EXPECT().
+		GetAccount(gomock.Any(), accountIdInt).
+		Return(model.NewAccount(&accountIdInt, documentNumber, model.DefaultCurrency), nil)
+	m.EXPECT().
+		GetAccount(gomock.Any(), 124).
+		Return(model.NewAccount(model.IntToPtr(124), documentNumber, "EUR"), nil)
+	rate := model.FXRate{Base: model.DefaultCurrency, Quote: "EUR", Rate: model.MustParseRate("0.92")}
+	m.EXPECT().
+		GetFXRate(gomock.Any(), model.DefaultCurrency, model.Currency("EUR")).
+		Return(&rate, nil)
+	m.EXPECT().
+		CreateTransfer(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, transfer model.Transfer) (*model.Transfer, error) {
+			transfer.TransferID = model.IntToPtr(5)
+			return &transfer, nil
+		})
+
+	// When.
+	hf := http.HandlerFunc(HandleTransferPost(m))
+	hf.ServeHTTP(recorder, req)
+
+	// Then.
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	var got model.Transfer
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Equal(t, model.MustParseMoney("-25.00"), got.Debit.Amount)
+	assert.Equal(t, model.DefaultCurrency, got.Debit.Currency)
+	assert.Equal(t, model.MustParseMoney("23.00"), got.Credit.Amount)
+	assert.Equal(t, model.Currency("EUR"), got.Credit.Currency)
+	assert.Equal(t, model.MustParseMoney("25.00"), *got.Credit.OriginalAmount)
+}
+
+func TestHandleTransferPost_SameAccount(t *testing.T) {
+	// Given.
+	body := fmt.Sprintf("{\"source_account_id\":%d,\"destination_account_id\":%d,\"amount\":25.00}", accountIdInt, accountIdInt)
+	req, err := http.NewRequest("POST", "/", strings.NewReader(body))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+
+	ctrl := gomock.NewController(t)
+	m := mock_store.NewMockStore(ctrl)
+
+	// When.
+	hf := http.HandlerFunc(HandleTransferPost(m))
+	hf.ServeHTTP(recorder, req)
+
+	// Then.
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	var got ErrorResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Equal(t, CodeValidationFailed, got.Code)
+	assert.Equal(t, []ErrorDetail{
+		{Field: "destination_account_id", Message: "must differ from source_account_id"},
+	}, got.Details)
+}
+
+func TestHandleGetTransfer_NotFound(t *testing.T) {
+	// Given.
+	req, err := http.NewRequest("GET", "/", nil)
+	require.NoError(t, err)
+
+	chiCtx := chi.NewRouteContext()
+	reqWithCtx := req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+	chiCtx.URLParams.Add("transferId", "5")
+
+	recorder := httptest.NewRecorder()
+
+	ctrl := gomock.NewController(t)
+	m := mock_store.NewMockStore(ctrl)
+	m.EXPECT().
+		GetTransfer(gomock.Any(), 5).
+		Return(nil, fmt.Errorf("%w: no transfer with id 5", store.ErrTransferNotFound))
+
+	// When.
+	hf := http.HandlerFunc(HandleGetTransfer(m))
+	hf.ServeHTTP(recorder, reqWithCtx)
+
+	// Then.
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	var got ErrorResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Equal(t, CodeTransferNotFound, got.Code)
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"account-transactions/config"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+)
+
+// NewHTTPServer returns an http.Server for the handler with the timeouts
+// from cfg.
+func NewHTTPServer(handler http.Handler, cfg config.ServerConfig) *http.Server {
+	return &http.Server{
+		Addr:         cfg.Addr,
+		Handler:      handler,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+		IdleTimeout:  cfg.IdleTimeout,
+	}
+}
+
+// Serve accepts connections on ln until ctx is done. It then stops
+// accepting new connections and waits up to shutdownTimeout for in-flight
+// requests to finish, so a payment being settled is not cut off halfway.
+func Serve(ctx context.Context, srv *http.Server, ln net.Listener, shutdownTimeout time.Duration) error {
+	errs := make(chan error, 1)
+	go func() {
+		errs <- srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-errs:
+		// The server failed before it was asked to stop.
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutting down", "timeout", shutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		// Cut off whatever is still running.
+		srv.Close()
+		return fmt.Errorf("shutting down: %w", err)
+	}
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"account-transactions/config"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServe_DrainsInFlightRequests(t *testing.T) {
+	// Given.
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release // A settlement still running when shutdown starts.
+		w.Write([]byte("settled"))
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := NewHTTPServer(handler, config.ServerConfig{})
+
+	ctx, stop := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- Serve(ctx, srv, ln, time.Minute)
+	}()
+
+	responses := make(chan string, 1)
+	go func() {
+		res, err := http.Post("http://"+ln.Addr().String()+"/transactions", "application/json", nil)
+		if err != nil {
+			responses <- err.Error()
+			return
+		}
+		defer res.Body.Close()
+		body, _ := io.ReadAll(res.Body)
+		responses <- string(body)
+	}()
+	<-started
+
+	// When.
+	stop()
+
+	// Then.
+	assert.Eventually(t, func() bool {
+		// New connections are refused once shutdown starts.
+		conn, err := net.Dial("tcp", ln.Addr().String())
+		if err == nil {
+			conn.Close()
+		}
+		return err != nil
+	}, time.Second, 10*time.Millisecond)
+	select {
+	case err := <-served:
+		t.Fatalf("Serve returned before the request finished: %v", err)
+	default:
+	}
+
+	close(release)
+	assert.Equal(t, "settled", <-responses)
+	require.NoError(t, <-served)
+}
+
+func TestServe_ShutdownTimeout(t *testing.T) {
+	// Given.
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done() // Never finishes on its own.
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := NewHTTPServer(handler, config.ServerConfig{})
+
+	ctx, stop := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- Serve(ctx, srv, ln, 10*time.Millisecond)
+	}()
+	go http.Get("http://" + ln.Addr().String())
+	<-started
+
+	// When.
+	stop()
+
+	// Then.
+	require.ErrorIs(t, <-served, context.DeadlineExceeded)
+}
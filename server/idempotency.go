@@ -0,0 +1,119 @@
+package server
+
+import (
+	"account-transactions/store"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+// Idempotency replays the stored response of a request sent again with the
+// same Idempotency-Key header, so client retries don't repeat side effects.
+// Reusing a key with a different request is a conflict. Keys expire after
+// ttl. Requests without the header are passed through.
+func Idempotency(db store.Store, ttl time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				writeError(w, r, http.StatusBadRequest, CodeBadRequest, fmt.Sprintf("%s must be at most %d characters", IdempotencyKeyHeader, maxIdempotencyKeyLength))
+				return
+			}
+
+			// The body is read before the handler gets to limit it.
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+			if err != nil {
+				writeDecodeError(w, r, err)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			hash := requestHash(r, body)
+
+			now := time.Now().UTC()
+			existing, err := db.ReserveIdempotencyKey(r.Context(), key, hash, now, now.Add(-ttl))
+			if err != nil {
+				writeStoreError(w, r, err)
+				return
+			}
+
+			if existing != nil {
+				switch {
+				case existing.RequestHash != hash:
+					writeError(w, r, http.StatusConflict, CodeIdempotencyKeyReused, fmt.Sprintf("%s was already used for a different request", IdempotencyKeyHeader))
+				case existing.InProgress():
+					writeError(w, r, http.StatusConflict, CodeIdempotencyKeyInProgress, fmt.Sprintf("a request with this %s is still in progress", IdempotencyKeyHeader))
+				default:
+					if existing.ContentType != "" {
+						w.Header().Set("Content-Type", existing.ContentType)
+					}
+					w.Header().Set(IdempotentReplayedHeader, "true")
+					w.WriteHeader(existing.StatusCode)
+					w.Write(existing.Body)
+				}
+				return
+			}
+
+			cw := &captureWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(cw, r)
+
+			// Server errors and abandoned requests are not remembered so the
+			// client can retry. Saving outlives the request's context so the
+			// key is never left reserved.
+			ctx := context.WithoutCancel(r.Context())
+			if cw.status >= http.StatusInternalServerError || r.Context().Err() != nil {
+				err = db.ReleaseIdempotencyKey(ctx, key)
+			} else {
+				err = db.CompleteIdempotencyKey(ctx, key, cw.status, w.Header().Get("Content-Type"), cw.body.Bytes())
+			}
+			if err != nil {
+				slog.Error("saving idempotency key", "key", key, "error", err)
+			}
+		})
+	}
+}
+
+// requestHash identifies a request by its method, path and body.
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n", r.Method, r.URL.Path)
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// captureWriter writes the response through while keeping a copy of it.
+type captureWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (w *captureWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *captureWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
@@ -0,0 +1,189 @@
+package server
+
+import (
+	"account-transactions/config"
+	"account-transactions/model"
+	"account-transactions/store"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func postWithKey(t *testing.T, h http.Handler, path string, key string, body string) *httptest.ResponseRecorder {
+	req, err := http.NewRequest("POST", path, strings.NewReader(body))
+	require.NoError(t, err)
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestIdempotency_Replay(t *testing.T) {
+	// Given.
+	db := store.NewMemory()
+	r := NewRouter(db, config.ServerConfig{IdempotencyTTL: time.Hour})
+	body := `{"document_number":"20251027"}`
+
+	// When.
+	first := postWithKey(t, r, "/accounts", "key-1", body)
+	second := postWithKey(t, r, "/accounts", "key-1", body)
+
+	// Then.
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, "application/json", second.Header().Get("Content-Type"))
+	assert.Equal(t, "true", second.Header().Get(IdempotentReplayedHeader))
+	assert.Empty(t, first.Header().Get(IdempotentReplayedHeader))
+
+	// Only one account was created.
+	_, err := db.GetAccount(context.Background(), 2)
+	require.Error(t, err)
+}
+
+func TestIdempotency_DifferentBodyConflicts(t *testing.T) {
+	// Given.
+	r := NewRouter(store.NewMemory(), config.ServerConfig{IdempotencyTTL: time.Hour})
+
+	// When.
+	first := postWithKey(t, r, "/accounts", "key-1", `{"document_number":"1"}`)
+	second := postWithKey(t, r, "/accounts", "key-1", `{"document_number":"2"}`)
+	otherPath := postWithKey(t, r, "/transactions", "key-1", `{"document_number":"1"}`)
+
+	// Then.
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, http.StatusConflict, second.Code)
+	assert.Equal(t, http.StatusConflict, otherPath.Code)
+}
+
+func TestIdempotency_ExpiredKeyRunsAgain(t *testing.T) {
+	// Given.
+	db := store.NewMemory()
+	r := NewRouter(db, config.ServerConfig{IdempotencyTTL: -time.Second}) // Every key is already expired.
+	body := `{"document_number":"20251027"}`
+
+	// When.
+	first := postWithKey(t, r, "/accounts", "key-1", body)
+	second := postWithKey(t, r, "/accounts", "key-1", body)
+
+	// Then.
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.NotEqual(t, first.Body.String(), second.Body.String())
+	assert.Empty(t, second.Header().Get(IdempotentReplayedHeader))
+}
+
+// failingStore fails every CreateAccount call.
+type failingStore struct {
+	*store.MemoryStore
+}
+
+func (failingStore) CreateAccount(context.Context, string, model.Currency) (*model.AccountImpl, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestIdempotency_ServerErrorsAreNotStored(t *testing.T) {
+	// Given.
+	r := NewRouter(failingStore{store.NewMemory()}, config.ServerConfig{IdempotencyTTL: time.Hour})
+	body := `{"document_number":"20251027"}`
+
+	// When.
+	first := postWithKey(t, r, "/accounts", "key-1", body)
+	second := postWithKey(t, r, "/accounts", "key-1", body)
+
+	// Then.
+	assert.Equal(t, http.StatusInternalServerError, first.Code)
+	assert.Equal(t, http.StatusInternalServerError, second.Code)
+	assert.Empty(t, second.Header().Get(IdempotentReplayedHeader))
+}
+
+// cancellingStore cancels the request during every CreateAccount call, as
+// if the client had disconnected.
+type cancellingStore struct {
+	*store.MemoryStore
+	cancel context.CancelFunc
+}
+
+func (s cancellingStore) CreateAccount(ctx context.Context, docNumber string, currency model.Currency) (*model.AccountImpl, error) {
+	s.cancel()
+	return s.MemoryStore.CreateAccount(ctx, docNumber, currency)
+}
+
+func TestIdempotency_CancelledRequestsAreNotStored(t *testing.T) {
+	// Given.
+	db := store.NewMemory()
+	ctx, cancel := context.WithCancel(context.Background())
+	r := NewRouter(cancellingStore{db, cancel}, config.ServerConfig{IdempotencyTTL: time.Hour})
+	body := `{"document_number":"20251027"}`
+
+	req, err := http.NewRequestWithContext(ctx, "POST", "/accounts", strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set(IdempotencyKeyHeader, "key-1")
+
+	// When.
+	cancelled := httptest.NewRecorder()
+	r.ServeHTTP(cancelled, req)
+	retry := postWithKey(t, NewRouter(db, config.ServerConfig{IdempotencyTTL: time.Hour}), "/accounts", "key-1", body)
+
+	// Then.
+	assert.Equal(t, StatusClientClosedRequest, cancelled.Code)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Empty(t, retry.Header().Get(IdempotentReplayedHeader))
+}
+
+func TestIdempotency_ClientErrorsAreReplayed(t *testing.T) {
+	// Given.
+	r := NewRouter(store.NewMemory(), config.ServerConfig{IdempotencyTTL: time.Hour})
+
+	// When.
+	first := postWithKey(t, r, "/accounts", "key-1", `not json`)
+	second := postWithKey(t, r, "/accounts", "key-1", `not json`)
+
+	// Then.
+	assert.Equal(t, http.StatusBadRequest, first.Code)
+	assert.Equal(t, http.StatusBadRequest, second.Code)
+	assert.Equal(t, "true", second.Header().Get(IdempotentReplayedHeader))
+}
+
+func TestIdempotency_WithoutKey(t *testing.T) {
+	// Given.
+	db := store.NewMemory()
+	r := NewRouter(db, config.ServerConfig{IdempotencyTTL: time.Hour})
+	body := `{"document_number":"20251027"}`
+
+	// When.
+	first := postWithKey(t, r, "/accounts", "", body)
+	second := postWithKey(t, r, "/accounts", "", body)
+
+	// Then.
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.NotEqual(t, first.Body.String(), second.Body.String())
+}
+
+func TestIdempotency_BodyTooLarge(t *testing.T) {
+	// Given.
+	db := store.NewMemory()
+	r := NewRouter(db, config.ServerConfig{IdempotencyTTL: time.Hour})
+	body := `{"document_number":"` + strings.Repeat("1", maxBodyBytes) + `"}`
+
+	// When.
+	recorder := postWithKey(t, r, "/accounts", "key-1", body)
+
+	// Then.
+	assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), CodeBadRequest)
+
+	// The key was not reserved.
+	retried := postWithKey(t, r, "/accounts", "key-1", `{"document_number":"20251027"}`)
+	assert.Equal(t, http.StatusCreated, retried.Code)
+}
@@ -1,28 +1,70 @@
 package server
 
 import (
+	"account-transactions/config"
 	"account-transactions/store"
 
 	_ "account-transactions/docs"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
-func NewRouter(db store.Store) *chi.Mux {
+func NewRouter(db store.Store, cfg config.ServerConfig) *chi.Mux {
 	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Use(Timeout(cfg.RequestTimeout))
+	idempotent := Idempotency(db, cfg.IdempotencyTTL)
+
 	r.Get("/swagger/*", httpSwagger.Handler(
-		httpSwagger.URL("http://localhost:8080/swagger/doc.json"), //The url pointing to API definition
+		httpSwagger.URL(cfg.SwaggerURL), //The url pointing to API definition
 	))
 	r.Route("/accounts", func(r chi.Router) {
-		r.Post("/", HandleAccountPost(db))
+		r.With(idempotent).Post("/", HandleAccountPost(db))
 
 		r.Route("/{accountId}", func(r chi.Router) {
 			r.Get("/", HandleGetAccount(db))
+			r.Get("/transactions", HandleListAccountTransactions(db))
+			r.Get("/balance", HandleGetAccountBalance(db))
+			r.Get("/statement", HandleGetAccountStatement(db))
+		})
+	})
+	r.Route("/admin/accounts/{accountId}", func(r chi.Router) {
+		r.Route("/credit-limit", func(r chi.Router) {
+			r.With(idempotent).Put("/", HandleCreditLimitPut(db))
+			r.Get("/history", HandleListCreditLimitChanges(db))
 		})
+		r.With(idempotent).Post("/block", HandleAccountBlock(db))
+		r.With(idempotent).Post("/unblock", HandleAccountUnblock(db))
+		r.With(idempotent).Post("/close", HandleAccountClose(db))
+		r.Get("/status/history", HandleListAccountStatusChanges(db))
+	})
+	r.Route("/admin/fx-rates", func(r chi.Router) {
+		r.With(idempotent).Put("/", HandleFXRatesPut(db))
+		r.Get("/", HandleListFXRates(db))
 	})
 	r.Route("/transactions", func(r chi.Router) {
-		r.Post("/", HandleTransactionPost(db))
+		r.With(idempotent).Post("/", HandleTransactionPost(db))
+
+		r.Route("/{transactionId}", func(r chi.Router) {
+			r.Get("/", HandleGetTransaction(db))
+			r.With(idempotent).Post("/reversal", HandleTransactionReversal(db))
+		})
+	})
+	r.Get("/installment-plans/{planId}/schedule", HandleGetInstallmentSchedule(db))
+	r.Route("/transfers", func(r chi.Router) {
+		r.With(idempotent).Post("/", HandleTransferPost(db))
+		r.Get("/{transferId}", HandleGetTransfer(db))
+	})
+	r.Route("/authorizations", func(r chi.Router) {
+		r.With(idempotent).Post("/", HandleAuthorizationPost(db, cfg.AuthorizationTTL))
+
+		r.Route("/{authorizationId}", func(r chi.Router) {
+			r.Get("/", HandleGetAuthorization(db))
+			r.With(idempotent).Post("/capture", HandleAuthorizationCapture(db))
+			r.With(idempotent).Post("/void", HandleAuthorizationVoid(db))
+		})
 	})
 
 	return r
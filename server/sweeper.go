@@ -0,0 +1,47 @@
+package server
+
+import (
+	"account-transactions/store"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Sweep marks pending authorizations whose expiry has passed as expired and
+// deletes Idempotency-Key records older than idempotencyTTL, once straight
+// away and then every interval, until ctx is done. Expired holds stop
+// counting against the credit limit as soon as they lapse, and expired keys
+// are ignored when they come back; sweeping only records the one and keeps
+// the other from piling up.
+func Sweep(ctx context.Context, db store.Store, interval time.Duration, idempotencyTTL time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		expired, err := db.ExpireAuthorizations(ctx)
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			slog.Error("expiring authorizations", "error", err)
+		case expired > 0:
+			slog.Info("expired authorizations", "count", expired)
+		}
+
+		purged, err := db.PurgeIdempotencyKeys(ctx, time.Now().UTC().Add(-idempotencyTTL))
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			slog.Error("purging idempotency keys", "error", err)
+		case purged > 0:
+			slog.Info("purged idempotency keys", "count", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
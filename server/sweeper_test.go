@@ -0,0 +1,49 @@
+package server
+
+import (
+	mock_store "account-transactions/mocks"
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestSweep(t *testing.T) {
+	// Given.
+	ctx, cancel := context.WithCancel(context.Background())
+	ctrl := gomock.NewController(t)
+	m := mock_store.NewMockStore(ctrl)
+	swept := make(chan struct{}, 2)
+	m.EXPECT().
+		ExpireAuthorizations(gomock.Any()).
+		DoAndReturn(func(context.Context) (int, error) {
+			select {
+			case swept <- struct{}{}:
+			default:
+			}
+			return 1, nil
+		}).
+		MinTimes(2)
+	m.EXPECT().
+		PurgeIdempotencyKeys(gomock.Any(), gomock.Any()).
+		Return(1, nil).
+		AnyTimes()
+
+	// When.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Sweep(ctx, m, time.Millisecond, time.Hour)
+	}()
+	<-swept
+	<-swept
+	cancel()
+
+	// Then.
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Sweep did not return once the context was done")
+	}
+}
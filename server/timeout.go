@@ -0,0 +1,23 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Timeout gives each request a deadline. Store calls made with the request
+// context are aborted once it passes and the request fails with a 504. A
+// timeout of 0 means no deadline.
+func Timeout(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if timeout <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
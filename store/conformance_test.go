@@ -0,0 +1,87 @@
+package store_test
+
+import (
+	"account-transactions/config"
+	"account-transactions/store"
+	"account-transactions/store/storetest"
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The conformance tests run storetest against the memory store, a SQLite
+// file, and the SQL databases listed in STORE_TEST_DRIVERS, e.g.
+//
+//	STORE_TEST_DRIVERS=mysql,postgres POSTGRES_PORT=5433 go test ./store
+//
+// The databases are configured like the server, from the environment. Their
+// schema is migrated down and up again before each test, which drops all of
+// their data.
+
+func TestConformance_Memory(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.Store {
+		return store.NewMemory()
+	})
+}
+
+func TestConformance_SQLite(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.Store {
+		s, err := store.NewSQLite(config.SQLiteConfig{Path: filepath.Join(t.TempDir(), "store.db"), BusyTimeout: 5 * time.Second})
+		require.NoError(t, err)
+		t.Cleanup(func() { s.Close() })
+		return migrated(t, s)
+	})
+}
+
+func TestConformance_MySQL(t *testing.T) {
+	storetest.Run(t, sqlStoreFactory(t, "mysql"))
+}
+
+func TestConformance_Postgres(t *testing.T) {
+	storetest.Run(t, sqlStoreFactory(t, "postgres"))
+}
+
+func sqlStoreFactory(t *testing.T, driver string) func(t *testing.T) store.Store {
+	if !slices.Contains(strings.Split(os.Getenv("STORE_TEST_DRIVERS"), ","), driver) {
+		t.Skipf("set STORE_TEST_DRIVERS=%s to run against %s", driver, driver)
+	}
+	cfg, err := config.Load([]string{"-store", driver})
+	require.NoError(t, err)
+
+	return func(t *testing.T) store.Store {
+		var s *store.StoreImpl
+		if driver == "postgres" {
+			s, err = store.NewPostgres(cfg.Store.Postgres)
+		} else {
+			s, err = store.New(cfg.Store.MySQL)
+		}
+		require.NoError(t, err)
+		t.Cleanup(func() { s.Close() })
+
+		m, err := s.Migrator()
+		require.NoError(t, err)
+		for {
+			reverted, err := m.Down(context.Background())
+			require.NoError(t, err)
+			if reverted == nil {
+				break
+			}
+		}
+		return migrated(t, s)
+	}
+}
+
+// migrated applies the schema migrations to s.
+func migrated(t *testing.T, s *store.StoreImpl) *store.StoreImpl {
+	m, err := s.Migrator()
+	require.NoError(t, err)
+	_, err = m.Up(context.Background())
+	require.NoError(t, err)
+	return s
+}
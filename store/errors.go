@@ -0,0 +1,15 @@
+package store
+
+import "errors"
+
+// Sentinel errors returned, wrapped, by Store implementations. Check them
+// with errors.Is.
+var (
+	ErrAccountNotFound         = errors.New("account not found")
+	ErrOperationNotFound       = errors.New("operation not found")
+	ErrTransactionNotFound     = errors.New("transaction not found")
+	ErrAuthorizationNotFound   = errors.New("authorization not found")
+	ErrInstallmentPlanNotFound = errors.New("installment plan not found")
+	ErrTransferNotFound        = errors.New("transfer not found")
+	ErrFXRateNotFound          = errors.New("fx rate not found")
+)
@@ -0,0 +1,1015 @@
+package store
+
+import (
+	"account-transactions/model"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+var _ Store = &MemoryStore{}
+
+// MemoryStore is an in-memory implementation of Store. It is safe for
+// concurrent use and is intended for local development and tests. Methods
+// fail with the context's error if it is done before they start.
+type MemoryStore struct {
+	mu sync.RWMutex
+
+	accounts     map[int]model.AccountImpl
+	operations   map[int]model.OperationImpl
+	transactions []model.TransactionImpl // Kept in EventDate order.
+	idempotency  map[string]model.IdempotencyRecord
+	// creditLimitChanges are in the order they were made.
+	creditLimitChanges []model.CreditLimitChange
+	// statusChanges are in the order they were made.
+	statusChanges []model.AccountStatusChange
+	// authorizations are indexed by their ID minus one.
+	authorizations []model.AuthorizationImpl
+	// plans are indexed by their ID minus one, without their Debits.
+	plans []model.InstallmentPlan
+	// transfers are indexed by their ID minus one, without their
+	// transactions.
+	transfers []model.Transfer
+	// fxRates are keyed by their base and quote currency.
+	fxRates map[[2]model.Currency]model.FXRate
+	// allocations are in the order they were made.
+	allocations model.Allocations
+
+	lastAccountId     int
+	lastTransactionId int
+
+	// now returns the EventDate for new transactions and is the clock
+	// authorizations are opened, closed and expired by.
+	now func() time.Time
+
+	// SettlementPolicy orders debts in SettlePayment.
+	SettlementPolicy model.SettlementPolicy
+	// InstallmentRounding places the remainder in CreateInstallmentPlan.
+	InstallmentRounding model.InstallmentRounding
+}
+
+// NewMemory returns an empty MemoryStore seeded with the same operation
+// types as the MySQL migrations.
+func NewMemory() *MemoryStore {
+	slog.Info("using store: memory")
+	return &MemoryStore{
+		accounts:    map[int]model.AccountImpl{},
+		idempotency: map[string]model.IdempotencyRecord{},
+		fxRates:     map[[2]model.Currency]model.FXRate{},
+		operations: map[int]model.OperationImpl{
+			1: {OperationTypeID: 1, Description: "PURCHASE", Direction: model.DirectionDebit},
+			2: {OperationTypeID: 2, Description: "INSTALLMENT PURCHASE", Direction: model.DirectionDebit},
+			3: {OperationTypeID: 3, Description: "WITHDRAWAL", Direction: model.DirectionDebit},
+			4: {OperationTypeID: 4, Description: "PAYMENT", Direction: model.DirectionCredit},
+			5: {OperationTypeID: 5, Description: "REFUND", Direction: model.DirectionCredit},
+			6: {OperationTypeID: 6, Description: "PAYMENT REVERSAL", Direction: model.DirectionDebit},
+			7: {OperationTypeID: 7, Description: "TRANSFER OUT", Direction: model.DirectionDebit},
+			8: {OperationTypeID: 8, Description: "TRANSFER IN", Direction: model.DirectionCredit},
+		},
+		now: time.Now,
+	}
+}
+
+func (s *MemoryStore) GetAccount(ctx context.Context, accountId int) (*model.AccountImpl, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	account, ok := s.accounts[accountId]
+	if !ok {
+		return &model.AccountImpl{}, fmt.Errorf("%w: no account with id %d", ErrAccountNotFound, accountId)
+	}
+	return &account, nil
+}
+
+func (s *MemoryStore) CreateAccount(ctx context.Context, docNumber string, currency model.Currency) (*model.AccountImpl, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastAccountId++
+	account := model.NewAccount(model.IntToPtr(s.lastAccountId), docNumber, currency)
+	s.accounts[s.lastAccountId] = *account
+	return account, nil
+}
+
+func (s *MemoryStore) UpdateCreditLimit(ctx context.Context, change model.CreditLimitChange) (*model.CreditLimitChange, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	account, ok := s.accounts[change.AccountID]
+	if !ok {
+		return nil, fmt.Errorf("%w: no account with id %d", ErrAccountNotFound, change.AccountID)
+	}
+	changedAt := s.now()
+	change.ChangeID = model.IntToPtr(len(s.creditLimitChanges) + 1)
+	change.OldLimit = account.CreditLimit
+	change.ChangedAt = &changedAt
+	s.creditLimitChanges = append(s.creditLimitChanges, change)
+
+	account.CreditLimit = model.MoneyToPtr(change.NewLimit)
+	s.accounts[change.AccountID] = account
+	return &change, nil
+}
+
+func (s *MemoryStore) ListCreditLimitChanges(ctx context.Context, accountId int) ([]model.CreditLimitChange, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	changes := []model.CreditLimitChange{}
+	for _, change := range s.creditLimitChanges {
+		if change.AccountID == accountId {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+func (s *MemoryStore) UpdateAccountStatus(ctx context.Context, change model.AccountStatusChange) (*model.AccountStatusChange, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	account, ok := s.accounts[change.AccountID]
+	if !ok {
+		return nil, fmt.Errorf("%w: no account with id %d", ErrAccountNotFound, change.AccountID)
+	}
+	var balance model.Money
+	for _, transaction := range s.transactions {
+		if transaction.AccountID == change.AccountID {
+			balance += transaction.Amount
+		}
+	}
+	if err := account.CheckTransition(change.NewStatus, balance, s.held(change.AccountID)); err != nil {
+		return nil, err
+	}
+	changedAt := s.now()
+	change.ChangeID = model.IntToPtr(len(s.statusChanges) + 1)
+	change.OldStatus = account.Status
+	change.ChangedAt = &changedAt
+	s.statusChanges = append(s.statusChanges, change)
+
+	account.Status = change.NewStatus
+	s.accounts[change.AccountID] = account
+	return &change, nil
+}
+
+func (s *MemoryStore) ListAccountStatusChanges(ctx context.Context, accountId int) ([]model.AccountStatusChange, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	changes := []model.AccountStatusChange{}
+	for _, change := range s.statusChanges {
+		if change.AccountID == accountId {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+func (s *MemoryStore) GetBalance(ctx context.Context, accountId int) (*model.AccountBalance, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	account, ok := s.accounts[accountId]
+	if !ok {
+		return nil, fmt.Errorf("%w: no account with id %d", ErrAccountNotFound, accountId)
+	}
+	var transactions model.Transactions
+	for _, transaction := range s.transactions {
+		if transaction.AccountID == accountId && transaction.Balance != 0 {
+			transactions = append(transactions, transaction)
+		}
+	}
+	return model.NewAccountBalance(&account, transactions, s.held(accountId), s.now()), nil
+}
+
+func (s *MemoryStore) GetStatement(ctx context.Context, accountId int, from *time.Time, to *time.Time) (*model.Statement, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.accounts[accountId]; !ok {
+		return nil, fmt.Errorf("%w: no account with id %d", ErrAccountNotFound, accountId)
+	}
+	var opening model.Money
+	var transactions model.Transactions
+	for _, transaction := range s.transactions {
+		switch {
+		case transaction.AccountID != accountId:
+		case from != nil && transaction.EventDate.Before(*from):
+			opening += transaction.Amount
+		case to == nil || transaction.EventDate.Before(*to):
+			transactions = append(transactions, transaction)
+		}
+	}
+	return model.NewStatement(accountId, from, to, opening, transactions), nil
+}
+
+func (s *MemoryStore) GetOperation(ctx context.Context, operationId int) (*model.OperationImpl, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	operation, ok := s.operations[operationId]
+	if !ok {
+		return &model.OperationImpl{}, fmt.Errorf("%w: no operation with id %d", ErrOperationNotFound, operationId)
+	}
+	return &operation, nil
+}
+
+func (s *MemoryStore) GetTransaction(ctx context.Context, transactionId int) (*model.TransactionImpl, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	i, ok := s.findTransaction(transactionId)
+	if !ok {
+		return &model.TransactionImpl{}, fmt.Errorf("%w: no transaction with id %d", ErrTransactionNotFound, transactionId)
+	}
+	transaction := s.transactions[i]
+	return &transaction, nil
+}
+
+func (s *MemoryStore) ListTransactions(ctx context.Context, accountId int, filter model.TransactionFilter) (model.Transactions, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// s.transactions is already in ascending (EventDate, Transaction_ID) order.
+	ordered := s.transactions
+	if filter.Descending {
+		ordered = slices.Clone(ordered)
+		slices.Reverse(ordered)
+	}
+
+	transactions := model.Transactions{}
+	for _, transaction := range ordered {
+		if len(transactions) == filter.Limit {
+			break
+		}
+		if transaction.AccountID != accountId ||
+			(filter.OperationTypeID != nil && transaction.OperationTypeID != *filter.OperationTypeID) ||
+			(filter.From != nil && transaction.EventDate.Before(*filter.From)) ||
+			(filter.To != nil && !transaction.EventDate.Before(*filter.To)) ||
+			(filter.After != nil && !isAfter(transaction, *filter.After, filter.Descending)) {
+			continue
+		}
+		transactions = append(transactions, transaction)
+	}
+	return transactions, nil
+}
+
+func (s *MemoryStore) GetNegativeTransactions(ctx context.Context, accountId int, operationType int) (model.Transactions, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.negativeTransactions(accountId, operationType), nil
+}
+
+func (s *MemoryStore) UpdateNegativeTransactions(ctx context.Context, transactions model.Transactions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.updateBalances(transactions)
+	return nil
+}
+
+func (s *MemoryStore) SettlePayment(ctx context.Context, payment model.TransactionImpl) (*model.TransactionImpl, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Validate before touching any balances so a failure leaves no trace.
+	if err := s.checkForeignKeys(payment); err != nil {
+		return nil, err
+	}
+	account := s.accounts[payment.AccountID]
+	if err := account.CheckPosting(payment.Amount); err != nil {
+		return nil, err
+	}
+	if err := account.Book(&payment); err != nil {
+		return nil, err
+	}
+
+	transactions, allocations, amount, err := model.ProcessNegativePayments(slices.Concat(s.SettlementPolicy.Order(s.debts(payment.AccountID)), s.scheduled(payment.AccountID)), payment.Amount, payment.Currency)
+	if err != nil {
+		return nil, err
+	}
+	s.updateBalances(transactions)
+
+	payment.Balance = amount
+	result := s.insertTransaction(payment)
+	s.allocations = append(s.allocations, allocations.For(*result.TransactionID)...)
+	return result, nil
+}
+
+func (s *MemoryStore) CreateDebit(ctx context.Context, debit model.TransactionImpl) (*model.TransactionImpl, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkForeignKeys(debit); err != nil {
+		return nil, err
+	}
+	account := s.accounts[debit.AccountID]
+	if err := account.CheckPosting(debit.Amount); err != nil {
+		return nil, err
+	}
+	if err := account.Book(&debit); err != nil {
+		return nil, err
+	}
+	if err := account.CheckDebit(s.owed(debit.AccountID), s.credits(debit.AccountID), s.held(debit.AccountID), debit.Amount); err != nil {
+		return nil, err
+	}
+	credits, allocations, amount, err := model.ProcessPositivePayments(s.credits(debit.AccountID), debit.Amount, debit.Currency)
+	if err != nil {
+		return nil, err
+	}
+	s.updateBalances(credits)
+
+	debit.Balance = amount
+	result := s.insertTransaction(debit)
+	s.allocations = append(s.allocations, allocations.For(*result.TransactionID)...)
+	return result, nil
+}
+
+func (s *MemoryStore) CreateTransaction(ctx context.Context, transaction model.TransactionImpl) (*model.TransactionImpl, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkForeignKeys(transaction); err != nil {
+		return nil, err
+	}
+	account := s.accounts[transaction.AccountID]
+	if err := account.CheckPosting(transaction.Amount); err != nil {
+		return nil, err
+	}
+	if err := account.Book(&transaction); err != nil {
+		return nil, err
+	}
+	return s.insertTransaction(transaction), nil
+}
+
+func (s *MemoryStore) GetAuthorization(ctx context.Context, authorizationId int) (*model.AuthorizationImpl, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	authorization, err := s.findAuthorization(authorizationId)
+	if err != nil {
+		return nil, err
+	}
+	result := *authorization
+	return &result, nil
+}
+
+func (s *MemoryStore) CreateAuthorization(ctx context.Context, authorization model.AuthorizationImpl) (*model.AuthorizationImpl, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkForeignKeys(model.TransactionImpl{AccountID: authorization.AccountID, OperationTypeID: authorization.OperationTypeID}); err != nil {
+		return nil, err
+	}
+	account := s.accounts[authorization.AccountID]
+	if err := account.CheckPosting(authorization.Amount); err != nil {
+		return nil, err
+	}
+	if err := account.CheckDebit(s.owed(authorization.AccountID), s.credits(authorization.AccountID), s.held(authorization.AccountID), authorization.Amount); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	authorizationId := len(s.authorizations) + 1
+	authorization.AuthorizationID = &authorizationId
+	authorization.Status = model.AuthorizationPending
+	authorization.CreatedAt = &now
+	s.authorizations = append(s.authorizations, authorization)
+	return &authorization, nil
+}
+
+func (s *MemoryStore) CaptureAuthorization(ctx context.Context, authorizationId int, amount *model.Money) (*model.TransactionImpl, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	authorization, err := s.findAuthorization(authorizationId)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	if err := authorization.CheckPending(now); err != nil {
+		return nil, err
+	}
+	debit, err := authorization.NewCapture(amount)
+	if err != nil {
+		return nil, err
+	}
+	account := s.accounts[debit.AccountID]
+	if err := account.CheckPosting(debit.Amount); err != nil {
+		return nil, err
+	}
+	if err := account.Book(debit); err != nil {
+		return nil, err
+	}
+	credits, allocations, rest, err := model.ProcessPositivePayments(s.credits(debit.AccountID), debit.Amount, debit.Currency)
+	if err != nil {
+		return nil, err
+	}
+	s.updateBalances(credits)
+
+	debit.Balance = rest
+	result := s.insertTransaction(*debit)
+	s.allocations = append(s.allocations, allocations.For(*result.TransactionID)...)
+	authorization.Status = model.AuthorizationCaptured
+	authorization.ClosedAt = &now
+	authorization.TransactionID = result.TransactionID
+	return result, nil
+}
+
+func (s *MemoryStore) VoidAuthorization(ctx context.Context, authorizationId int) (*model.AuthorizationImpl, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	authorization, err := s.findAuthorization(authorizationId)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	if err := authorization.CheckPending(now); err != nil {
+		return nil, err
+	}
+	authorization.Status = model.AuthorizationVoided
+	authorization.ClosedAt = &now
+	result := *authorization
+	return &result, nil
+}
+
+func (s *MemoryStore) ExpireAuthorizations(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	expired := 0
+	for i, authorization := range s.authorizations {
+		if authorization.Status == model.AuthorizationPending && authorization.CheckPending(now) != nil {
+			s.authorizations[i].Status = model.AuthorizationExpired
+			s.authorizations[i].ClosedAt = &now
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+func (s *MemoryStore) CreateInstallmentPlan(ctx context.Context, purchase model.TransactionImpl, count int) (*model.InstallmentPlan, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkForeignKeys(purchase); err != nil {
+		return nil, err
+	}
+	account := s.accounts[purchase.AccountID]
+	if err := account.CheckPosting(purchase.Amount); err != nil {
+		return nil, err
+	}
+	if err := account.Book(&purchase); err != nil {
+		return nil, err
+	}
+	plan, err := model.NewInstallmentPlan(purchase, count, s.InstallmentRounding, s.now())
+	if err != nil {
+		return nil, err
+	}
+	if err := account.CheckDebit(s.owed(purchase.AccountID), s.credits(purchase.AccountID), s.held(purchase.AccountID), plan.Amount); err != nil {
+		return nil, err
+	}
+
+	credits := s.credits(purchase.AccountID)
+	allocations := make([]model.Allocations, len(plan.Debits))
+	for i, debit := range plan.Debits {
+		credits, allocations[i], plan.Debits[i].Balance, err = model.ProcessPositivePayments(credits, debit.Amount, debit.Currency)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Nothing can fail from here on, so no half plan is left behind.
+	planId := len(s.plans) + 1
+	plan.PlanID = &planId
+	stored := *plan
+	stored.Debits = nil
+	s.plans = append(s.plans, stored)
+	for i, debit := range plan.Debits {
+		debit.PlanID = &planId
+		plan.Debits[i] = *s.insertTransaction(debit)
+		s.allocations = append(s.allocations, allocations[i].For(*plan.Debits[i].TransactionID)...)
+	}
+	s.updateBalances(credits)
+	return plan, nil
+}
+
+func (s *MemoryStore) GetInstallmentPlan(ctx context.Context, planId int) (*model.InstallmentPlan, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if planId < 1 || planId > len(s.plans) {
+		return nil, fmt.Errorf("%w: no installment plan with id %d", ErrInstallmentPlanNotFound, planId)
+	}
+	plan := s.plans[planId-1]
+	for _, transaction := range s.transactions {
+		if transaction.PlanID != nil && *transaction.PlanID == planId {
+			plan.Debits = append(plan.Debits, transaction)
+		}
+	}
+	sortByDueDate(plan.Debits)
+	return &plan, nil
+}
+
+func (s *MemoryStore) CreateTransfer(ctx context.Context, transfer model.Transfer) (*model.Transfer, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if transfer.SourceAccountID == transfer.DestinationAccountID {
+		return nil, fmt.Errorf("%w: account %d", model.ErrSameAccount, transfer.SourceAccountID)
+	}
+	debit, credit := *transfer.Debit, *transfer.Credit
+	if err := s.checkForeignKeys(debit); err != nil {
+		return nil, err
+	}
+	if err := s.checkForeignKeys(credit); err != nil {
+		return nil, err
+	}
+	account, destination := s.accounts[debit.AccountID], s.accounts[credit.AccountID]
+	if err := account.CheckPosting(debit.Amount); err != nil {
+		return nil, err
+	}
+	if err := destination.CheckPosting(credit.Amount); err != nil {
+		return nil, err
+	}
+	if err := account.Book(&debit); err != nil {
+		return nil, err
+	}
+	// The credit is in the debit's currency unless it was converted.
+	if credit.Currency == "" {
+		credit.Currency = debit.Currency
+	}
+	if err := destination.Book(&credit); err != nil {
+		return nil, err
+	}
+	if err := account.CheckDebit(s.owed(debit.AccountID), s.credits(debit.AccountID), s.held(debit.AccountID), debit.Amount); err != nil {
+		return nil, err
+	}
+
+	credits, debitAllocations, rest, err := model.ProcessPositivePayments(s.credits(debit.AccountID), debit.Amount, debit.Currency)
+	if err != nil {
+		return nil, err
+	}
+	debit.Balance = rest
+	transactions, creditAllocations, rest, err := model.ProcessNegativePayments(slices.Concat(s.SettlementPolicy.Order(s.debts(credit.AccountID)), s.scheduled(credit.AccountID)), credit.Amount, credit.Currency)
+	if err != nil {
+		return nil, err
+	}
+	credit.Balance = rest
+
+	// Nothing can fail from here on, so no half transfer is left behind.
+	createdAt := s.now()
+	transferId := len(s.transfers) + 1
+	transfer.TransferID = &transferId
+	transfer.CreatedAt = &createdAt
+	transfer.Debit, transfer.Credit = nil, nil
+	s.transfers = append(s.transfers, transfer)
+
+	s.updateBalances(credits)
+	debit.TransferID = &transferId
+	transfer.Debit = s.insertTransaction(debit)
+	s.updateBalances(transactions)
+	credit.TransferID = &transferId
+	transfer.Credit = s.insertTransaction(credit)
+	s.allocations = append(s.allocations, debitAllocations.For(*transfer.Debit.TransactionID)...)
+	s.allocations = append(s.allocations, creditAllocations.For(*transfer.Credit.TransactionID)...)
+	return &transfer, nil
+}
+
+func (s *MemoryStore) GetTransfer(ctx context.Context, transferId int) (*model.Transfer, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if transferId < 1 || transferId > len(s.transfers) {
+		return nil, fmt.Errorf("%w: no transfer with id %d", ErrTransferNotFound, transferId)
+	}
+	transfer := s.transfers[transferId-1]
+	for _, transaction := range s.transactions {
+		switch {
+		case transaction.TransferID == nil || *transaction.TransferID != transferId:
+		case transaction.Amount < 0:
+			transfer.Debit = &transaction
+		default:
+			transfer.Credit = &transaction
+		}
+	}
+	return &transfer, nil
+}
+
+func (s *MemoryStore) ReverseTransaction(ctx context.Context, transactionId int, amount *model.Money) (*model.TransactionImpl, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i, ok := s.findTransaction(transactionId)
+	if !ok {
+		return nil, fmt.Errorf("%w: no transaction with id %d", ErrTransactionNotFound, transactionId)
+	}
+	original := s.transactions[i]
+	var reversed model.Money
+	for _, transaction := range s.transactions {
+		if transaction.OriginalTransactionID != nil && *transaction.OriginalTransactionID == transactionId {
+			reversed += transaction.Amount
+		}
+	}
+
+	reversal, err := model.NewReversal(&original, reversed, amount)
+	if err != nil {
+		return nil, err
+	}
+	account := s.accounts[original.AccountID]
+	if err := account.CheckPosting(reversal.Amount); err != nil {
+		return nil, err
+	}
+	allocations := s.allocationsOf(transactionId)
+	transactions, allocations, err := s.SettlementPolicy.SettleReversal(&original, reversal, allocations, s.allocated(transactionId, allocations), s.debts(original.AccountID), s.scheduled(original.AccountID), s.credits(original.AccountID))
+	if err != nil {
+		return nil, err
+	}
+	s.updateBalances(transactions)
+	result := s.insertTransaction(*reversal)
+	s.allocations = append(s.allocations, allocations.For(*result.TransactionID)...)
+	return result, nil
+}
+
+func (s *MemoryStore) GetFXRate(ctx context.Context, base model.Currency, quote model.Currency) (*model.FXRate, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rate, ok := s.fxRates[[2]model.Currency{base, quote}]
+	if !ok {
+		return nil, fmt.Errorf("%w: no rate from %s to %s", ErrFXRateNotFound, base, quote)
+	}
+	return &rate, nil
+}
+
+func (s *MemoryStore) ListFXRates(ctx context.Context) ([]model.FXRate, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rates := []model.FXRate{}
+	for _, rate := range s.fxRates {
+		rates = append(rates, rate)
+	}
+	sort.Slice(rates, func(i, j int) bool {
+		if rates[i].Base != rates[j].Base {
+			return rates[i].Base < rates[j].Base
+		}
+		return rates[i].Quote < rates[j].Quote
+	})
+	return rates, nil
+}
+
+func (s *MemoryStore) UpdateFXRates(ctx context.Context, rates []model.FXRate) ([]model.FXRate, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	updated := make([]model.FXRate, len(rates))
+	for i, rate := range rates {
+		rate.UpdatedAt = &now
+		s.fxRates[[2]model.Currency{rate.Base, rate.Quote}] = rate
+		updated[i] = rate
+	}
+	return updated, nil
+}
+
+func (s *MemoryStore) ReserveIdempotencyKey(ctx context.Context, key string, requestHash string, createdAt time.Time, expiresBefore time.Time) (*model.IdempotencyRecord, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.idempotency[key]; ok && !existing.CreatedAt.Before(expiresBefore) {
+		return &existing, nil
+	}
+	s.idempotency[key] = model.IdempotencyRecord{Key: key, RequestHash: requestHash, CreatedAt: createdAt}
+	return nil, nil
+}
+
+func (s *MemoryStore) CompleteIdempotencyKey(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.idempotency[key]
+	if !ok {
+		return nil
+	}
+	record.StatusCode = statusCode
+	record.ContentType = contentType
+	record.Body = slices.Clone(body)
+	s.idempotency[key] = record
+	return nil
+}
+
+func (s *MemoryStore) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.idempotency, key)
+	return nil
+}
+
+func (s *MemoryStore) PurgeIdempotencyKeys(ctx context.Context, expiresBefore time.Time) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	purged := 0
+	for key, record := range s.idempotency {
+		if record.CreatedAt.Before(expiresBefore) {
+			delete(s.idempotency, key)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// Close does nothing; the data is dropped with the MemoryStore.
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// The helpers below expect the caller to hold s.mu.
+
+// checkForeignKeys mirrors the foreign keys on the Transactions table.
+func (s *MemoryStore) checkForeignKeys(transaction model.TransactionImpl) error {
+	if _, ok := s.accounts[transaction.AccountID]; !ok {
+		return fmt.Errorf("%w: no account with id %d", ErrAccountNotFound, transaction.AccountID)
+	}
+	if _, ok := s.operations[transaction.OperationTypeID]; !ok {
+		return fmt.Errorf("%w: no operation with id %d", ErrOperationNotFound, transaction.OperationTypeID)
+	}
+	return nil
+}
+
+// negativeTransactions returns the account's outstanding debits of one
+// operation type that are due, oldest first.
+func (s *MemoryStore) negativeTransactions(accountId int, operationType int) model.Transactions {
+	now := s.now()
+	var transactions model.Transactions
+	for _, transaction := range s.transactions {
+		if transaction.AccountID == accountId && transaction.OperationTypeID == operationType && transaction.Balance < 0 && transaction.IsDue(now) {
+			transactions = append(transactions, transaction)
+		}
+	}
+	return transactions
+}
+
+// debts returns the account's outstanding debits of any operation type
+// that are due, oldest first.
+func (s *MemoryStore) debts(accountId int) model.Transactions {
+	now := s.now()
+	var transactions model.Transactions
+	for _, transaction := range s.transactions {
+		operation := s.operations[transaction.OperationTypeID]
+		if transaction.AccountID == accountId && operation.IsDebit() && transaction.Balance < 0 && transaction.IsDue(now) {
+			transactions = append(transactions, transaction)
+		}
+	}
+	return transactions
+}
+
+// scheduled returns the account's installments that are not due yet,
+// soonest due first.
+func (s *MemoryStore) scheduled(accountId int) model.Transactions {
+	now := s.now()
+	var transactions model.Transactions
+	for _, transaction := range s.transactions {
+		if transaction.AccountID == accountId && transaction.Balance < 0 && !transaction.IsDue(now) {
+			transactions = append(transactions, transaction)
+		}
+	}
+	sortByDueDate(transactions)
+	return transactions
+}
+
+// owed returns all the account will owe: its debts, then its installments
+// that are not due yet.
+func (s *MemoryStore) owed(accountId int) model.Transactions {
+	return slices.Concat(s.debts(accountId), s.scheduled(accountId))
+}
+
+// credits returns the account's payments with an unapplied balance,
+// oldest first.
+func (s *MemoryStore) credits(accountId int) model.Transactions {
+	var transactions model.Transactions
+	for _, transaction := range s.transactions {
+		operation := s.operations[transaction.OperationTypeID]
+		if transaction.AccountID == accountId && !operation.IsDebit() && transaction.Balance > 0 {
+			transactions = append(transactions, transaction)
+		}
+	}
+	return transactions
+}
+
+// allocationsOf returns what is still allocated between the transaction
+// and others, netted by model.Allocations.Net.
+func (s *MemoryStore) allocationsOf(transactionId int) model.Allocations {
+	var allocations model.Allocations
+	for _, allocation := range s.allocations {
+		if allocation.CreditTransactionID == transactionId || allocation.DebitTransactionID == transactionId {
+			allocations = append(allocations, allocation)
+		}
+	}
+	return allocations.Net()
+}
+
+// allocated returns the transactions on the other side of the
+// transaction's allocations.
+func (s *MemoryStore) allocated(transactionId int, allocations model.Allocations) model.Transactions {
+	var transactions model.Transactions
+	for _, allocation := range allocations {
+		otherId := allocation.CreditTransactionID
+		if otherId == transactionId {
+			otherId = allocation.DebitTransactionID
+		}
+		if i, ok := s.findTransaction(otherId); ok {
+			transactions = append(transactions, s.transactions[i])
+		}
+	}
+	return transactions
+}
+
+// held returns what the account's pending authorizations that have not
+// expired hold, as a positive amount.
+func (s *MemoryStore) held(accountId int) model.Money {
+	var authorizations []model.AuthorizationImpl
+	for _, authorization := range s.authorizations {
+		if authorization.AccountID == accountId {
+			authorizations = append(authorizations, authorization)
+		}
+	}
+	return model.Held(authorizations, s.now())
+}
+
+// findAuthorization returns the authorization, which callers may change
+// in place.
+func (s *MemoryStore) findAuthorization(authorizationId int) (*model.AuthorizationImpl, error) {
+	if authorizationId < 1 || authorizationId > len(s.authorizations) {
+		return nil, fmt.Errorf("%w: no authorization with id %d", ErrAuthorizationNotFound, authorizationId)
+	}
+	return &s.authorizations[authorizationId-1], nil
+}
+
+func (s *MemoryStore) updateBalances(transactions model.Transactions) {
+	for _, transaction := range transactions {
+		if transaction.TransactionID == nil {
+			continue
+		}
+		// Like an UPDATE matching no rows, unknown IDs are ignored.
+		if i, ok := s.findTransaction(*transaction.TransactionID); ok {
+			s.transactions[i].Balance = transaction.Balance
+		}
+	}
+}
+
+func (s *MemoryStore) insertTransaction(transaction model.TransactionImpl) *model.TransactionImpl {
+	s.lastTransactionId++
+	transactionId := s.lastTransactionId
+	eventDate := s.now()
+	transaction.TransactionID = &transactionId
+	transaction.EventDate = &eventDate
+
+	// Insert in EventDate order so negativeTransactions returns oldest first.
+	i := sort.Search(len(s.transactions), func(i int) bool {
+		return s.transactions[i].EventDate.After(eventDate)
+	})
+	s.transactions = append(s.transactions, model.TransactionImpl{})
+	copy(s.transactions[i+1:], s.transactions[i:])
+	s.transactions[i] = transaction
+
+	return &transaction
+}
+
+// sortByDueDate sorts installments soonest due first, keeping the
+// EventDate order of those due at the same time.
+func sortByDueDate(transactions model.Transactions) {
+	slices.SortStableFunc(transactions, func(a, b model.TransactionImpl) int {
+		return a.DueDate.Compare(*b.DueDate)
+	})
+}
+
+// isAfter reports whether the transaction comes after the cursor in the
+// given order.
+func isAfter(transaction model.TransactionImpl, cursor model.Cursor, descending bool) bool {
+	cmp := transaction.EventDate.Compare(cursor.EventDate)
+	if cmp == 0 {
+		cmp = *transaction.TransactionID - cursor.TransactionID
+	}
+	if descending {
+		return cmp < 0
+	}
+	return cmp > 0
+}
+
+// findTransaction returns the index of the transaction with the given ID.
+func (s *MemoryStore) findTransaction(transactionId int) (int, bool) {
+	for i, transaction := range s.transactions {
+		if transaction.TransactionID != nil && *transaction.TransactionID == transactionId {
+			return i, true
+		}
+	}
+	return 0, false
+}
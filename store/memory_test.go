@@ -0,0 +1,331 @@
+package store
+
+import (
+	"account-transactions/model"
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore_Account(t *testing.T) {
+	// Given.
+	store := NewMemory()
+
+	// When.
+	created, err := store.CreateAccount(context.Background(), documentNumber, model.DefaultCurrency)
+	require.NoError(t, err)
+	got, err := store.GetAccount(context.Background(), *created.AccountID)
+
+	// Then.
+	require.NoError(t, err)
+	assert.Equal(t, model.NewAccount(model.IntToPtr(1), documentNumber, model.DefaultCurrency), got)
+}
+
+func TestMemoryStore_AccountNotFound(t *testing.T) {
+	// Given.
+	store := NewMemory()
+
+	// When.
+	account, err := store.GetAccount(context.Background(), invalidAccountId)
+
+	// Then.
+	require.ErrorIs(t, err, ErrAccountNotFound)
+	assert.Equal(t, &model.AccountImpl{}, account)
+	assert.Contains(t, err.Error(), fmt.Sprintf("no account with id %d", invalidAccountId))
+}
+
+func TestMemoryStore_Operations(t *testing.T) {
+	// Given.
+	store := NewMemory()
+
+	// When.
+	purchase, err := store.GetOperation(context.Background(), 1)
+	require.NoError(t, err)
+	payment, err := store.GetOperation(context.Background(), 4)
+	require.NoError(t, err)
+	_, err = store.GetOperation(context.Background(), 9)
+
+	// Then.
+	assert.True(t, purchase.IsPurchase())
+	assert.True(t, payment.IsPayment())
+	assert.True(t, purchase.IsDebit())
+	assert.False(t, payment.IsDebit())
+	require.ErrorIs(t, err, ErrOperationNotFound)
+}
+
+func TestMemoryStore_CreateTransactionUnknownAccount(t *testing.T) {
+	// Given.
+	store := NewMemory()
+
+	// When.
+	transaction, err := store.CreateTransaction(context.Background(), *model.NewTransaction(nil, invalidAccountId, 1, model.MustParseMoney("-10.00"), model.MustParseMoney("-10.00"), nil))
+
+	// Then.
+	require.Error(t, err)
+	assert.Nil(t, transaction)
+}
+
+func TestMemoryStore_NegativeTransactions(t *testing.T) {
+	// Given.
+	store := NewMemory()
+	account, err := store.CreateAccount(context.Background(), documentNumber, model.DefaultCurrency)
+	require.NoError(t, err)
+	accountId := *account.AccountID
+
+	// Out of order event dates to check ordering.
+	dates := []time.Time{
+		time.Date(2025, 10, 3, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 10, 2, 0, 0, 0, 0, time.UTC),
+	}
+	for i, date := range dates {
+		store.now = func() time.Time { return date }
+		_, err := store.CreateTransaction(context.Background(), *model.NewTransaction(nil, accountId, 1, model.Money(-1000*(i+1)), model.Money(-1000*(i+1)), nil))
+		require.NoError(t, err)
+	}
+	_, err = store.CreateTransaction(context.Background(), *model.NewTransaction(nil, accountId, 4, model.MustParseMoney("60.00"), model.MustParseMoney("60.00"), nil))
+	require.NoError(t, err)
+
+	// When.
+	transactions, err := store.GetNegativeTransactions(context.Background(), accountId, 1)
+	require.NoError(t, err)
+
+	// Then.
+	require.Len(t, transactions, 3)
+	assert.Equal(t, []model.Money{-2000, -3000, -1000}, []model.Money{transactions[0].Balance, transactions[1].Balance, transactions[2].Balance})
+
+	// When.
+	transactions[0].Balance = 0
+	require.NoError(t, store.UpdateNegativeTransactions(context.Background(), transactions))
+	transactions, err = store.GetNegativeTransactions(context.Background(), accountId, 1)
+	require.NoError(t, err)
+
+	// Then.
+	require.Len(t, transactions, 2)
+	got, err := store.GetTransaction(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, model.Money(0), got.Balance)
+}
+
+func TestMemoryStore_Concurrent(t *testing.T) {
+	// Given.
+	store := NewMemory()
+	account, err := store.CreateAccount(context.Background(), documentNumber, model.DefaultCurrency)
+	require.NoError(t, err)
+
+	// When.
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := store.CreateTransaction(context.Background(), *model.NewTransaction(nil, *account.AccountID, 1, model.MustParseMoney("-1.00"), model.MustParseMoney("-1.00"), nil))
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	// Then.
+	transactions, err := store.GetNegativeTransactions(context.Background(), *account.AccountID, 1)
+	require.NoError(t, err)
+	assert.Len(t, transactions, 50)
+}
+
+func TestMemoryStore_SettlePayment(t *testing.T) {
+	// Given.
+	store := NewMemory()
+	account, err := store.CreateAccount(context.Background(), documentNumber, model.DefaultCurrency)
+	require.NoError(t, err)
+	accountId := *account.AccountID
+
+	_, err = store.CreateTransaction(context.Background(), *model.NewTransaction(nil, accountId, 1, model.MustParseMoney("-50.00"), model.MustParseMoney("-50.00"), nil))
+	require.NoError(t, err)
+	_, err = store.CreateTransaction(context.Background(), *model.NewTransaction(nil, accountId, 1, model.MustParseMoney("-100.00"), model.MustParseMoney("-100.00"), nil))
+	require.NoError(t, err)
+
+	// When.
+	payment, err := store.SettlePayment(context.Background(), *model.NewTransaction(nil, accountId, 4, model.MustParseMoney("60.00"), 0, nil))
+
+	// Then.
+	require.NoError(t, err)
+	assert.Equal(t, model.Money(0), payment.Balance)
+	transactions, err := store.GetNegativeTransactions(context.Background(), accountId, 1)
+	require.NoError(t, err)
+	require.Len(t, transactions, 1)
+	assert.Equal(t, model.MustParseMoney("-90.00"), transactions[0].Balance)
+
+	// When.
+	payment, err = store.SettlePayment(context.Background(), *model.NewTransaction(nil, accountId, 4, model.MustParseMoney("100.00"), 0, nil))
+
+	// Then.
+	require.NoError(t, err)
+	assert.Equal(t, model.MustParseMoney("10.00"), payment.Balance)
+	transactions, err = store.GetNegativeTransactions(context.Background(), accountId, 1)
+	require.NoError(t, err)
+	assert.Empty(t, transactions)
+}
+
+func TestMemoryStore_SettlePaymentUnknownAccount(t *testing.T) {
+	// Given.
+	store := NewMemory()
+
+	// When.
+	payment, err := store.SettlePayment(context.Background(), *model.NewTransaction(nil, invalidAccountId, 4, model.MustParseMoney("60.00"), 0, nil))
+
+	// Then.
+	require.Error(t, err)
+	assert.Nil(t, payment)
+}
+
+func TestMemoryStore_SettlePaymentAllDebits(t *testing.T) {
+	// Given.
+	store := NewMemory()
+	store.SettlementPolicy = model.SettlementPolicy{OperationPriority: []int{3}}
+	account, err := store.CreateAccount(context.Background(), documentNumber, model.DefaultCurrency)
+	require.NoError(t, err)
+	accountId := *account.AccountID
+
+	for _, operationTypeId := range []int{1, 2, 3} {
+		_, err = store.CreateTransaction(context.Background(), *model.NewTransaction(nil, accountId, operationTypeId, model.MustParseMoney("-40.00"), model.MustParseMoney("-40.00"), nil))
+		require.NoError(t, err)
+	}
+
+	// When.
+	_, err = store.SettlePayment(context.Background(), *model.NewTransaction(nil, accountId, 4, model.MustParseMoney("60.00"), 0, nil))
+	require.NoError(t, err)
+
+	// Then.
+	// The withdrawal goes first, then the purchase as it is older.
+	var balances []model.Money
+	for transactionId := 1; transactionId <= 3; transactionId++ {
+		transaction, err := store.GetTransaction(context.Background(), transactionId)
+		require.NoError(t, err)
+		balances = append(balances, transaction.Balance)
+	}
+	assert.Equal(t, []model.Money{model.MustParseMoney("-20.00"), model.MustParseMoney("-40.00"), 0}, balances)
+}
+
+func TestMemoryStore_ListTransactions(t *testing.T) {
+	// Given.
+	store := NewMemory()
+	account, err := store.CreateAccount(context.Background(), documentNumber, model.DefaultCurrency)
+	require.NoError(t, err)
+	accountId := *account.AccountID
+	other, err := store.CreateAccount(context.Background(), documentNumber, model.DefaultCurrency)
+	require.NoError(t, err)
+
+	start := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	for day := range 5 {
+		store.now = func() time.Time { return start.AddDate(0, 0, day) }
+		operationTypeId := 1
+		if day%2 == 1 {
+			operationTypeId = 4
+		}
+		_, err := store.CreateTransaction(context.Background(), *model.NewTransaction(nil, accountId, operationTypeId, 100, 100, nil))
+		require.NoError(t, err)
+	}
+	_, err = store.CreateTransaction(context.Background(), *model.NewTransaction(nil, *other.AccountID, 1, 100, 100, nil))
+	require.NoError(t, err)
+
+	ids := func(transactions model.Transactions) []int {
+		ids := []int{}
+		for _, transaction := range transactions {
+			ids = append(ids, *transaction.TransactionID)
+		}
+		return ids
+	}
+
+	// When.
+	page, err := store.ListTransactions(context.Background(), accountId, model.TransactionFilter{Limit: 2})
+	require.NoError(t, err)
+	next, err := store.ListTransactions(context.Background(), accountId, model.TransactionFilter{Limit: 10, After: model.CursorAfter(page[1])})
+	require.NoError(t, err)
+
+	// Then.
+	assert.Equal(t, []int{1, 2}, ids(page))
+	assert.Equal(t, []int{3, 4, 5}, ids(next))
+
+	// When.
+	from, to := start.AddDate(0, 0, 1), start.AddDate(0, 0, 4)
+	filtered, err := store.ListTransactions(context.Background(), accountId, model.TransactionFilter{
+		OperationTypeID: model.IntToPtr(1),
+		From:            &from,
+		To:              &to,
+		Limit:           10,
+	})
+	require.NoError(t, err)
+	descending, err := store.ListTransactions(context.Background(), accountId, model.TransactionFilter{
+		Descending: true,
+		After:      &model.Cursor{EventDate: start.AddDate(0, 0, 3), TransactionID: 4},
+		Limit:      10,
+	})
+	require.NoError(t, err)
+
+	// Then.
+	assert.Equal(t, []int{3}, ids(filtered))
+	assert.Equal(t, []int{3, 2, 1}, ids(descending))
+}
+
+func TestMemoryStore_Cancelled(t *testing.T) {
+	// Given.
+	store := NewMemory()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// When.
+	account, err := store.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+
+	// Then.
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, account)
+	_, err = store.GetAccount(context.Background(), 1)
+	require.ErrorIs(t, err, ErrAccountNotFound)
+}
+
+func TestMemoryStore_CreateInstallmentPlanFailureLeavesNothing(t *testing.T) {
+	// Given.
+	store := NewMemory()
+	account, err := store.CreateAccount(context.Background(), documentNumber, model.DefaultCurrency)
+	require.NoError(t, err)
+	accountId := *account.AccountID
+	// A credit in another currency makes settling the installments fail.
+	credit := model.NewTransaction(nil, accountId, 4, model.MustParseMoney("10.00"), model.MustParseMoney("10.00"), nil)
+	credit.Currency = "EUR"
+	store.insertTransaction(*credit)
+
+	// When.
+	_, err = store.CreateInstallmentPlan(context.Background(), *model.NewTransaction(nil, accountId, 2, model.MustParseMoney("-90.00"), 0, nil), 3)
+
+	// Then.
+	require.ErrorIs(t, err, model.ErrCurrencyMismatch)
+	_, err = store.GetInstallmentPlan(context.Background(), 1)
+	require.ErrorIs(t, err, ErrInstallmentPlanNotFound)
+	assert.Len(t, store.transactions, 1)
+}
+
+func TestMemoryStore_CreateTransferFailureLeavesNothing(t *testing.T) {
+	// Given.
+	store := NewMemory()
+	source, err := store.CreateAccount(context.Background(), documentNumber, model.DefaultCurrency)
+	require.NoError(t, err)
+	destination, err := store.CreateAccount(context.Background(), documentNumber, model.DefaultCurrency)
+	require.NoError(t, err)
+	// A debt in another currency makes settling the credit side fail.
+	debt := model.NewTransaction(nil, *destination.AccountID, 1, model.MustParseMoney("-10.00"), model.MustParseMoney("-10.00"), nil)
+	debt.Currency = "EUR"
+	store.insertTransaction(*debt)
+
+	// When.
+	_, err = store.CreateTransfer(context.Background(), *model.NewTransfer(model.TransferRequest{SourceAccountID: *source.AccountID, DestinationAccountID: *destination.AccountID, Amount: model.MustParseMoney("25.00")}))
+
+	// Then.
+	require.ErrorIs(t, err, model.ErrCurrencyMismatch)
+	_, err = store.GetTransfer(context.Background(), 1)
+	require.ErrorIs(t, err, ErrTransferNotFound)
+	assert.Len(t, store.transactions, 1)
+}
@@ -0,0 +1,61 @@
+package store
+
+import (
+	"account-transactions/migrate"
+	"embed"
+	"io/fs"
+)
+
+//go:embed migrations/mysql/*.sql migrations/postgres/*.sql migrations/sqlite/*.sql
+var migrations embed.FS
+
+// MySQLMigrations returns the MySQL schema migrations.
+func MySQLMigrations() fs.FS {
+	return migrationsDir("mysql")
+}
+
+// PostgresMigrations returns the Postgres schema migrations. They must
+// build the same schema as MySQLMigrations, version for version.
+func PostgresMigrations() fs.FS {
+	return migrationsDir("postgres")
+}
+
+// SQLiteMigrations returns the SQLite schema migrations, version for
+// version like PostgresMigrations.
+func SQLiteMigrations() fs.FS {
+	return migrationsDir("sqlite")
+}
+
+func migrationsDir(name string) fs.FS {
+	sub, err := fs.Sub(migrations, "migrations/"+name)
+	if err != nil {
+		panic(err) // The path is fixed by the embed directive.
+	}
+	return sub
+}
+
+// Migrator returns a migrator for the store's schema. On MySQL and
+// Postgres it takes a session lock while migrating, so instances that
+// auto-migrate at the same time wait for each other; SQLite connections
+// already begin transactions with an exclusive write lock.
+func (s *StoreImpl) Migrator() (*migrate.Migrator, error) {
+	if isSQLite(s.db) {
+		return migrate.New(s.db, SQLiteMigrations())
+	}
+	if isPostgres(s.db) {
+		m, err := migrate.New(s.db, PostgresMigrations())
+		if err != nil {
+			return nil, err
+		}
+		m.Lock = "SELECT 1 FROM pg_advisory_lock(hashtext('" + migrate.VersionTable + "'))"
+		m.Unlock = "SELECT pg_advisory_unlock(hashtext('" + migrate.VersionTable + "'))"
+		return m, nil
+	}
+	m, err := migrate.New(s.db, MySQLMigrations())
+	if err != nil {
+		return nil, err
+	}
+	m.Lock = "SELECT GET_LOCK('" + migrate.VersionTable + "', -1)"
+	m.Unlock = "SELECT RELEASE_LOCK('" + migrate.VersionTable + "')"
+	return m, nil
+}
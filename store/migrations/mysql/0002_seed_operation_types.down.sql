@@ -0,0 +1 @@
+DELETE FROM OperationsTypes WHERE OperationType_ID IN (1, 2, 3, 4);
@@ -0,0 +1,13 @@
+-- A NULL credit limit means the account has no limit.
+ALTER TABLE Accounts ADD COLUMN Credit_Limit DECIMAL (18,2) NULL;
+
+CREATE TABLE CreditLimitChanges (
+    Change_ID int NOT NULL auto_increment,
+    Account_ID int NOT NULL,
+    Old_Limit DECIMAL (18,2) NULL,
+    New_Limit DECIMAL (18,2) NOT NULL,
+    Reason VARCHAR (255) NOT NULL,
+    Changed_At DATETIME NOT NULL,
+    PRIMARY KEY (Change_ID),
+    FOREIGN KEY (Account_ID) REFERENCES Accounts(Account_ID)
+);
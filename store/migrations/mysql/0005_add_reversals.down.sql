@@ -0,0 +1,4 @@
+DELETE FROM OperationsTypes WHERE OperationType_ID IN (5, 6);
+ALTER TABLE Transactions
+    DROP FOREIGN KEY Transactions_Original_Transaction_ID,
+    DROP COLUMN Original_Transaction_ID;
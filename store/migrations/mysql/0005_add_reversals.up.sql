@@ -0,0 +1,10 @@
+-- Reversals reference the transaction they reverse. The IDs are referenced
+-- by model.OperationTypeRefund and model.OperationTypePaymentReversal.
+ALTER TABLE Transactions
+    ADD COLUMN Original_Transaction_ID int NULL,
+    ADD CONSTRAINT Transactions_Original_Transaction_ID FOREIGN KEY (Original_Transaction_ID) REFERENCES Transactions(Transaction_ID);
+
+INSERT INTO OperationsTypes ( OperationType_ID, Description, Direction )
+VALUES
+(5, 'REFUND', 'CREDIT'),
+(6, 'PAYMENT REVERSAL', 'DEBIT');
@@ -0,0 +1,21 @@
+-- Authorizations hold credit for debits that are posted when they are
+-- captured. Only PENDING authorizations that have not expired hold credit.
+CREATE TABLE Authorizations (
+    Authorization_ID int NOT NULL auto_increment,
+    Account_ID int NOT NULL,
+    OperationType_ID int NOT NULL,
+    Amount DECIMAL (18,2) NOT NULL,
+    Status ENUM ('PENDING', 'CAPTURED', 'VOIDED', 'EXPIRED') NOT NULL,
+    Created_At DATETIME NOT NULL,
+    Expires_At DATETIME NOT NULL,
+    Closed_At DATETIME NULL,
+    Transaction_ID int NULL,
+    PRIMARY KEY (Authorization_ID),
+    FOREIGN KEY (Account_ID) REFERENCES Accounts(Account_ID),
+    FOREIGN KEY (OperationType_ID) REFERENCES OperationsTypes(OperationType_ID),
+    FOREIGN KEY (Transaction_ID) REFERENCES Transactions(Transaction_ID)
+);
+
+CREATE INDEX Authorizations_Account_ID ON Authorizations (Account_ID, Status, Expires_At);
+
+CREATE INDEX Authorizations_Status ON Authorizations (Status, Expires_At);
@@ -0,0 +1,5 @@
+ALTER TABLE Transactions
+    DROP FOREIGN KEY Transactions_Plan_ID,
+    DROP COLUMN Plan_ID,
+    DROP COLUMN Due_Date;
+DROP TABLE InstallmentPlans;
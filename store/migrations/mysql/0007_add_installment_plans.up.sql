@@ -0,0 +1,18 @@
+-- Installment plans split an installment purchase into monthly debits,
+-- which reference their plan and are not outstanding until their due date.
+CREATE TABLE InstallmentPlans (
+    Plan_ID int NOT NULL auto_increment,
+    Account_ID int NOT NULL,
+    OperationType_ID int NOT NULL,
+    Amount DECIMAL (18,2) NOT NULL,
+    Installment_Count int NOT NULL,
+    Created_At DATETIME NOT NULL,
+    PRIMARY KEY (Plan_ID),
+    FOREIGN KEY (Account_ID) REFERENCES Accounts(Account_ID),
+    FOREIGN KEY (OperationType_ID) REFERENCES OperationsTypes(OperationType_ID)
+);
+
+ALTER TABLE Transactions
+    ADD COLUMN Plan_ID int NULL,
+    ADD COLUMN Due_Date DATETIME NULL,
+    ADD CONSTRAINT Transactions_Plan_ID FOREIGN KEY (Plan_ID) REFERENCES InstallmentPlans(Plan_ID);
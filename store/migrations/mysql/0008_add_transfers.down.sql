@@ -0,0 +1,5 @@
+DELETE FROM OperationsTypes WHERE OperationType_ID IN (7, 8);
+ALTER TABLE Transactions
+    DROP FOREIGN KEY Transactions_Transfer_ID,
+    DROP COLUMN Transfer_ID;
+DROP TABLE Transfers;
@@ -0,0 +1,23 @@
+-- Transfers move funds between accounts as a TRANSFER OUT debit on the
+-- source and a TRANSFER IN credit on the destination, which both reference
+-- their transfer. The IDs are referenced by model.OperationTypeTransferOut
+-- and model.OperationTypeTransferIn.
+CREATE TABLE Transfers (
+    Transfer_ID int NOT NULL auto_increment,
+    Source_Account_ID int NOT NULL,
+    Destination_Account_ID int NOT NULL,
+    Amount DECIMAL (18,2) NOT NULL,
+    Created_At DATETIME NOT NULL,
+    PRIMARY KEY (Transfer_ID),
+    FOREIGN KEY (Source_Account_ID) REFERENCES Accounts(Account_ID),
+    FOREIGN KEY (Destination_Account_ID) REFERENCES Accounts(Account_ID)
+);
+
+ALTER TABLE Transactions
+    ADD COLUMN Transfer_ID int NULL,
+    ADD CONSTRAINT Transactions_Transfer_ID FOREIGN KEY (Transfer_ID) REFERENCES Transfers(Transfer_ID);
+
+INSERT INTO OperationsTypes ( OperationType_ID, Description, Direction )
+VALUES
+(7, 'TRANSFER OUT', 'DEBIT'),
+(8, 'TRANSFER IN', 'CREDIT');
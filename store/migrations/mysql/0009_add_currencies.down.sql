@@ -0,0 +1,12 @@
+DROP TABLE FXRates;
+ALTER TABLE InstallmentPlans
+    DROP COLUMN FX_Rate,
+    DROP COLUMN Original_Currency,
+    DROP COLUMN Original_Amount,
+    DROP COLUMN Currency;
+ALTER TABLE Transactions
+    DROP COLUMN FX_Rate,
+    DROP COLUMN Original_Currency,
+    DROP COLUMN Original_Amount,
+    DROP COLUMN Currency;
+ALTER TABLE Accounts DROP COLUMN Currency;
@@ -0,0 +1,26 @@
+-- Accounts and their transactions are in one ISO 4217 currency, USD for
+-- those that existed before (model.DefaultCurrency). Transactions sent in
+-- another currency keep what they were sent in and the rate they were
+-- converted with.
+ALTER TABLE Accounts ADD COLUMN Currency CHAR (3) NOT NULL DEFAULT 'USD';
+
+ALTER TABLE Transactions
+    ADD COLUMN Currency CHAR (3) NOT NULL DEFAULT 'USD',
+    ADD COLUMN Original_Amount DECIMAL (18,2) NULL,
+    ADD COLUMN Original_Currency CHAR (3) NULL,
+    ADD COLUMN FX_Rate DECIMAL (18,8) NULL;
+
+ALTER TABLE InstallmentPlans
+    ADD COLUMN Currency CHAR (3) NOT NULL DEFAULT 'USD',
+    ADD COLUMN Original_Amount DECIMAL (18,2) NULL,
+    ADD COLUMN Original_Currency CHAR (3) NULL,
+    ADD COLUMN FX_Rate DECIMAL (18,8) NULL;
+
+-- One unit of Base_Currency is worth Rate units of Quote_Currency.
+CREATE TABLE FXRates (
+    Base_Currency CHAR (3) NOT NULL,
+    Quote_Currency CHAR (3) NOT NULL,
+    Rate DECIMAL (18,8) NOT NULL,
+    Updated_At DATETIME NOT NULL,
+    PRIMARY KEY (Base_Currency, Quote_Currency)
+);
@@ -0,0 +1,2 @@
+DROP TABLE AccountStatusChanges;
+ALTER TABLE Accounts DROP COLUMN Status;
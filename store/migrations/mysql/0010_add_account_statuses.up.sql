@@ -0,0 +1,14 @@
+-- Accounts are active until blocked or closed; accounts that existed
+-- before are active.
+ALTER TABLE Accounts ADD COLUMN Status ENUM ('ACTIVE', 'BLOCKED', 'CLOSED') NOT NULL DEFAULT 'ACTIVE';
+
+CREATE TABLE AccountStatusChanges (
+    Change_ID int NOT NULL auto_increment,
+    Account_ID int NOT NULL,
+    Old_Status ENUM ('ACTIVE', 'BLOCKED', 'CLOSED') NOT NULL,
+    New_Status ENUM ('ACTIVE', 'BLOCKED', 'CLOSED') NOT NULL,
+    Reason VARCHAR (255) NOT NULL,
+    Changed_At DATETIME NOT NULL,
+    PRIMARY KEY (Change_ID),
+    FOREIGN KEY (Account_ID) REFERENCES Accounts(Account_ID)
+);
@@ -0,0 +1,13 @@
+-- Allocations record how much of a debit each credit, e.g. a payment,
+-- settled, so reversals can give it back. They are only added to: a
+-- released allocation is recorded again with a negative amount.
+-- Settlements made before this migration are not recorded.
+CREATE TABLE Allocations (
+    Allocation_ID int NOT NULL auto_increment,
+    Credit_Transaction_ID int NOT NULL,
+    Debit_Transaction_ID int NOT NULL,
+    Amount DECIMAL (18,2) NOT NULL,
+    PRIMARY KEY (Allocation_ID),
+    FOREIGN KEY (Credit_Transaction_ID) REFERENCES Transactions(Transaction_ID),
+    FOREIGN KEY (Debit_Transaction_ID) REFERENCES Transactions(Transaction_ID)
+);
@@ -0,0 +1,27 @@
+CREATE TABLE Accounts (
+    Account_ID SERIAL NOT NULL,
+    Document_Number BIGINT NOT NULL,
+    PRIMARY KEY (Account_ID)
+);
+
+CREATE TABLE OperationsTypes (
+    OperationType_ID SERIAL NOT NULL,
+    Description VARCHAR (255) NOT NULL,
+    Direction VARCHAR (6) NOT NULL CHECK (Direction IN ('DEBIT', 'CREDIT')),
+    PRIMARY KEY (OperationType_ID)
+);
+
+CREATE TABLE Transactions (
+    Transaction_ID SERIAL NOT NULL,
+    Account_ID int NOT NULL,
+    OperationType_ID int NOT NULL,
+    Amount NUMERIC (18,2) NOT NULL,
+    Balance NUMERIC (18,2) DEFAULT 0.00 NOT NULL,
+    EventDate TIMESTAMP (0) NOT NULL,
+    PRIMARY KEY (Transaction_ID),
+    FOREIGN KEY (Account_ID) REFERENCES Accounts(Account_ID),
+    FOREIGN KEY (OperationType_ID) REFERENCES OperationsTypes(OperationType_ID)
+);
+
+-- Unlike MySQL, Postgres does not index foreign keys by itself.
+CREATE INDEX Transactions_Account_ID ON Transactions (Account_ID, EventDate, Transaction_ID);
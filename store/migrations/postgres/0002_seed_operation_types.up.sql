@@ -0,0 +1,10 @@
+-- The IDs are referenced by model.OperationTypePurchase and friends.
+INSERT INTO OperationsTypes ( OperationType_ID, Description, Direction )
+VALUES
+(1, 'PURCHASE', 'DEBIT'),
+(2, 'INSTALLMENT PURCHASE', 'DEBIT'),
+(3, 'WITHDRAWAL', 'DEBIT'),
+(4, 'PAYMENT', 'CREDIT');
+
+-- Explicit IDs don't advance the sequence.
+SELECT setval(pg_get_serial_sequence('OperationsTypes', 'operationtype_id'), 4);
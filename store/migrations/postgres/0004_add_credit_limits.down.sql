@@ -0,0 +1,2 @@
+DROP TABLE CreditLimitChanges;
+ALTER TABLE Accounts DROP COLUMN Credit_Limit;
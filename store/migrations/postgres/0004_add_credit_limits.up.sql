@@ -0,0 +1,15 @@
+-- A NULL credit limit means the account has no limit.
+ALTER TABLE Accounts ADD COLUMN Credit_Limit NUMERIC (18,2) NULL;
+
+CREATE TABLE CreditLimitChanges (
+    Change_ID SERIAL NOT NULL,
+    Account_ID int NOT NULL,
+    Old_Limit NUMERIC (18,2) NULL,
+    New_Limit NUMERIC (18,2) NOT NULL,
+    Reason VARCHAR (255) NOT NULL,
+    Changed_At TIMESTAMP (0) NOT NULL,
+    PRIMARY KEY (Change_ID),
+    FOREIGN KEY (Account_ID) REFERENCES Accounts(Account_ID)
+);
+
+CREATE INDEX CreditLimitChanges_Account_ID ON CreditLimitChanges (Account_ID, Change_ID);
@@ -0,0 +1,2 @@
+DELETE FROM OperationsTypes WHERE OperationType_ID IN (5, 6);
+ALTER TABLE Transactions DROP COLUMN Original_Transaction_ID;
@@ -0,0 +1,13 @@
+-- Reversals reference the transaction they reverse. The IDs are referenced
+-- by model.OperationTypeRefund and model.OperationTypePaymentReversal.
+ALTER TABLE Transactions ADD COLUMN Original_Transaction_ID int NULL REFERENCES Transactions(Transaction_ID);
+
+CREATE INDEX Transactions_Original_Transaction_ID ON Transactions (Original_Transaction_ID);
+
+INSERT INTO OperationsTypes ( OperationType_ID, Description, Direction )
+VALUES
+(5, 'REFUND', 'CREDIT'),
+(6, 'PAYMENT REVERSAL', 'DEBIT');
+
+-- Explicit IDs don't advance the sequence.
+SELECT setval(pg_get_serial_sequence('OperationsTypes', 'operationtype_id'), (SELECT MAX(OperationType_ID) FROM OperationsTypes));
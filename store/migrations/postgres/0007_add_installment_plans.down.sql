@@ -0,0 +1,4 @@
+ALTER TABLE Transactions
+    DROP COLUMN Plan_ID,
+    DROP COLUMN Due_Date;
+DROP TABLE InstallmentPlans;
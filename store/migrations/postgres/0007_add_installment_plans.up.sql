@@ -0,0 +1,19 @@
+-- Installment plans split an installment purchase into monthly debits,
+-- which reference their plan and are not outstanding until their due date.
+CREATE TABLE InstallmentPlans (
+    Plan_ID SERIAL NOT NULL,
+    Account_ID int NOT NULL,
+    OperationType_ID int NOT NULL,
+    Amount NUMERIC (18,2) NOT NULL,
+    Installment_Count int NOT NULL,
+    Created_At TIMESTAMP (0) NOT NULL,
+    PRIMARY KEY (Plan_ID),
+    FOREIGN KEY (Account_ID) REFERENCES Accounts(Account_ID),
+    FOREIGN KEY (OperationType_ID) REFERENCES OperationsTypes(OperationType_ID)
+);
+
+ALTER TABLE Transactions
+    ADD COLUMN Plan_ID int NULL REFERENCES InstallmentPlans(Plan_ID),
+    ADD COLUMN Due_Date TIMESTAMP (0) NULL;
+
+CREATE INDEX Transactions_Plan_ID ON Transactions (Plan_ID, Due_Date);
@@ -0,0 +1,3 @@
+DELETE FROM OperationsTypes WHERE OperationType_ID IN (7, 8);
+ALTER TABLE Transactions DROP COLUMN Transfer_ID;
+DROP TABLE Transfers;
@@ -0,0 +1,26 @@
+-- Transfers move funds between accounts as a TRANSFER OUT debit on the
+-- source and a TRANSFER IN credit on the destination, which both reference
+-- their transfer. The IDs are referenced by model.OperationTypeTransferOut
+-- and model.OperationTypeTransferIn.
+CREATE TABLE Transfers (
+    Transfer_ID SERIAL NOT NULL,
+    Source_Account_ID int NOT NULL,
+    Destination_Account_ID int NOT NULL,
+    Amount NUMERIC (18,2) NOT NULL,
+    Created_At TIMESTAMP (0) NOT NULL,
+    PRIMARY KEY (Transfer_ID),
+    FOREIGN KEY (Source_Account_ID) REFERENCES Accounts(Account_ID),
+    FOREIGN KEY (Destination_Account_ID) REFERENCES Accounts(Account_ID)
+);
+
+ALTER TABLE Transactions ADD COLUMN Transfer_ID int NULL REFERENCES Transfers(Transfer_ID);
+
+CREATE INDEX Transactions_Transfer_ID ON Transactions (Transfer_ID);
+
+INSERT INTO OperationsTypes ( OperationType_ID, Description, Direction )
+VALUES
+(7, 'TRANSFER OUT', 'DEBIT'),
+(8, 'TRANSFER IN', 'CREDIT');
+
+-- Explicit IDs don't advance the sequence.
+SELECT setval(pg_get_serial_sequence('OperationsTypes', 'operationtype_id'), (SELECT MAX(OperationType_ID) FROM OperationsTypes));
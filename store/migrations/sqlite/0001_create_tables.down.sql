@@ -0,0 +1,3 @@
+DROP TABLE Transactions;
+DROP TABLE OperationsTypes;
+DROP TABLE Accounts;
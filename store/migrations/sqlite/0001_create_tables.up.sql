@@ -0,0 +1,25 @@
+-- SQLite has no exact DECIMAL, so amounts are stored as integer cents in
+-- columns declared CENTS; the store converts them to and from model.Money.
+CREATE TABLE Accounts (
+    Account_ID INTEGER PRIMARY KEY AUTOINCREMENT,
+    Document_Number BIGINT NOT NULL
+);
+
+CREATE TABLE OperationsTypes (
+    OperationType_ID INTEGER PRIMARY KEY AUTOINCREMENT,
+    Description VARCHAR (255) NOT NULL,
+    Direction VARCHAR (6) NOT NULL CHECK (Direction IN ('DEBIT', 'CREDIT'))
+);
+
+CREATE TABLE Transactions (
+    Transaction_ID INTEGER PRIMARY KEY AUTOINCREMENT,
+    Account_ID int NOT NULL,
+    OperationType_ID int NOT NULL,
+    Amount CENTS NOT NULL CHECK (typeof(Amount) = 'integer'),
+    Balance CENTS DEFAULT 0 NOT NULL CHECK (typeof(Balance) = 'integer'),
+    EventDate DATETIME NOT NULL,
+    FOREIGN KEY (Account_ID) REFERENCES Accounts(Account_ID),
+    FOREIGN KEY (OperationType_ID) REFERENCES OperationsTypes(OperationType_ID)
+);
+
+CREATE INDEX Transactions_Account_ID ON Transactions (Account_ID, EventDate, Transaction_ID);
@@ -0,0 +1,7 @@
+-- The IDs are referenced by model.OperationTypePurchase and friends.
+INSERT INTO OperationsTypes ( OperationType_ID, Description, Direction )
+VALUES
+(1, 'PURCHASE', 'DEBIT'),
+(2, 'INSTALLMENT PURCHASE', 'DEBIT'),
+(3, 'WITHDRAWAL', 'DEBIT'),
+(4, 'PAYMENT', 'CREDIT');
@@ -0,0 +1,9 @@
+CREATE TABLE IdempotencyKeys (
+    Idempotency_Key VARCHAR (255) NOT NULL,
+    Request_Hash CHAR (64) NOT NULL,
+    Status_Code int DEFAULT 0 NOT NULL,
+    Content_Type VARCHAR (255) DEFAULT '' NOT NULL,
+    Response_Body BLOB,
+    Created_At DATETIME NOT NULL,
+    PRIMARY KEY (Idempotency_Key)
+);
@@ -0,0 +1,14 @@
+-- A NULL credit limit means the account has no limit.
+ALTER TABLE Accounts ADD COLUMN Credit_Limit CENTS NULL CHECK (Credit_Limit IS NULL OR typeof(Credit_Limit) = 'integer');
+
+CREATE TABLE CreditLimitChanges (
+    Change_ID INTEGER PRIMARY KEY AUTOINCREMENT,
+    Account_ID int NOT NULL,
+    Old_Limit CENTS NULL CHECK (Old_Limit IS NULL OR typeof(Old_Limit) = 'integer'),
+    New_Limit CENTS NOT NULL CHECK (typeof(New_Limit) = 'integer'),
+    Reason VARCHAR (255) NOT NULL,
+    Changed_At DATETIME NOT NULL,
+    FOREIGN KEY (Account_ID) REFERENCES Accounts(Account_ID)
+);
+
+CREATE INDEX CreditLimitChanges_Account_ID ON CreditLimitChanges (Account_ID, Change_ID);
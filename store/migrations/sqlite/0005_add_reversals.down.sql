@@ -0,0 +1,3 @@
+DELETE FROM OperationsTypes WHERE OperationType_ID IN (5, 6);
+DROP INDEX Transactions_Original_Transaction_ID;
+ALTER TABLE Transactions DROP COLUMN Original_Transaction_ID;
@@ -0,0 +1,13 @@
+-- Reversals reference the transaction they reverse. The IDs are referenced
+-- by model.OperationTypeRefund and model.OperationTypePaymentReversal.
+--
+-- SQLite cannot drop a column that is part of a foreign key, so unlike the
+-- other databases the link is not declared as one.
+ALTER TABLE Transactions ADD COLUMN Original_Transaction_ID int NULL;
+
+CREATE INDEX Transactions_Original_Transaction_ID ON Transactions (Original_Transaction_ID);
+
+INSERT INTO OperationsTypes ( OperationType_ID, Description, Direction )
+VALUES
+(5, 'REFUND', 'CREDIT'),
+(6, 'PAYMENT REVERSAL', 'DEBIT');
@@ -0,0 +1,4 @@
+DROP INDEX Transactions_Plan_ID;
+ALTER TABLE Transactions DROP COLUMN Plan_ID;
+ALTER TABLE Transactions DROP COLUMN Due_Date;
+DROP TABLE InstallmentPlans;
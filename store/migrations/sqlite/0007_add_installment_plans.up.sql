@@ -0,0 +1,20 @@
+-- Installment plans split an installment purchase into monthly debits,
+-- which reference their plan and are not outstanding until their due date.
+CREATE TABLE InstallmentPlans (
+    Plan_ID INTEGER PRIMARY KEY AUTOINCREMENT,
+    Account_ID int NOT NULL,
+    OperationType_ID int NOT NULL,
+    Amount CENTS NOT NULL CHECK (typeof(Amount) = 'integer'),
+    Installment_Count int NOT NULL,
+    Created_At DATETIME NOT NULL,
+    FOREIGN KEY (Account_ID) REFERENCES Accounts(Account_ID),
+    FOREIGN KEY (OperationType_ID) REFERENCES OperationsTypes(OperationType_ID)
+);
+
+-- SQLite cannot drop a column that is part of a foreign key, so unlike the
+-- other databases the link to the plan is not declared as one.
+ALTER TABLE Transactions ADD COLUMN Plan_ID int NULL;
+
+ALTER TABLE Transactions ADD COLUMN Due_Date DATETIME NULL;
+
+CREATE INDEX Transactions_Plan_ID ON Transactions (Plan_ID, Due_Date);
@@ -0,0 +1,4 @@
+DELETE FROM OperationsTypes WHERE OperationType_ID IN (7, 8);
+DROP INDEX Transactions_Transfer_ID;
+ALTER TABLE Transactions DROP COLUMN Transfer_ID;
+DROP TABLE Transfers;
@@ -0,0 +1,24 @@
+-- Transfers move funds between accounts as a TRANSFER OUT debit on the
+-- source and a TRANSFER IN credit on the destination, which both reference
+-- their transfer. The IDs are referenced by model.OperationTypeTransferOut
+-- and model.OperationTypeTransferIn.
+CREATE TABLE Transfers (
+    Transfer_ID INTEGER PRIMARY KEY AUTOINCREMENT,
+    Source_Account_ID int NOT NULL,
+    Destination_Account_ID int NOT NULL,
+    Amount CENTS NOT NULL CHECK (typeof(Amount) = 'integer'),
+    Created_At DATETIME NOT NULL,
+    FOREIGN KEY (Source_Account_ID) REFERENCES Accounts(Account_ID),
+    FOREIGN KEY (Destination_Account_ID) REFERENCES Accounts(Account_ID)
+);
+
+-- SQLite cannot drop a column that is part of a foreign key, so unlike the
+-- other databases the link to the transfer is not declared as one.
+ALTER TABLE Transactions ADD COLUMN Transfer_ID int NULL;
+
+CREATE INDEX Transactions_Transfer_ID ON Transactions (Transfer_ID);
+
+INSERT INTO OperationsTypes ( OperationType_ID, Description, Direction )
+VALUES
+(7, 'TRANSFER OUT', 'DEBIT'),
+(8, 'TRANSFER IN', 'CREDIT');
@@ -0,0 +1,10 @@
+DROP TABLE FXRates;
+ALTER TABLE InstallmentPlans DROP COLUMN FX_Rate;
+ALTER TABLE InstallmentPlans DROP COLUMN Original_Currency;
+ALTER TABLE InstallmentPlans DROP COLUMN Original_Amount;
+ALTER TABLE InstallmentPlans DROP COLUMN Currency;
+ALTER TABLE Transactions DROP COLUMN FX_Rate;
+ALTER TABLE Transactions DROP COLUMN Original_Currency;
+ALTER TABLE Transactions DROP COLUMN Original_Amount;
+ALTER TABLE Transactions DROP COLUMN Currency;
+ALTER TABLE Accounts DROP COLUMN Currency;
@@ -0,0 +1,25 @@
+-- Accounts and their transactions are in one ISO 4217 currency, USD for
+-- those that existed before (model.DefaultCurrency). Transactions sent in
+-- another currency keep what they were sent in and the rate they were
+-- converted with. SQLite has no exact decimal for rates either, so they
+-- are kept as the decimal text model.Rate writes.
+ALTER TABLE Accounts ADD COLUMN Currency CHAR (3) NOT NULL DEFAULT 'USD';
+
+ALTER TABLE Transactions ADD COLUMN Currency CHAR (3) NOT NULL DEFAULT 'USD';
+ALTER TABLE Transactions ADD COLUMN Original_Amount CENTS NULL CHECK (Original_Amount IS NULL OR typeof(Original_Amount) = 'integer');
+ALTER TABLE Transactions ADD COLUMN Original_Currency CHAR (3) NULL;
+ALTER TABLE Transactions ADD COLUMN FX_Rate TEXT NULL;
+
+ALTER TABLE InstallmentPlans ADD COLUMN Currency CHAR (3) NOT NULL DEFAULT 'USD';
+ALTER TABLE InstallmentPlans ADD COLUMN Original_Amount CENTS NULL CHECK (Original_Amount IS NULL OR typeof(Original_Amount) = 'integer');
+ALTER TABLE InstallmentPlans ADD COLUMN Original_Currency CHAR (3) NULL;
+ALTER TABLE InstallmentPlans ADD COLUMN FX_Rate TEXT NULL;
+
+-- One unit of Base_Currency is worth Rate units of Quote_Currency.
+CREATE TABLE FXRates (
+    Base_Currency CHAR (3) NOT NULL,
+    Quote_Currency CHAR (3) NOT NULL,
+    Rate TEXT NOT NULL,
+    Updated_At DATETIME NOT NULL,
+    PRIMARY KEY (Base_Currency, Quote_Currency)
+);
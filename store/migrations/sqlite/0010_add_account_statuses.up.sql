@@ -0,0 +1,15 @@
+-- Accounts are active until blocked or closed; accounts that existed
+-- before are active.
+ALTER TABLE Accounts ADD COLUMN Status VARCHAR (7) NOT NULL DEFAULT 'ACTIVE' CHECK (Status IN ('ACTIVE', 'BLOCKED', 'CLOSED'));
+
+CREATE TABLE AccountStatusChanges (
+    Change_ID INTEGER PRIMARY KEY AUTOINCREMENT,
+    Account_ID int NOT NULL,
+    Old_Status VARCHAR (7) NOT NULL CHECK (Old_Status IN ('ACTIVE', 'BLOCKED', 'CLOSED')),
+    New_Status VARCHAR (7) NOT NULL CHECK (New_Status IN ('ACTIVE', 'BLOCKED', 'CLOSED')),
+    Reason VARCHAR (255) NOT NULL,
+    Changed_At DATETIME NOT NULL,
+    FOREIGN KEY (Account_ID) REFERENCES Accounts(Account_ID)
+);
+
+CREATE INDEX AccountStatusChanges_Account_ID ON AccountStatusChanges (Account_ID, Change_ID);
@@ -0,0 +1,15 @@
+-- Allocations record how much of a debit each credit, e.g. a payment,
+-- settled, so reversals can give it back. They are only added to: a
+-- released allocation is recorded again with a negative amount.
+-- Settlements made before this migration are not recorded.
+CREATE TABLE Allocations (
+    Allocation_ID INTEGER PRIMARY KEY AUTOINCREMENT,
+    Credit_Transaction_ID int NOT NULL,
+    Debit_Transaction_ID int NOT NULL,
+    Amount CENTS NOT NULL CHECK (typeof(Amount) = 'integer'),
+    FOREIGN KEY (Credit_Transaction_ID) REFERENCES Transactions(Transaction_ID),
+    FOREIGN KEY (Debit_Transaction_ID) REFERENCES Transactions(Transaction_ID)
+);
+
+CREATE INDEX Allocations_Credit_Transaction_ID ON Allocations (Credit_Transaction_ID);
+CREATE INDEX Allocations_Debit_Transaction_ID ON Allocations (Debit_Transaction_ID);
@@ -0,0 +1,33 @@
+package store
+
+import (
+	"account-transactions/migrate"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrations(t *testing.T) {
+	// When.
+	mysql, err := migrate.Load(MySQLMigrations())
+	require.NoError(t, err)
+	postgres, err := migrate.Load(PostgresMigrations())
+	require.NoError(t, err)
+	sqlite, err := migrate.Load(SQLiteMigrations())
+	require.NoError(t, err)
+
+	// Then.
+	require.NotEmpty(t, mysql)
+	for i, migration := range mysql {
+		assert.Equal(t, i+1, migration.Version, "migration versions must have no gaps")
+	}
+	require.Len(t, postgres, len(mysql), "every migration needs a Postgres version")
+	for i := range postgres {
+		assert.Equal(t, mysql[i].String(), postgres[i].String())
+	}
+	require.Len(t, sqlite, len(mysql), "every migration needs a SQLite version")
+	for i := range sqlite {
+		assert.Equal(t, mysql[i].String(), sqlite[i].String())
+	}
+}
@@ -0,0 +1,63 @@
+package store
+
+import (
+	"account-transactions/config"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/reflectx"
+)
+
+// postgresDriver is the database/sql driver registered by pgx.
+const postgresDriver = "pgx"
+
+// NewPostgres connects to Postgres with the given configuration. It shares
+// its queries with the MySQL store; sqlx rebinds their placeholders to $n
+// and generated IDs are read back with RETURNING.
+func NewPostgres(cfg config.PostgresConfig) (*StoreImpl, error) {
+	db, err := connect(postgresDriver, postgresDSN(cfg), cfg.PoolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to postgres on %s:%d: %w", cfg.Host, cfg.Port, err)
+	}
+	usePostgresNames(db)
+
+	slog.Info("using store: postgres", "host", cfg.Host, "port", cfg.Port)
+	return &StoreImpl{
+		db: db,
+	}, nil
+}
+
+// usePostgresNames maps struct fields to column names as Postgres returns
+// them: it folds unquoted identifiers such as Account_ID to lower case.
+func usePostgresNames(db *sqlx.DB) {
+	db.Mapper = reflectx.NewMapperTagFunc("db", strings.ToLower, strings.ToLower)
+}
+
+// postgresDSN builds the Postgres connection URL from the configuration.
+func postgresDSN(cfg config.PostgresConfig) string {
+	query := url.Values{}
+	query.Set("sslmode", cfg.SSLMode)
+	if cfg.ConnectTimeout > 0 {
+		// connect_timeout is in whole seconds.
+		query.Set("connect_timeout", strconv.Itoa(max(1, int(cfg.ConnectTimeout.Seconds()))))
+	}
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(cfg.User, cfg.Password),
+		Host:     net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		Path:     "/" + cfg.Database,
+		RawQuery: query.Encode(),
+	}
+	return u.String()
+}
+
+// isPostgres reports whether q talks to Postgres.
+func isPostgres(q interface{ DriverName() string }) bool {
+	return q.DriverName() == postgresDriver
+}
@@ -0,0 +1,179 @@
+package store
+
+import (
+	"account-transactions/config"
+	"account-transactions/model"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"log/slog"
+	"net/url"
+
+	"github.com/jmoiron/sqlx"
+	"modernc.org/sqlite"
+)
+
+// sqliteDriver is the driver name of the SQLite store's *sqlx.DB. Its
+// connections come from sqliteConnector rather than a registered driver;
+// sqlx leaves the ? placeholders of unknown drivers as they are.
+const sqliteDriver = "sqlite"
+
+// sqliteCents is the declared type of the money columns in the SQLite
+// schema. SQLite has no exact decimal type, so they hold integer cents.
+const sqliteCents = "CENTS"
+
+// NewSQLite opens the SQLite database file at cfg.Path, creating it if it
+// does not exist. It shares its queries with the MySQL store.
+//
+// The database runs in WAL mode so readers don't block the writer, and
+// transactions begin IMMEDIATE: concurrent payments queue on the write
+// lock for up to cfg.BusyTimeout instead of failing when they upgrade from
+// reading to writing.
+func NewSQLite(cfg config.SQLiteConfig) (*StoreImpl, error) {
+	db := sqlx.NewDb(sql.OpenDB(sqliteConnector{dsn: sqliteDSN(cfg)}), sqliteDriver)
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("opening sqlite database %s: %w", cfg.Path, err)
+	}
+
+	slog.Info("using store: sqlite", "path", cfg.Path)
+	return &StoreImpl{
+		db: db,
+	}, nil
+}
+
+// sqliteDSN builds the modernc.org/sqlite data source name. The pragmas
+// are applied to every connection.
+func sqliteDSN(cfg config.SQLiteConfig) string {
+	query := url.Values{}
+	query.Add("_pragma", "foreign_keys(1)")
+	query.Add("_pragma", "journal_mode(WAL)")
+	query.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", cfg.BusyTimeout.Milliseconds()))
+	query.Set("_txlock", "immediate")
+	query.Set("_time_format", "sqlite")
+	return cfg.Path + "?" + query.Encode()
+}
+
+// isSQLite reports whether q talks to SQLite.
+func isSQLite(q interface{ DriverName() string }) bool {
+	return q.DriverName() == sqliteDriver
+}
+
+// sqliteConnector opens modernc.org/sqlite connections that store
+// model.Money as integer cents.
+type sqliteConnector struct {
+	dsn string
+}
+
+func (c sqliteConnector) Connect(context.Context) (driver.Conn, error) {
+	conn, err := c.Driver().Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &sqliteConn{conn.(sqliteDriverConn)}, nil
+}
+
+func (c sqliteConnector) Driver() driver.Driver {
+	return &sqlite.Driver{}
+}
+
+// sqliteDriverConn is what a modernc.org/sqlite connection implements.
+type sqliteDriverConn interface {
+	driver.Conn
+	driver.ConnBeginTx
+	driver.ConnPrepareContext
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+	driver.SessionResetter
+	driver.Validator
+}
+
+// sqliteConn writes model.Money arguments as cents and reads CENTS
+// columns back as decimal strings, which model.Money scans exactly.
+type sqliteConn struct {
+	sqliteDriverConn
+}
+
+func (c *sqliteConn) CheckNamedValue(nv *driver.NamedValue) error {
+	switch money := nv.Value.(type) {
+	case model.Money:
+		nv.Value = int64(money)
+	case *model.Money:
+		nv.Value = nil
+		if money != nil {
+			nv.Value = int64(*money)
+		}
+	default:
+		return driver.ErrSkip // The default conversion.
+	}
+	return nil
+}
+
+func (c *sqliteConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *sqliteConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	stmt, err := c.sqliteDriverConn.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &sqliteStmt{stmt.(sqliteDriverStmt)}, nil
+}
+
+func (c *sqliteConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	rows, err := c.sqliteDriverConn.QueryContext(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	return newSQLiteRows(rows), nil
+}
+
+// sqliteDriverStmt is what a modernc.org/sqlite statement implements.
+type sqliteDriverStmt interface {
+	driver.Stmt
+	driver.StmtExecContext
+	driver.StmtQueryContext
+}
+
+type sqliteStmt struct {
+	sqliteDriverStmt
+}
+
+func (s *sqliteStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	rows, err := s.sqliteDriverStmt.QueryContext(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return newSQLiteRows(rows), nil
+}
+
+type sqliteRows struct {
+	driver.Rows
+	// cents marks the CENTS columns.
+	cents []bool
+}
+
+func newSQLiteRows(rows driver.Rows) *sqliteRows {
+	r := &sqliteRows{Rows: rows, cents: make([]bool, len(rows.Columns()))}
+	if types, ok := rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		for i := range r.cents {
+			r.cents[i] = types.ColumnTypeDatabaseTypeName(i) == sqliteCents
+		}
+	}
+	return r
+}
+
+func (r *sqliteRows) Next(dest []driver.Value) error {
+	if err := r.Rows.Next(dest); err != nil {
+		return err
+	}
+	for i, cents := range r.cents {
+		if v, ok := dest[i].(int64); ok && cents {
+			dest[i] = model.Money(v).String()
+		}
+	}
+	return nil
+}
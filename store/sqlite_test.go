@@ -0,0 +1,139 @@
+package store
+
+import (
+	"account-transactions/config"
+	"account-transactions/model"
+	"context"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSQLiteStore returns a migrated store on a new database file.
+func newSQLiteStore(t *testing.T) *StoreImpl {
+	s, err := NewSQLite(config.SQLiteConfig{Path: filepath.Join(t.TempDir(), "store.db"), BusyTimeout: 5 * time.Second})
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close() })
+
+	m, err := s.Migrator()
+	require.NoError(t, err)
+	_, err = m.Up(context.Background())
+	require.NoError(t, err)
+	return s
+}
+
+func TestSQLiteDSN(t *testing.T) {
+	// When.
+	dsn := sqliteDSN(config.SQLiteConfig{Path: "data/store.db", BusyTimeout: 2 * time.Second})
+
+	// Then.
+	path, rawQuery, ok := strings.Cut(dsn, "?")
+	require.True(t, ok)
+	assert.Equal(t, "data/store.db", path)
+	query, err := url.ParseQuery(rawQuery)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"foreign_keys(1)", "journal_mode(WAL)", "busy_timeout(2000)"}, query["_pragma"])
+	assert.Equal(t, "immediate", query.Get("_txlock"))
+}
+
+func TestSQLite_MoneyIsExact(t *testing.T) {
+	// Given.
+	s := newSQLiteStore(t)
+	account, err := s.CreateAccount(context.Background(), documentNumber, model.DefaultCurrency)
+	require.NoError(t, err)
+
+	// When.
+	created, err := s.CreateTransaction(context.Background(), *model.NewTransaction(nil, *account.AccountID, 1, model.MustParseMoney("-0.10"), model.MustParseMoney("-1234567890123.45"), nil))
+	require.NoError(t, err)
+	got, err := s.GetTransaction(context.Background(), *created.TransactionID)
+	require.NoError(t, err)
+
+	// Then.
+	assert.Equal(t, model.MustParseMoney("-0.10"), got.Amount)
+	assert.Equal(t, model.MustParseMoney("-1234567890123.45"), got.Balance)
+	var storedType string
+	var cents int64
+	require.NoError(t, s.db.QueryRow("SELECT typeof(Balance), CAST(Balance AS INTEGER) FROM Transactions").Scan(&storedType, &cents))
+	assert.Equal(t, "integer", storedType)
+	assert.Equal(t, int64(-123456789012345), cents)
+}
+
+func TestSQLite_ForeignKeys(t *testing.T) {
+	// Given.
+	s := newSQLiteStore(t)
+
+	// When.
+	_, err := s.db.Exec("INSERT INTO Transactions(Account_ID, OperationType_ID, Amount, Balance, EventDate) VALUES( ?, ?, ?, ?, ? )", invalidAccountId, 1, -100, -100, time.Now())
+
+	// Then.
+	require.ErrorContains(t, err, "FOREIGN KEY")
+}
+
+func TestSQLite_ConcurrentPayments(t *testing.T) {
+	// Given.
+	s := newSQLiteStore(t)
+	ctx := context.Background()
+	account, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+	require.NoError(t, err)
+	accountId := *account.AccountID
+	for range 20 {
+		_, err := s.CreateTransaction(ctx, *model.NewTransaction(nil, accountId, 1, model.MustParseMoney("-10.00"), model.MustParseMoney("-10.00"), nil))
+		require.NoError(t, err)
+	}
+
+	// When.
+	payments := make([]*model.TransactionImpl, 25)
+	var wg sync.WaitGroup
+	for i := range payments {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			payment, err := s.SettlePayment(ctx, *model.NewTransaction(nil, accountId, 4, model.MustParseMoney("10.00"), 0, nil))
+			assert.NoError(t, err)
+			payments[i] = payment
+		}()
+	}
+	wg.Wait()
+
+	// Then.
+	// Every debt is settled exactly once, so five payments are left over.
+	debts, err := s.GetNegativeTransactions(ctx, accountId, 1)
+	require.NoError(t, err)
+	assert.Empty(t, debts)
+	var leftover model.Money
+	for _, payment := range payments {
+		require.NotNil(t, payment)
+		leftover += payment.Balance
+	}
+	assert.Equal(t, model.MustParseMoney("50.00"), leftover)
+}
+
+func TestSQLite_MigrateDownAndUp(t *testing.T) {
+	// Given.
+	s := newSQLiteStore(t)
+	m, err := s.Migrator()
+	require.NoError(t, err)
+
+	// When.
+	for {
+		reverted, err := m.Down(context.Background())
+		require.NoError(t, err)
+		if reverted == nil {
+			break
+		}
+	}
+	_, err = m.Up(context.Background())
+
+	// Then.
+	require.NoError(t, err)
+	account, err := s.CreateAccount(context.Background(), "20251027", model.DefaultCurrency)
+	require.NoError(t, err)
+	_, err = s.UpdateCreditLimit(context.Background(), model.CreditLimitChange{AccountID: *account.AccountID, NewLimit: 100, Reason: "opening"})
+	require.NoError(t, err)
+}
@@ -1,34 +1,160 @@
 package store
 
 import (
+	"account-transactions/config"
 	"account-transactions/model"
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
+	"strconv"
+	"time"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
 )
 
+// Store persists accounts and transactions. Every method takes the context
+// of the request it serves and gives up once the context is done.
+// Methods that post transactions or hold credit fail on accounts that do
+// not accept them, as model.AccountImpl.CheckPosting describes.
 type Store interface {
 	Account
 	Operation
 	Transaction
+	Authorization
+	Installment
+	Transfer
+	FXRate
+	Idempotency
+
+	// Close releases the store's resources, e.g. its database connections.
+	Close() error
 }
 
 type Account interface {
-	GetAccount(int) (*model.AccountImpl, error)
-	CreateAccount(string) (*model.AccountImpl, error)
+	GetAccount(context.Context, int) (*model.AccountImpl, error)
+	// CreateAccount opens an account for the document number, booked in
+	// the given currency.
+	CreateAccount(context.Context, string, model.Currency) (*model.AccountImpl, error)
+	// UpdateCreditLimit sets the account's credit limit to change.NewLimit
+	// and records the change, with the old limit, in its history.
+	UpdateCreditLimit(context.Context, model.CreditLimitChange) (*model.CreditLimitChange, error)
+	// ListCreditLimitChanges returns the account's credit limit history,
+	// oldest first.
+	ListCreditLimitChanges(context.Context, int) ([]model.CreditLimitChange, error)
+	// UpdateAccountStatus moves the account to change.NewStatus, if
+	// model.AccountImpl.CheckTransition allows it, and records the change,
+	// with the old status, in its history.
+	UpdateAccountStatus(context.Context, model.AccountStatusChange) (*model.AccountStatusChange, error)
+	// ListAccountStatusChanges returns the account's status history,
+	// oldest first.
+	ListAccountStatusChanges(context.Context, int) ([]model.AccountStatusChange, error)
+	// GetBalance sums up the account's outstanding debts, unapplied
+	// payments and installments that are not due yet.
+	GetBalance(context.Context, int) (*model.AccountBalance, error)
+	// GetStatement returns the account's transactions from the first time
+	// up to the second, oldest first, with the balance before and after.
+	// Either time may be nil to leave the period open.
+	GetStatement(context.Context, int, *time.Time, *time.Time) (*model.Statement, error)
 }
 
 type Operation interface {
-	GetOperation(int) (*model.OperationImpl, error)
+	GetOperation(context.Context, int) (*model.OperationImpl, error)
 }
 
 type Transaction interface {
-	GetTransaction(int) (*model.TransactionImpl, error)
-	GetNegativeTransactions(int, int) (model.Transactions, error)
-	UpdateNegativeTransactions(model.Transactions) error
-	CreateTransaction(model.TransactionImpl) (*model.TransactionImpl, error)
+	GetTransaction(context.Context, int) (*model.TransactionImpl, error)
+	ListTransactions(context.Context, int, model.TransactionFilter) (model.Transactions, error)
+	GetNegativeTransactions(context.Context, int, int) (model.Transactions, error)
+	UpdateNegativeTransactions(context.Context, model.Transactions) error
+	SettlePayment(context.Context, model.TransactionImpl) (*model.TransactionImpl, error)
+	// CreateDebit inserts a debit, first paying it from the unapplied
+	// balances of earlier payments. The rest becomes its outstanding
+	// balance if it fits in the account's available credit; otherwise it
+	// fails with model.ErrCreditLimitExceeded.
+	CreateDebit(context.Context, model.TransactionImpl) (*model.TransactionImpl, error)
+	// CreateTransaction inserts the transaction as it is, without settling
+	// anything, in the account's currency.
+	CreateTransaction(context.Context, model.TransactionImpl) (*model.TransactionImpl, error)
+	// ReverseTransaction inserts a reversal of the given amount of the
+	// transaction, or of all that is left of it if the amount is nil, and
+	// settles it as model.SettlementPolicy.SettleReversal describes.
+	ReverseTransaction(context.Context, int, *model.Money) (*model.TransactionImpl, error)
+}
+
+// Authorization holds an account's credit for debits that are posted
+// later. Authorizations are opened and closed against the store's clock.
+type Authorization interface {
+	GetAuthorization(context.Context, int) (*model.AuthorizationImpl, error)
+	// CreateAuthorization holds the amount until ExpiresAt, which must be
+	// set. It fails with model.ErrCreditLimitExceeded if the amount, plus
+	// other pending holds, does not fit in the available credit.
+	CreateAuthorization(context.Context, model.AuthorizationImpl) (*model.AuthorizationImpl, error)
+	// CaptureAuthorization posts the given amount of a pending
+	// authorization, or all of it if the amount is nil, like CreateDebit
+	// but without checking the credit limit again, and releases the rest.
+	CaptureAuthorization(context.Context, int, *model.Money) (*model.TransactionImpl, error)
+	// VoidAuthorization releases a pending authorization's hold.
+	VoidAuthorization(context.Context, int) (*model.AuthorizationImpl, error)
+	// ExpireAuthorizations marks the pending authorizations whose expiry
+	// has passed as expired and returns how many there were.
+	ExpireAuthorizations(context.Context) (int, error)
+}
+
+// Installment splits installment purchases into monthly installments.
+type Installment interface {
+	// CreateInstallmentPlan splits the purchase into the given number of
+	// installments, as model.NewInstallmentPlan does, if the whole purchase
+	// fits in the account's available credit; otherwise it fails with
+	// model.ErrCreditLimitExceeded. Unapplied payments pay the installments
+	// first, soonest due first.
+	CreateInstallmentPlan(context.Context, model.TransactionImpl, int) (*model.InstallmentPlan, error)
+	// GetInstallmentPlan returns the plan with its installments, soonest
+	// due first.
+	GetInstallmentPlan(context.Context, int) (*model.InstallmentPlan, error)
+}
+
+// Transfer moves funds between accounts.
+type Transfer interface {
+	// CreateTransfer posts the debit and credit of a transfer built by
+	// model.NewTransfer. The debit is posted on the source account like
+	// CreateDebit, failing with model.ErrCreditLimitExceeded if it does not
+	// fit, and the credit on the destination like SettlePayment, all at
+	// once. Transfers to the same account fail with model.ErrSameAccount.
+	CreateTransfer(context.Context, model.Transfer) (*model.Transfer, error)
+	// GetTransfer returns the transfer with its debit and credit.
+	GetTransfer(context.Context, int) (*model.Transfer, error)
+}
+
+// FXRate keeps the exchange rates amounts in a foreign currency are
+// converted with.
+type FXRate interface {
+	// GetFXRate returns the rate from the first currency to the second, or
+	// fails with ErrFXRateNotFound if none was loaded.
+	GetFXRate(context.Context, model.Currency, model.Currency) (*model.FXRate, error)
+	// ListFXRates returns every rate, by base and then quote currency.
+	ListFXRates(context.Context) ([]model.FXRate, error)
+	// UpdateFXRates loads the rates, replacing those already loaded for the
+	// same currencies, all at once.
+	UpdateFXRates(context.Context, []model.FXRate) ([]model.FXRate, error)
+}
+
+// Idempotency stores the responses of requests sent with an
+// Idempotency-Key header.
+type Idempotency interface {
+	// ReserveIdempotencyKey claims the key for a new request. Records
+	// created before expiresBefore are discarded first. If the key is
+	// already taken, the existing record is returned and nothing is
+	// reserved.
+	ReserveIdempotencyKey(ctx context.Context, key string, requestHash string, createdAt time.Time, expiresBefore time.Time) (*model.IdempotencyRecord, error)
+	// CompleteIdempotencyKey stores the response for a reserved key.
+	CompleteIdempotencyKey(ctx context.Context, key string, statusCode int, contentType string, body []byte) error
+	// ReleaseIdempotencyKey drops a reserved key so the request can be retried.
+	ReleaseIdempotencyKey(ctx context.Context, key string) error
+	// PurgeIdempotencyKeys deletes every record created before
+	// expiresBefore and returns how many there were.
+	PurgeIdempotencyKeys(ctx context.Context, expiresBefore time.Time) (int, error)
 }
 
 var _ Store = &StoreImpl{}
@@ -36,43 +162,59 @@ var _ Store = &StoreImpl{}
 type StoreImpl struct {
 	db *sqlx.DB
 	Store
-}
 
-var dbport = 3306
+	// SettlementPolicy orders debts in SettlePayment.
+	SettlementPolicy model.SettlementPolicy
+	// InstallmentRounding places the remainder in CreateInstallmentPlan.
+	InstallmentRounding model.InstallmentRounding
+}
 
-func New() *StoreImpl {
-	db, err := connect()
+// New connects to MySQL with the given configuration.
+func New(cfg config.MySQLConfig) (*StoreImpl, error) {
+	db, err := connect("mysql", mysqlDSN(cfg), cfg.PoolConfig)
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("connecting to mysql on %s:%d: %w", cfg.Host, cfg.Port, err)
 	}
-	log.Println("using store: mysql")
+	slog.Info("using store: mysql", "host", cfg.Host, "port", cfg.Port)
 	return &StoreImpl{
 		db: db,
-	}
+	}, nil
 }
 
-func connect() (*sqlx.DB, error) {
-	var db *sqlx.DB
-	var err error
-	localServer := true
-	if localServer {
-		// When running the DB in a container and the server as a local binary.
-		db, err = sqlx.Open("mysql", fmt.Sprintf("storeuser:example@tcp(0.0.0.0:%d)/store", dbport))
-	} else {
-		// When both the DB and server are running in containers.
-		db, err = sqlx.Open("mysql", fmt.Sprintf("storeuser:example@tcp(db:%d)/store", dbport))
-	}
+// Close closes the database connections.
+func (s *StoreImpl) Close() error {
+	return s.db.Close()
+}
+
+// mysqlDSN builds the MySQL data source name from the configuration.
+func mysqlDSN(cfg config.MySQLConfig) string {
+	c := mysql.NewConfig()
+	c.User = cfg.User
+	c.Passwd = cfg.Password
+	c.Net = "tcp"
+	c.Addr = net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	c.DBName = cfg.Database
+	c.ParseTime = true
+	c.Timeout = cfg.DialTimeout
+	c.ReadTimeout = cfg.ReadTimeout
+	c.WriteTimeout = cfg.WriteTimeout
+	return c.FormatDSN()
+}
 
+// connect opens a connection pool and checks that the database is
+// reachable.
+func connect(driver string, dsn string, pool config.PoolConfig) (*sqlx.DB, error) {
+	db, err := sqlx.Open(driver, dsn)
 	if err != nil {
-		log.Println(err)
 		return nil, err
 	}
+	db.SetMaxOpenConns(pool.MaxOpenConns)
+	db.SetMaxIdleConns(pool.MaxIdleConns)
+	db.SetConnMaxLifetime(pool.ConnMaxLifetime)
 
-	err = db.Ping()
-	if err != nil {
-		log.Println(err)
+	if err := db.Ping(); err != nil {
+		db.Close()
 		return nil, err
 	}
-	log.Printf("mysql connected on %d\n", dbport)
-	return db, err
+	return db, nil
 }
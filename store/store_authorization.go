@@ -0,0 +1,203 @@
+package store
+
+import (
+	"account-transactions/model"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const selectAuthorization = "SELECT Authorization_ID, Account_ID, OperationType_ID, Amount, Status, Created_At, Expires_At, Closed_At, Transaction_ID FROM Authorizations WHERE Authorization_ID=?"
+
+func (s *StoreImpl) GetAuthorization(ctx context.Context, authorizationId int) (*model.AuthorizationImpl, error) {
+	return getAuthorization(ctx, s.db, authorizationId, selectAuthorization)
+}
+
+// CreateAuthorization locks the account, its payments and its debts like
+// CreateDebit, so holds and debits are checked against the limit one at a
+// time.
+func (s *StoreImpl) CreateAuthorization(ctx context.Context, authorization model.AuthorizationImpl) (*model.AuthorizationImpl, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // No-op once committed.
+
+	account, err := lockAccount(ctx, tx, authorization.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := account.CheckPosting(authorization.Amount); err != nil {
+		return nil, err
+	}
+	credits, err := getCredits(ctx, tx, authorization.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	// DATETIME has second precision, so truncate to return what is stored.
+	now := time.Now().UTC().Truncate(time.Second)
+	debts, err := getOwed(ctx, tx, authorization.AccountID, now)
+	if err != nil {
+		return nil, err
+	}
+	held, err := getHeld(ctx, tx, authorization.AccountID, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := account.CheckDebit(debts, credits, held, authorization.Amount); err != nil {
+		return nil, err
+	}
+
+	expiresAt := authorization.ExpiresAt.UTC().Truncate(time.Second)
+	authorizationId, err := insert(ctx, tx, "INSERT INTO Authorizations(Account_ID, OperationType_ID, Amount, Status, Created_At, Expires_At) VALUES( ?, ?, ?, ?, ?, ? )", "Authorization_ID",
+		authorization.AccountID, authorization.OperationTypeID, authorization.Amount, model.AuthorizationPending, now, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	authorization.AuthorizationID = &authorizationId
+	authorization.Status = model.AuthorizationPending
+	authorization.CreatedAt = &now
+	authorization.ExpiresAt = &expiresAt
+	return &authorization, nil
+}
+
+// CaptureAuthorization locks the account and its payments like CreateDebit,
+// and only then the authorization, so it cannot deadlock with them.
+func (s *StoreImpl) CaptureAuthorization(ctx context.Context, authorizationId int, amount *model.Money) (*model.TransactionImpl, error) {
+	authorization, err := s.GetAuthorization(ctx, authorizationId)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // No-op once committed.
+
+	account, err := lockAccount(ctx, tx, authorization.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	credits, err := getCredits(ctx, tx, authorization.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	// Read the authorization again now that it cannot be closed meanwhile.
+	authorization, err = getAuthorization(ctx, tx, authorizationId, forUpdate(tx, selectAuthorization, "FOR UPDATE"))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	if err := authorization.CheckPending(now); err != nil {
+		return nil, err
+	}
+
+	debit, err := authorization.NewCapture(amount)
+	if err != nil {
+		return nil, err
+	}
+	if err := account.CheckPosting(debit.Amount); err != nil {
+		return nil, err
+	}
+	if err := account.Book(debit); err != nil {
+		return nil, err
+	}
+	// The hold already reserved the credit, so the limit is not checked.
+	credits, allocations, rest, err := model.ProcessPositivePayments(credits, debit.Amount, debit.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if err := updateNegativeTransactions(ctx, tx, credits); err != nil {
+		return nil, err
+	}
+	debit.Balance = rest
+	result, err := createTransaction(ctx, tx, *debit)
+	if err != nil {
+		return nil, err
+	}
+	if err := insertAllocations(ctx, tx, allocations.For(*result.TransactionID)); err != nil {
+		return nil, err
+	}
+	if err := closeAuthorization(ctx, tx, authorizationId, model.AuthorizationCaptured, now, result.TransactionID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *StoreImpl) VoidAuthorization(ctx context.Context, authorizationId int) (*model.AuthorizationImpl, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // No-op once committed.
+
+	authorization, err := getAuthorization(ctx, tx, authorizationId, forUpdate(tx, selectAuthorization, "FOR UPDATE"))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	if err := authorization.CheckPending(now); err != nil {
+		return nil, err
+	}
+	if err := closeAuthorization(ctx, tx, authorizationId, model.AuthorizationVoided, now, nil); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	authorization.Status = model.AuthorizationVoided
+	authorization.ClosedAt = &now
+	return authorization, nil
+}
+
+func (s *StoreImpl) ExpireAuthorizations(ctx context.Context) (int, error) {
+	now := time.Now().UTC().Truncate(time.Second)
+	res, err := s.db.ExecContext(ctx, s.db.Rebind("UPDATE Authorizations SET Status=?, Closed_At=? WHERE Status=? AND Expires_At <= ?"),
+		model.AuthorizationExpired, now, model.AuthorizationPending, now)
+	if err != nil {
+		return 0, err
+	}
+	expired, err := res.RowsAffected()
+	return int(expired), err
+}
+
+func getAuthorization(ctx context.Context, q dbtx, authorizationId int, query string) (*model.AuthorizationImpl, error) {
+	var authorization model.AuthorizationImpl
+	err := sqlx.GetContext(ctx, q, &authorization, q.Rebind(query), authorizationId)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, fmt.Errorf("%w: no authorization with id %d", ErrAuthorizationNotFound, authorizationId)
+	case err != nil:
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	return &authorization, nil
+}
+
+// getHeld returns what the account's pending authorizations that have not
+// expired by now hold, as a positive amount. Lock the account first so no
+// authorization is opened meanwhile.
+func getHeld(ctx context.Context, q dbtx, accountId int, now time.Time) (model.Money, error) {
+	held, err := sumMoney(ctx, q, "SELECT SUM(Amount) FROM Authorizations WHERE Account_ID=? AND Status=? AND Expires_At > ?", accountId, model.AuthorizationPending, now)
+	if err != nil {
+		return 0, fmt.Errorf("query error: %w", err)
+	}
+	return -held, nil
+}
+
+func closeAuthorization(ctx context.Context, q dbtx, authorizationId int, status model.AuthorizationStatus, closedAt time.Time, transactionId *int) error {
+	_, err := q.ExecContext(ctx, q.Rebind("UPDATE Authorizations SET Status=?, Closed_At=?, Transaction_ID=? WHERE Authorization_ID=?"), status, closedAt, transactionId, authorizationId)
+	return err
+}
@@ -0,0 +1,49 @@
+package store
+
+import (
+	"account-transactions/model"
+	"context"
+	"fmt"
+	"time"
+)
+
+func (s *StoreImpl) UpdateCreditLimit(ctx context.Context, change model.CreditLimitChange) (*model.CreditLimitChange, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // No-op once committed.
+
+	account, err := lockAccount(ctx, tx, change.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, tx.Rebind("UPDATE Accounts SET Credit_Limit=? WHERE Account_ID=?"), change.NewLimit, change.AccountID); err != nil {
+		return nil, err
+	}
+
+	// DATETIME has second precision, so truncate to return what is stored.
+	changedAt := time.Now().UTC().Truncate(time.Second)
+	changeId, err := insert(ctx, tx, "INSERT INTO CreditLimitChanges(Account_ID, Old_Limit, New_Limit, Reason, Changed_At) VALUES( ?, ?, ?, ?, ? )", "Change_ID",
+		change.AccountID, account.CreditLimit, change.NewLimit, change.Reason, changedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	change.ChangeID = &changeId
+	change.OldLimit = account.CreditLimit
+	change.ChangedAt = &changedAt
+	return &change, nil
+}
+
+func (s *StoreImpl) ListCreditLimitChanges(ctx context.Context, accountId int) ([]model.CreditLimitChange, error) {
+	changes := []model.CreditLimitChange{}
+	err := s.db.SelectContext(ctx, &changes, s.db.Rebind("SELECT Change_ID, Account_ID, Old_Limit, New_Limit, Reason, Changed_At FROM CreditLimitChanges WHERE Account_ID=? ORDER BY Change_ID"), accountId)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	return changes, nil
+}
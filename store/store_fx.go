@@ -0,0 +1,65 @@
+package store
+
+import (
+	"account-transactions/model"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func (s *StoreImpl) GetFXRate(ctx context.Context, base model.Currency, quote model.Currency) (*model.FXRate, error) {
+	var rate model.FXRate
+	err := s.db.GetContext(ctx, &rate, s.db.Rebind("SELECT Base_Currency, Quote_Currency, Rate, Updated_At FROM FXRates WHERE Base_Currency=? AND Quote_Currency=?"), base, quote)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, fmt.Errorf("%w: no rate from %s to %s", ErrFXRateNotFound, base, quote)
+	case err != nil:
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	return &rate, nil
+}
+
+func (s *StoreImpl) ListFXRates(ctx context.Context) ([]model.FXRate, error) {
+	rates := []model.FXRate{}
+	if err := s.db.SelectContext(ctx, &rates, "SELECT Base_Currency, Quote_Currency, Rate, Updated_At FROM FXRates ORDER BY Base_Currency, Quote_Currency"); err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	return rates, nil
+}
+
+// UpdateFXRates deletes the rows of the rates it loads before inserting
+// them again, which every database supports alike, in one DB transaction.
+func (s *StoreImpl) UpdateFXRates(ctx context.Context, rates []model.FXRate) ([]model.FXRate, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // No-op once committed.
+
+	// DATETIME has second precision, so truncate to return what is stored.
+	now := time.Now().UTC().Truncate(time.Second)
+	updated := make([]model.FXRate, len(rates))
+	for i, rate := range rates {
+		if err := updateFXRate(ctx, tx, rate, now); err != nil {
+			return nil, err
+		}
+		rate.UpdatedAt = &now
+		updated[i] = rate
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func updateFXRate(ctx context.Context, tx *sqlx.Tx, rate model.FXRate, now time.Time) error {
+	if _, err := tx.ExecContext(ctx, tx.Rebind("DELETE FROM FXRates WHERE Base_Currency=? AND Quote_Currency=?"), rate.Base, rate.Quote); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx, tx.Rebind("INSERT INTO FXRates(Base_Currency, Quote_Currency, Rate, Updated_At) VALUES( ?, ?, ?, ? )"), rate.Base, rate.Quote, rate.Rate, now)
+	return err
+}
@@ -0,0 +1,64 @@
+package store
+
+import (
+	"account-transactions/model"
+	"context"
+	"time"
+)
+
+func (s *StoreImpl) ReserveIdempotencyKey(ctx context.Context, key string, requestHash string, createdAt time.Time, expiresBefore time.Time) (*model.IdempotencyRecord, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // No-op once committed.
+
+	if _, err := tx.ExecContext(ctx, tx.Rebind("DELETE FROM IdempotencyKeys WHERE Idempotency_Key=? AND Created_At < ?"), key, expiresBefore); err != nil {
+		return nil, err
+	}
+	insertIgnore := "INSERT IGNORE INTO IdempotencyKeys(Idempotency_Key, Request_Hash, Created_At) VALUES( ?, ?, ? )"
+	if isPostgres(tx) || isSQLite(tx) {
+		insertIgnore = "INSERT INTO IdempotencyKeys(Idempotency_Key, Request_Hash, Created_At) VALUES( ?, ?, ? ) ON CONFLICT DO NOTHING"
+	}
+	res, err := tx.ExecContext(ctx, tx.Rebind(insertIgnore), key, requestHash, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	inserted, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	var existing *model.IdempotencyRecord
+	if inserted == 0 {
+		existing = &model.IdempotencyRecord{}
+		err = tx.GetContext(ctx, existing, tx.Rebind("SELECT Idempotency_Key, Request_Hash, Status_Code, Content_Type, Response_Body, Created_At FROM IdempotencyKeys WHERE Idempotency_Key=?"), key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+func (s *StoreImpl) CompleteIdempotencyKey(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	_, err := s.db.ExecContext(ctx, s.db.Rebind("UPDATE IdempotencyKeys SET Status_Code=?, Content_Type=?, Response_Body=? WHERE Idempotency_Key=?"), statusCode, contentType, body, key)
+	return err
+}
+
+func (s *StoreImpl) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	_, err := s.db.ExecContext(ctx, s.db.Rebind("DELETE FROM IdempotencyKeys WHERE Idempotency_Key=?"), key)
+	return err
+}
+
+func (s *StoreImpl) PurgeIdempotencyKeys(ctx context.Context, expiresBefore time.Time) (int, error) {
+	res, err := s.db.ExecContext(ctx, s.db.Rebind("DELETE FROM IdempotencyKeys WHERE Created_At < ?"), expiresBefore)
+	if err != nil {
+		return 0, err
+	}
+	purged, err := res.RowsAffected()
+	return int(purged), err
+}
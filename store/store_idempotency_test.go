@@ -0,0 +1,77 @@
+package store
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReserveIdempotencyKey_New(t *testing.T) {
+	// Given.
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	store := &StoreImpl{db: sqlxDB}
+
+	now := time.Date(2025, 10, 27, 0, 0, 0, 0, time.UTC)
+	expiresBefore := now.Add(-time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM IdempotencyKeys WHERE Idempotency_Key=? AND Created_At < ?`)).
+		WithArgs("key-1", expiresBefore).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT IGNORE INTO IdempotencyKeys(Idempotency_Key, Request_Hash, Created_At) VALUES( ?, ?, ? )`)).
+		WithArgs("key-1", "hash", now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// When.
+	existing, err := store.ReserveIdempotencyKey(context.Background(), "key-1", "hash", now, expiresBefore)
+
+	// Then.
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReserveIdempotencyKey_Existing(t *testing.T) {
+	// Given.
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	store := &StoreImpl{db: sqlxDB}
+
+	now := time.Date(2025, 10, 27, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"Idempotency_Key", "Request_Hash", "Status_Code", "Content_Type", "Response_Body", "Created_At"}).
+		AddRow("key-1", "hash", 201, "application/json", []byte(`{}`), now)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM IdempotencyKeys`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT IGNORE INTO IdempotencyKeys`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT Idempotency_Key, Request_Hash, Status_Code, Content_Type, Response_Body, Created_At FROM IdempotencyKeys WHERE Idempotency_Key=?`)).
+		WithArgs("key-1").
+		WillReturnRows(rows)
+	mock.ExpectCommit()
+
+	// When.
+	existing, err := store.ReserveIdempotencyKey(context.Background(), "key-1", "hash", now, now.Add(-time.Hour))
+
+	// Then.
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	assert.Equal(t, 201, existing.StatusCode)
+	assert.Equal(t, []byte(`{}`), existing.Body)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
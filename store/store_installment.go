@@ -0,0 +1,102 @@
+package store
+
+import (
+	"account-transactions/model"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// CreateInstallmentPlan locks the account, its payments and all it owes
+// like CreateDebit, so the whole purchase is checked against the limit at
+// once. Unapplied payments then pay the installments, soonest due first.
+func (s *StoreImpl) CreateInstallmentPlan(ctx context.Context, purchase model.TransactionImpl, count int) (*model.InstallmentPlan, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // No-op once committed.
+
+	account, err := lockAccount(ctx, tx, purchase.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := account.CheckPosting(purchase.Amount); err != nil {
+		return nil, err
+	}
+	if err := account.Book(&purchase); err != nil {
+		return nil, err
+	}
+	// DATETIME has second precision, so truncate to return what is stored.
+	now := time.Now().UTC().Truncate(time.Second)
+	plan, err := model.NewInstallmentPlan(purchase, count, s.InstallmentRounding, now)
+	if err != nil {
+		return nil, err
+	}
+	credits, err := getCredits(ctx, tx, purchase.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	debts, err := getOwed(ctx, tx, purchase.AccountID, now)
+	if err != nil {
+		return nil, err
+	}
+	held, err := getHeld(ctx, tx, purchase.AccountID, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := account.CheckDebit(debts, credits, held, plan.Amount); err != nil {
+		return nil, err
+	}
+
+	planId, err := insert(ctx, tx, "INSERT INTO InstallmentPlans(Account_ID, OperationType_ID, Amount, Installment_Count, Created_At, Currency, Original_Amount, Original_Currency, FX_Rate) VALUES( ?, ?, ?, ?, ?, ?, ?, ?, ? )", "Plan_ID",
+		plan.AccountID, plan.OperationTypeID, plan.Amount, plan.InstallmentCount, now, plan.Currency, plan.OriginalAmount, plan.OriginalCurrency, plan.FXRate)
+	if err != nil {
+		return nil, err
+	}
+	plan.PlanID = &planId
+
+	for i, debit := range plan.Debits {
+		var allocations model.Allocations
+		credits, allocations, debit.Balance, err = model.ProcessPositivePayments(credits, debit.Amount, debit.Currency)
+		if err != nil {
+			return nil, err
+		}
+		debit.PlanID = &planId
+		result, err := createTransaction(ctx, tx, debit)
+		if err != nil {
+			return nil, err
+		}
+		if err := insertAllocations(ctx, tx, allocations.For(*result.TransactionID)); err != nil {
+			return nil, err
+		}
+		plan.Debits[i] = *result
+	}
+	if err := updateNegativeTransactions(ctx, tx, credits); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (s *StoreImpl) GetInstallmentPlan(ctx context.Context, planId int) (*model.InstallmentPlan, error) {
+	var plan model.InstallmentPlan
+	err := s.db.GetContext(ctx, &plan, s.db.Rebind("SELECT Plan_ID, Account_ID, OperationType_ID, Amount, Installment_Count, Created_At, Currency, Original_Amount, Original_Currency, FX_Rate FROM InstallmentPlans WHERE Plan_ID=?"), planId)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, fmt.Errorf("%w: no installment plan with id %d", ErrInstallmentPlanNotFound, planId)
+	case err != nil:
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+
+	if err := sqlx.SelectContext(ctx, s.db, &plan.Debits, s.db.Rebind("SELECT Transaction_ID, Account_ID, OperationType_ID, Amount, Balance, EventDate, Original_Transaction_ID, Plan_ID, Due_Date, Transfer_ID, Currency, Original_Amount, Original_Currency, FX_Rate FROM Transactions WHERE Plan_ID=? ORDER BY Due_Date, Transaction_ID"), planId); err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	return &plan, nil
+}
@@ -2,128 +2,416 @@ package store
 
 import (
 	"account-transactions/model"
+	"context"
 	"database/sql"
 	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jmoiron/sqlx"
 )
 
-func (s *StoreImpl) GetAccount(accountId int) (*model.AccountImpl, error) {
+func (s *StoreImpl) GetAccount(ctx context.Context, accountId int) (*model.AccountImpl, error) {
 
 	var account model.AccountImpl
-	err := s.db.Get(&account, "SELECT Account_ID, Document_Number FROM Accounts WHERE Account_ID=?", accountId)
+	err := s.db.GetContext(ctx, &account, s.db.Rebind("SELECT Account_ID, Document_Number, Credit_Limit, Currency, Status FROM Accounts WHERE Account_ID=?"), accountId)
 	switch {
 	case err == sql.ErrNoRows:
-		err = fmt.Errorf("no account with id %d, err: %v", accountId, err)
+		err = fmt.Errorf("%w: no account with id %d", ErrAccountNotFound, accountId)
 	case err != nil:
-		err = fmt.Errorf("query error: %v", err)
+		err = fmt.Errorf("query error: %w", err)
 	}
 	return &account, err
 }
 
-func (s *StoreImpl) GetOperation(operationId int) (*model.OperationImpl, error) {
+func (s *StoreImpl) GetOperation(ctx context.Context, operationId int) (*model.OperationImpl, error) {
 
 	var account model.OperationImpl
-	err := s.db.Get(&account, "SELECT OperationType_ID, Description FROM OperationsTypes WHERE OperationType_ID=?", operationId)
+	err := s.db.GetContext(ctx, &account, s.db.Rebind("SELECT OperationType_ID, Description, Direction FROM OperationsTypes WHERE OperationType_ID=?"), operationId)
 	switch {
 	case err == sql.ErrNoRows:
-		err = fmt.Errorf("no operation with id %d, err: %v", operationId, err)
+		err = fmt.Errorf("%w: no operation with id %d", ErrOperationNotFound, operationId)
 	case err != nil:
-		err = fmt.Errorf("query error: %v", err)
+		err = fmt.Errorf("query error: %w", err)
 	}
 	return &account, err
 }
 
-func (s *StoreImpl) GetTransaction(transactionId int) (*model.TransactionImpl, error) {
+func (s *StoreImpl) GetTransaction(ctx context.Context, transactionId int) (*model.TransactionImpl, error) {
+	return getTransaction(ctx, s.db, transactionId, "SELECT Transaction_ID, Account_ID, OperationType_ID, Amount, Balance, EventDate, Original_Transaction_ID, Plan_ID, Due_Date, Transfer_ID, Currency, Original_Amount, Original_Currency, FX_Rate FROM Transactions WHERE Transaction_ID=?")
+}
 
+func getTransaction(ctx context.Context, q dbtx, transactionId int, query string) (*model.TransactionImpl, error) {
 	var transaction model.TransactionImpl
-	err := s.db.Get(&transaction, "SELECT Transaction_ID, Account_ID, OperationType_ID, Amount FROM Transactions WHERE Transaction_ID=?", transactionId)
+	err := sqlx.GetContext(ctx, q, &transaction, q.Rebind(query), transactionId)
 	switch {
 	case err == sql.ErrNoRows:
-		err = fmt.Errorf("no transaction with id %d, err: %v", transactionId, err)
+		err = fmt.Errorf("%w: no transaction with id %d", ErrTransactionNotFound, transactionId)
 	case err != nil:
-		err = fmt.Errorf("query error: %v", err)
+		err = fmt.Errorf("query error: %w", err)
 	}
 	return &transaction, err
 }
 
-func (s *StoreImpl) GetNegativeTransactions(accountId int, operationType int) (model.Transactions, error) {
+// ListTransactions returns up to filter.Limit of the account's transactions
+// matching the filter, ordered by EventDate and then Transaction_ID.
+func (s *StoreImpl) ListTransactions(ctx context.Context, accountId int, filter model.TransactionFilter) (model.Transactions, error) {
+	query := "SELECT Transaction_ID, Account_ID, OperationType_ID, Amount, Balance, EventDate, Original_Transaction_ID, Plan_ID, Due_Date, Transfer_ID, Currency, Original_Amount, Original_Currency, FX_Rate FROM Transactions WHERE Account_ID=?"
+	args := []any{accountId}
+
+	if filter.OperationTypeID != nil {
+		query += " AND OperationType_ID=?"
+		args = append(args, *filter.OperationTypeID)
+	}
+	// EventDate is stored in UTC without a zone, so compare in UTC.
+	if filter.From != nil {
+		query += " AND EventDate >= ?"
+		args = append(args, filter.From.UTC())
+	}
+	if filter.To != nil {
+		query += " AND EventDate < ?"
+		args = append(args, filter.To.UTC())
+	}
+
+	order, cmp := "ASC", ">"
+	if filter.Descending {
+		order, cmp = "DESC", "<"
+	}
+	if filter.After != nil {
+		query += fmt.Sprintf(" AND (EventDate %s ? OR (EventDate = ? AND Transaction_ID %s ?))", cmp, cmp)
+		args = append(args, filter.After.EventDate.UTC(), filter.After.EventDate.UTC(), filter.After.TransactionID)
+	}
+	query += fmt.Sprintf(" ORDER BY EventDate %s, Transaction_ID %s LIMIT ?", order, order)
+	args = append(args, filter.Limit)
 
 	var transactions model.Transactions
+	if err := s.db.SelectContext(ctx, &transactions, s.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	return transactions, nil
+}
 
-	rows, err := s.db.Query("SELECT Transaction_ID, Account_ID, OperationType_ID, Amount, Balance FROM Transactions WHERE Account_ID=? AND OperationType_ID=? AND Balance < 0 ORDER BY EventDate", accountId, operationType)
+func (s *StoreImpl) GetNegativeTransactions(ctx context.Context, accountId int, operationType int) (model.Transactions, error) {
+	return getNegativeTransactions(ctx, s.db, accountId, operationType, time.Now().UTC().Truncate(time.Second))
+}
+
+func (s *StoreImpl) UpdateNegativeTransactions(ctx context.Context, transactions model.Transactions) error {
+	return updateNegativeTransactions(ctx, s.db, transactions)
+}
+
+// SettlePayment pays down the account's outstanding debts in the order of
+// s.SettlementPolicy, then pays installments that are not due yet early,
+// soonest due first, and inserts the payment with any leftover amount as
+// its balance. The account and its debt rows are locked and everything
+// runs in one DB transaction, so concurrent payments cannot allocate
+// against the same balance, nor the account be closed meanwhile.
+func (s *StoreImpl) SettlePayment(ctx context.Context, payment model.TransactionImpl) (*model.TransactionImpl, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return transactions, err
+		return nil, err
 	}
-	defer rows.Close()
+	defer tx.Rollback() // No-op once committed.
 
-	for rows.Next() {
-		var transaction model.TransactionImpl
-		if err := rows.Scan(&transaction.TransactionID, &transaction.AccountID, &transaction.OperationTypeID, &transaction.Amount, &transaction.Balance); err != nil {
-			return transactions, err
-		}
+	account, err := lockAccount(ctx, tx, payment.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := account.CheckPosting(payment.Amount); err != nil {
+		return nil, err
+	}
+	if err := account.Book(&payment); err != nil {
+		return nil, err
+	}
 
-		transactions = append(transactions, transaction)
+	now := time.Now().UTC().Truncate(time.Second)
+	debts, err := getDebts(ctx, tx, payment.AccountID, now)
+	if err != nil {
+		return nil, err
+	}
+	scheduled, err := getScheduled(ctx, tx, payment.AccountID, now)
+	if err != nil {
+		return nil, err
+	}
+	transactions, allocations, amount, err := model.ProcessNegativePayments(slices.Concat(s.SettlementPolicy.Order(debts), scheduled), payment.Amount, payment.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if err := updateNegativeTransactions(ctx, tx, transactions); err != nil {
+		return nil, err
+	}
+
+	payment.Balance = amount
+	result, err := createTransaction(ctx, tx, payment)
+	if err != nil {
+		return nil, err
 	}
-	return transactions, err
+	if err := insertAllocations(ctx, tx, allocations.For(*result.TransactionID)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
-func (s *StoreImpl) UpdateNegativeTransactions(transactions model.Transactions) error {
-	for _, transaction := range transactions {
-		stmt, err := s.db.Prepare("UPDATE Transactions SET Balance=? WHERE Transaction_ID=?")
-		if err != nil {
-			return err
-		}
-		defer stmt.Close() // Prepared statements take up server resources and should be closed after use.
+// CreateDebit first draws the debit down from the unapplied balances of
+// earlier payments, oldest first, and inserts it with the rest as its
+// balance. Only that rest counts against the credit limit, along with
+// installments that are not due yet and what pending authorizations hold.
+// The account, its payments and its debts are locked meanwhile, so
+// concurrent debits cannot both take the same credit.
+func (s *StoreImpl) CreateDebit(ctx context.Context, debit model.TransactionImpl) (*model.TransactionImpl, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // No-op once committed.
 
-		_, err = stmt.Exec(transaction.Balance, transaction.TransactionID)
-		if err != nil {
-			return err
-		}
+	account, err := lockAccount(ctx, tx, debit.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := account.CheckPosting(debit.Amount); err != nil {
+		return nil, err
+	}
+	if err := account.Book(&debit); err != nil {
+		return nil, err
+	}
+	credits, err := getCredits(ctx, tx, debit.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	debts, err := getOwed(ctx, tx, debit.AccountID, now)
+	if err != nil {
+		return nil, err
+	}
+	held, err := getHeld(ctx, tx, debit.AccountID, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := account.CheckDebit(debts, credits, held, debit.Amount); err != nil {
+		return nil, err
+	}
+	credits, allocations, amount, err := model.ProcessPositivePayments(credits, debit.Amount, debit.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if err := updateNegativeTransactions(ctx, tx, credits); err != nil {
+		return nil, err
 	}
 
-	return nil
+	debit.Balance = amount
+	result, err := createTransaction(ctx, tx, debit)
+	if err != nil {
+		return nil, err
+	}
+	if err := insertAllocations(ctx, tx, allocations.For(*result.TransactionID)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
-func (s *StoreImpl) CreateAccount(docNumber string) (*model.AccountImpl, error) {
+func (s *StoreImpl) CreateAccount(ctx context.Context, docNumber string, currency model.Currency) (*model.AccountImpl, error) {
 
-	stmt, err := s.db.Prepare("INSERT INTO Accounts(Document_Number) VALUES( ? )")
+	accountId, err := insert(ctx, s.db, "INSERT INTO Accounts(Document_Number, Currency) VALUES( ?, ? )", "Account_ID", docNumber, currency)
 	if err != nil {
 		return nil, err
 	}
-	defer stmt.Close() // Prepared statements take up server resources and should be closed after use.
+	account := model.NewAccount(&accountId, docNumber, currency)
+	return account, nil
+}
 
-	res, err := stmt.Exec(docNumber)
+func (s *StoreImpl) CreateTransaction(ctx context.Context, transaction model.TransactionImpl) (*model.TransactionImpl, error) {
+	account, err := s.GetAccount(ctx, transaction.AccountID)
 	if err != nil {
 		return nil, err
 	}
-	// Get the transaction id from the inserted row.
+	if err := account.CheckPosting(transaction.Amount); err != nil {
+		return nil, err
+	}
+	if err := account.Book(&transaction); err != nil {
+		return nil, err
+	}
+	return createTransaction(ctx, s.db, transaction)
+}
+
+// dbtx is satisfied by both *sqlx.DB and *sqlx.Tx. Queries are written
+// with ? placeholders and rebound for the driver.
+type dbtx interface {
+	sqlx.ExtContext
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// insert runs an INSERT and returns the ID generated for idColumn. Postgres
+// has no LastInsertId, so there the ID is read back with RETURNING.
+func insert(ctx context.Context, q dbtx, query string, idColumn string, args ...any) (int, error) {
+	if isPostgres(q) {
+		query += " RETURNING " + idColumn
+	}
+	stmt, err := q.PrepareContext(ctx, q.Rebind(query))
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close() // Prepared statements take up server resources and should be closed after use.
+
+	if isPostgres(q) {
+		var id int
+		err := stmt.QueryRowContext(ctx, args...).Scan(&id)
+		return id, err
+	}
+
+	res, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return 0, err
+	}
+	// Get the id from the inserted row.
 	lastId, err := res.LastInsertId()
+	return int(lastId), err
+}
+
+// getNegativeTransactions returns the account's outstanding debits of one
+// operation type that are due by now, oldest first.
+func getNegativeTransactions(ctx context.Context, q dbtx, accountId int, operationType int, now time.Time) (model.Transactions, error) {
+	return queryTransactions(ctx, q, "SELECT Transaction_ID, Account_ID, OperationType_ID, Amount, Balance, Currency FROM Transactions WHERE Account_ID=? AND OperationType_ID=? AND Balance < 0 AND (Due_Date IS NULL OR Due_Date <= ?) ORDER BY EventDate, Transaction_ID", accountId, operationType, now)
+}
+
+// getDebts locks and returns the account's outstanding debits of any
+// operation type that are due by now, oldest first.
+func getDebts(ctx context.Context, q dbtx, accountId int, now time.Time) (model.Transactions, error) {
+	return queryTransactions(ctx, q, forUpdate(q, "SELECT t.Transaction_ID, t.Account_ID, t.OperationType_ID, t.Amount, t.Balance, t.Currency FROM Transactions t JOIN OperationsTypes o ON o.OperationType_ID = t.OperationType_ID WHERE t.Account_ID=? AND o.Direction='DEBIT' AND t.Balance < 0 AND (t.Due_Date IS NULL OR t.Due_Date <= ?) ORDER BY t.EventDate, t.Transaction_ID", "FOR UPDATE OF t"), accountId, now)
+}
+
+// getScheduled locks and returns the account's installments that are not
+// due by now, soonest due first.
+func getScheduled(ctx context.Context, q dbtx, accountId int, now time.Time) (model.Transactions, error) {
+	return queryTransactions(ctx, q, forUpdate(q, "SELECT Transaction_ID, Account_ID, OperationType_ID, Amount, Balance, Currency FROM Transactions WHERE Account_ID=? AND Balance < 0 AND Due_Date > ? ORDER BY Due_Date, Transaction_ID", "FOR UPDATE"), accountId, now)
+}
+
+// getOwed locks and returns all the account will owe: its debts, then its
+// installments that are not due yet. Credit limit checks count both.
+func getOwed(ctx context.Context, q dbtx, accountId int, now time.Time) (model.Transactions, error) {
+	debts, err := getDebts(ctx, q, accountId, now)
 	if err != nil {
 		return nil, err
 	}
-	lastIdConverted := int(lastId)
-	account := model.NewAccount(&lastIdConverted, docNumber)
-	return account, err
+	scheduled, err := getScheduled(ctx, q, accountId, now)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Concat(debts, scheduled), nil
+}
+
+// getCredits locks and returns the account's payments with an unapplied
+// balance, oldest first.
+func getCredits(ctx context.Context, q dbtx, accountId int) (model.Transactions, error) {
+	return queryTransactions(ctx, q, forUpdate(q, "SELECT t.Transaction_ID, t.Account_ID, t.OperationType_ID, t.Amount, t.Balance, t.Currency FROM Transactions t JOIN OperationsTypes o ON o.OperationType_ID = t.OperationType_ID WHERE t.Account_ID=? AND o.Direction='CREDIT' AND t.Balance > 0 ORDER BY t.EventDate, t.Transaction_ID", "FOR UPDATE OF t"), accountId)
+}
+
+// lockAccount returns the account and locks its row until the transaction
+// ends.
+func lockAccount(ctx context.Context, q dbtx, accountId int) (*model.AccountImpl, error) {
+	var account model.AccountImpl
+	err := sqlx.GetContext(ctx, q, &account, q.Rebind(forUpdate(q, "SELECT Account_ID, Document_Number, Credit_Limit, Currency, Status FROM Accounts WHERE Account_ID=?", "FOR UPDATE")), accountId)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, fmt.Errorf("%w: no account with id %d", ErrAccountNotFound, accountId)
+	case err != nil:
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	return &account, nil
+}
+
+// forUpdate appends a row locking clause, e.g. FOR UPDATE, to the query.
+// SQLite has no row locks; its transactions take the write lock when they
+// begin instead.
+func forUpdate(q dbtx, query string, clause string) string {
+	if isSQLite(q) {
+		return query
+	}
+	return query + " " + clause
 }
 
-func (s *StoreImpl) CreateTransaction(transaction model.TransactionImpl) (*model.TransactionImpl, error) {
-	stmt, err := s.db.Prepare("INSERT INTO Transactions(Account_ID, OperationType_ID, Amount, Balance, EventDate) VALUES( ?, ?, ?, ?, Now() )")
+func queryTransactions(ctx context.Context, q dbtx, query string, args ...any) (model.Transactions, error) {
+
+	var transactions model.Transactions
+
+	rows, err := q.QueryContext(ctx, q.Rebind(query), args...)
 	if err != nil {
-		return nil, err
+		return transactions, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var transaction model.TransactionImpl
+		if err := rows.Scan(&transaction.TransactionID, &transaction.AccountID, &transaction.OperationTypeID, &transaction.Amount, &transaction.Balance, &transaction.Currency); err != nil {
+			return transactions, err
+		}
+
+		transactions = append(transactions, transaction)
+	}
+	return transactions, rows.Err()
+}
+
+func updateNegativeTransactions(ctx context.Context, q dbtx, transactions model.Transactions) error {
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	stmt, err := q.PrepareContext(ctx, q.Rebind("UPDATE Transactions SET Balance=? WHERE Transaction_ID=?"))
+	if err != nil {
+		return err
 	}
 	defer stmt.Close() // Prepared statements take up server resources and should be closed after use.
 
-	res, err := stmt.Exec(transaction.AccountID, transaction.OperationTypeID, transaction.Amount, transaction.Balance)
+	for _, transaction := range transactions {
+		_, err = stmt.ExecContext(ctx, transaction.Balance, transaction.TransactionID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// insertAllocations records what credits settled of debits.
+func insertAllocations(ctx context.Context, q dbtx, allocations model.Allocations) error {
+	if len(allocations) == 0 {
+		return nil
+	}
+
+	stmt, err := q.PrepareContext(ctx, q.Rebind("INSERT INTO Allocations(Credit_Transaction_ID, Debit_Transaction_ID, Amount) VALUES( ?, ?, ? )"))
 	if err != nil {
-		return nil, err
+		return err
 	}
-	// Get the transaction id from the inserted row.
-	lastId, err := res.LastInsertId()
+	defer stmt.Close() // Prepared statements take up server resources and should be closed after use.
+
+	for _, allocation := range allocations {
+		_, err = stmt.ExecContext(ctx, allocation.CreditTransactionID, allocation.DebitTransactionID, allocation.Amount)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func createTransaction(ctx context.Context, q dbtx, transaction model.TransactionImpl) (*model.TransactionImpl, error) {
+	// DATETIME has second precision, so truncate to return what is stored.
+	eventDate := time.Now().UTC().Truncate(time.Second)
+	transactionId, err := insert(ctx, q, "INSERT INTO Transactions(Account_ID, OperationType_ID, Amount, Balance, EventDate, Original_Transaction_ID, Plan_ID, Due_Date, Transfer_ID, Currency, Original_Amount, Original_Currency, FX_Rate) VALUES( ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ? )", "Transaction_ID",
+		transaction.AccountID, transaction.OperationTypeID, transaction.Amount, transaction.Balance, eventDate, transaction.OriginalTransactionID, transaction.PlanID, transaction.DueDate, transaction.TransferID, transaction.Currency, transaction.OriginalAmount, transaction.OriginalCurrency, transaction.FXRate)
 	if err != nil {
 		return nil, err
 	}
 
-	transactionId := int(lastId)
 	transaction.TransactionID = &transactionId
+	transaction.EventDate = &eventDate
 
-	return &transaction, err
+	return &transaction, nil
 }
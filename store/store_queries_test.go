@@ -2,10 +2,12 @@ package store
 
 import (
 	"account-transactions/model"
+	"context"
 	"database/sql"
 	"fmt"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
@@ -30,25 +32,55 @@ func TestGetAccount_Success(t *testing.T) {
 	sqlxDB := sqlx.NewDb(db, "sqlmock")
 	store := &StoreImpl{db: sqlxDB}
 
-	rows := sqlmock.NewRows([]string{"Account_ID", "Document_Number"}).
-		AddRow(accountIdInt, documentNumber)
+	rows := sqlmock.NewRows([]string{"Account_ID", "Document_Number", "Credit_Limit", "Currency", "Status"}).
+		AddRow(accountIdInt, documentNumber, []byte("500.00"), "USD", "ACTIVE")
 
-	mock.ExpectQuery("SELECT Account_ID, Document_Number FROM Accounts WHERE Account_ID=?").
+	mock.ExpectQuery("SELECT Account_ID, Document_Number, Credit_Limit, Currency, Status FROM Accounts WHERE Account_ID=?").
 		WithArgs(accountIdInt).
 		WillReturnRows(rows)
 
 	// When.
-	account, err := store.GetAccount(accountIdInt)
+	account, err := store.GetAccount(context.Background(), accountIdInt)
 
 	// Then.
 	require.NoError(t, err)
 	expectedAccount := &model.AccountImpl{
 		AccountID:      model.IntToPtr(accountIdInt),
 		DocumentNumber: documentNumber,
+		CreditLimit:    model.MoneyToPtr(model.MustParseMoney("500.00")),
+		Currency:       model.DefaultCurrency,
+		Status:         model.AccountActive,
 	}
 	assert.Equal(t, expectedAccount, account)
 }
 
+func TestGetAccount_DeadlineAbortsQuery(t *testing.T) {
+	// Given.
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	store := &StoreImpl{db: sqlxDB}
+
+	mock.ExpectQuery("SELECT Account_ID, Document_Number, Credit_Limit, Currency, Status FROM Accounts WHERE Account_ID=?").
+		WithArgs(accountIdInt).
+		WillDelayFor(time.Minute).
+		WillReturnRows(sqlmock.NewRows([]string{"Account_ID", "Document_Number", "Credit_Limit", "Currency", "Status"}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// When.
+	start := time.Now()
+	_, err = store.GetAccount(ctx, accountIdInt)
+
+	// Then.
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), time.Minute)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetAccount_NotFound(t *testing.T) {
 	// Given.
 	db, mock, err := sqlmock.New()
@@ -58,16 +90,16 @@ func TestGetAccount_NotFound(t *testing.T) {
 	sqlxDB := sqlx.NewDb(db, "sqlmock")
 	store := &StoreImpl{db: sqlxDB}
 
-	mock.ExpectQuery("SELECT Account_ID, Document_Number FROM Accounts WHERE Account_ID=?").
+	mock.ExpectQuery("SELECT Account_ID, Document_Number, Credit_Limit, Currency, Status FROM Accounts WHERE Account_ID=?").
 		WithArgs(invalidAccountId).
 		WillReturnError(sql.ErrNoRows)
 
 	// When.
-	account, err := store.GetAccount(invalidAccountId)
+	account, err := store.GetAccount(context.Background(), invalidAccountId)
 
 	// Then.
-	require.Error(t, err)
-	assert.Equal(t, model.NewAccount(nil, ""), account)
+	require.ErrorIs(t, err, ErrAccountNotFound)
+	assert.Equal(t, &model.AccountImpl{}, account)
 	assert.Contains(t, err.Error(), fmt.Sprintf("no account with id %d", invalidAccountId))
 }
 
@@ -80,19 +112,21 @@ func TestCreateAccount_Success(t *testing.T) {
 	sqlxDB := sqlx.NewDb(db, "sqlmock")
 	store := &StoreImpl{db: sqlxDB}
 
-	mock.ExpectPrepare(regexp.QuoteMeta(`INSERT INTO Accounts(Document_Number) VALUES( ? )`)).
+	mock.ExpectPrepare(regexp.QuoteMeta(`INSERT INTO Accounts(Document_Number, Currency) VALUES( ?, ? )`)).
 		ExpectExec().
-		WithArgs("20251027").
+		WithArgs("20251027", "USD").
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	// When.
-	account, err := store.CreateAccount("20251027")
+	account, err := store.CreateAccount(context.Background(), "20251027", model.DefaultCurrency)
 
 	// Then.
 	require.NoError(t, err)
 	expectedAccount := &model.AccountImpl{
 		AccountID:      model.IntToPtr(1),
 		DocumentNumber: "20251027",
+		Currency:       model.DefaultCurrency,
+		Status:         model.AccountActive,
 	}
 	assert.Equal(t, expectedAccount, account)
 }
@@ -106,13 +140,13 @@ func TestCreateAccount_Fail(t *testing.T) {
 	sqlxDB := sqlx.NewDb(db, "sqlmock")
 	store := &StoreImpl{db: sqlxDB}
 
-	mock.ExpectPrepare(regexp.QuoteMeta(`INSERT INTO Accounts(Document_Number) VALUES( ? )`)).
+	mock.ExpectPrepare(regexp.QuoteMeta(`INSERT INTO Accounts(Document_Number, Currency) VALUES( ?, ? )`)).
 		ExpectExec().
-		WithArgs("20251027").
+		WithArgs("20251027", "USD").
 		WillReturnError(sql.ErrConnDone)
 
 	// When.
-	account, err := store.CreateAccount("20251027")
+	account, err := store.CreateAccount(context.Background(), "20251027", model.DefaultCurrency)
 
 	// Then.
 	require.Error(t, err)
@@ -129,13 +163,14 @@ func TestCreateTransaction_Success(t *testing.T) {
 	sqlxDB := sqlx.NewDb(db, "sqlmock")
 	store := &StoreImpl{db: sqlxDB}
 
-	mock.ExpectPrepare(regexp.QuoteMeta(`INSERT INTO Transactions(Account_ID, OperationType_ID, Amount, EventDate) VALUES( ?, ?, ?, Now() )`)).
+	expectGetAccount(mock)
+	mock.ExpectPrepare(regexp.QuoteMeta(`INSERT INTO Transactions(Account_ID, OperationType_ID, Amount, Balance, EventDate, Original_Transaction_ID, Plan_ID, Due_Date, Transfer_ID, Currency, Original_Amount, Original_Currency, FX_Rate) VALUES( ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ? )`)).
 		ExpectExec().
-		WithArgs(accountIdInt, 4, 5000.00).
+		WithArgs(accountIdInt, 4, "5000.00", "5000.00", sqlmock.AnyArg(), nil, nil, nil, nil, "USD", nil, nil, nil).
 		WillReturnResult(sqlmock.NewResult(int64(transactionID), 1))
 
 	// When.
-	transaction, err := store.CreateTransaction(*model.NewTransaction(nil, accountIdInt, 4, 5000.00, nil))
+	transaction, err := store.CreateTransaction(context.Background(), *model.NewTransaction(nil, accountIdInt, 4, model.MustParseMoney("5000.00"), model.MustParseMoney("5000.00"), nil))
 
 	// Then.
 	require.NoError(t, err)
@@ -143,8 +178,12 @@ func TestCreateTransaction_Success(t *testing.T) {
 		TransactionID:   &transactionID,
 		AccountID:       accountIdInt,
 		OperationTypeID: 4,
-		Amount:          5000.00,
+		Amount:          model.MustParseMoney("5000.00"),
+		Balance:         model.MustParseMoney("5000.00"),
+		EventDate:       transaction.EventDate,
+		Currency:        model.DefaultCurrency,
 	}
+	assert.NotNil(t, transaction.EventDate)
 	assert.Equal(t, expectedTransaction, transaction)
 }
 
@@ -157,14 +196,15 @@ func TestCreateTransaction_Fail(t *testing.T) {
 	sqlxDB := sqlx.NewDb(db, "sqlmock")
 	store := &StoreImpl{db: sqlxDB}
 
-	mock.ExpectPrepare(regexp.QuoteMeta(`INSERT INTO Transactions(Account_ID, OperationType_ID, Amount, EventDate) VALUES( ?, ?, ?, Now() )`)).
+	expectGetAccount(mock)
+	mock.ExpectPrepare(regexp.QuoteMeta(`INSERT INTO Transactions(Account_ID, OperationType_ID, Amount, Balance, EventDate, Original_Transaction_ID, Plan_ID, Due_Date, Transfer_ID, Currency, Original_Amount, Original_Currency, FX_Rate) VALUES( ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ? )`)).
 		ExpectExec().
-		WithArgs(accountIdInt, 4, 5000.00).
+		WithArgs(accountIdInt, 4, "5000.00", "5000.00", sqlmock.AnyArg(), nil, nil, nil, nil, "USD", nil, nil, nil).
 		WillReturnError(sql.ErrConnDone)
 
 	// When.
-	transaction, err := store.CreateTransaction(
-		*model.NewTransaction(nil, accountIdInt, 4, 5000.00, nil),
+	transaction, err := store.CreateTransaction(context.Background(),
+		*model.NewTransaction(nil, accountIdInt, 4, model.MustParseMoney("5000.00"), model.MustParseMoney("5000.00"), nil),
 	)
 
 	// Then.
@@ -172,3 +212,208 @@ func TestCreateTransaction_Fail(t *testing.T) {
 	assert.Nil(t, transaction)
 	assert.Contains(t, err.Error(), "sql: connection is already closed")
 }
+
+func TestSettlePayment_Success(t *testing.T) {
+	// Given.
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	// Settle withdrawals before purchases.
+	store := &StoreImpl{db: sqlxDB, SettlementPolicy: model.SettlementPolicy{OperationPriority: []int{3}}}
+
+	rows := sqlmock.NewRows([]string{"Transaction_ID", "Account_ID", "OperationType_ID", "Amount", "Balance", "Currency"}).
+		AddRow(1, accountIdInt, 1, "-50.00", "-50.00", "USD").
+		AddRow(2, accountIdInt, 3, "-100.00", "-100.00", "USD")
+
+	mock.ExpectBegin()
+	expectLockAccount(mock)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT t.Transaction_ID, t.Account_ID, t.OperationType_ID, t.Amount, t.Balance, t.Currency FROM Transactions t JOIN OperationsTypes o ON o.OperationType_ID = t.OperationType_ID WHERE t.Account_ID=? AND o.Direction='DEBIT' AND t.Balance < 0 AND (t.Due_Date IS NULL OR t.Due_Date <= ?) ORDER BY t.EventDate, t.Transaction_ID FOR UPDATE`)).
+		WithArgs(accountIdInt, sqlmock.AnyArg()).
+		WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT Transaction_ID, Account_ID, OperationType_ID, Amount, Balance, Currency FROM Transactions WHERE Account_ID=? AND Balance < 0 AND Due_Date > ? ORDER BY Due_Date, Transaction_ID FOR UPDATE`)).
+		WithArgs(accountIdInt, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"Transaction_ID", "Account_ID", "OperationType_ID", "Amount", "Balance", "Currency"}))
+	update := mock.ExpectPrepare(regexp.QuoteMeta(`UPDATE Transactions SET Balance=? WHERE Transaction_ID=?`))
+	update.ExpectExec().WithArgs("-40.00", 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	update.ExpectExec().WithArgs("-50.00", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare(regexp.QuoteMeta(`INSERT INTO Transactions(Account_ID, OperationType_ID, Amount, Balance, EventDate, Original_Transaction_ID, Plan_ID, Due_Date, Transfer_ID, Currency, Original_Amount, Original_Currency, FX_Rate) VALUES( ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ? )`)).
+		ExpectExec().
+		WithArgs(accountIdInt, 4, "60.00", "0.00", sqlmock.AnyArg(), nil, nil, nil, nil, "USD", nil, nil, nil).
+		WillReturnResult(sqlmock.NewResult(int64(transactionID), 1))
+	// The payment is recorded against the debts it settled.
+	allocate := mock.ExpectPrepare(regexp.QuoteMeta(`INSERT INTO Allocations(Credit_Transaction_ID, Debit_Transaction_ID, Amount) VALUES( ?, ?, ? )`))
+	allocate.ExpectExec().WithArgs(transactionID, 2, "60.00").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	// When.
+	transaction, err := store.SettlePayment(context.Background(), *model.NewTransaction(nil, accountIdInt, 4, model.MustParseMoney("60.00"), 0, nil))
+
+	// Then.
+	require.NoError(t, err)
+	expectedTransaction := model.NewTransaction(&transactionID, accountIdInt, 4, model.MustParseMoney("60.00"), 0, transaction.EventDate)
+	expectedTransaction.Currency = model.DefaultCurrency
+	assert.Equal(t, expectedTransaction, transaction)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSettlePayment_RollbackOnInsertFail(t *testing.T) {
+	// Given.
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	store := &StoreImpl{db: sqlxDB}
+
+	rows := sqlmock.NewRows([]string{"Transaction_ID", "Account_ID", "OperationType_ID", "Amount", "Balance", "Currency"}).
+		AddRow(1, accountIdInt, 1, "-50.00", "-50.00", "USD")
+
+	mock.ExpectBegin()
+	expectLockAccount(mock)
+	mock.ExpectQuery(regexp.QuoteMeta(`o.Direction='DEBIT'`)).
+		WithArgs(accountIdInt, sqlmock.AnyArg()).
+		WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta(`Due_Date > ?`)).
+		WithArgs(accountIdInt, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"Transaction_ID", "Account_ID", "OperationType_ID", "Amount", "Balance", "Currency"}))
+	mock.ExpectPrepare(regexp.QuoteMeta(`UPDATE Transactions SET Balance=? WHERE Transaction_ID=?`)).
+		ExpectExec().
+		WithArgs("0.00", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare(regexp.QuoteMeta(`INSERT INTO Transactions`)).
+		ExpectExec().
+		WillReturnError(sql.ErrConnDone)
+	mock.ExpectRollback()
+
+	// When.
+	transaction, err := store.SettlePayment(context.Background(), *model.NewTransaction(nil, accountIdInt, 4, model.MustParseMoney("60.00"), 0, nil))
+
+	// Then.
+	require.Error(t, err)
+	assert.Nil(t, transaction)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateDebit_RollbackWhenOverLimit(t *testing.T) {
+	// Given.
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	store := &StoreImpl{db: sqlxDB}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT Account_ID, Document_Number, Credit_Limit, Currency, Status FROM Accounts WHERE Account_ID=? FOR UPDATE`)).
+		WithArgs(accountIdInt).
+		WillReturnRows(sqlmock.NewRows([]string{"Account_ID", "Document_Number", "Credit_Limit", "Currency", "Status"}).
+			AddRow(accountIdInt, documentNumber, []byte("100.00"), "USD", "ACTIVE"))
+	// 5.00 of the debit is paid from an earlier overpayment.
+	mock.ExpectQuery(regexp.QuoteMeta(`o.Direction='CREDIT' AND t.Balance > 0 ORDER BY t.EventDate, t.Transaction_ID FOR UPDATE`)).
+		WithArgs(accountIdInt).
+		WillReturnRows(sqlmock.NewRows([]string{"Transaction_ID", "Account_ID", "OperationType_ID", "Amount", "Balance", "Currency"}).
+			AddRow(2, accountIdInt, 4, "5.00", "5.00", "USD"))
+	mock.ExpectQuery(regexp.QuoteMeta(`o.Direction='DEBIT' AND t.Balance < 0 AND (t.Due_Date IS NULL OR t.Due_Date <= ?) ORDER BY t.EventDate, t.Transaction_ID FOR UPDATE`)).
+		WithArgs(accountIdInt, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"Transaction_ID", "Account_ID", "OperationType_ID", "Amount", "Balance", "Currency"}).
+			AddRow(1, accountIdInt, 1, "-75.00", "-75.00", "USD"))
+	// An installment that is not due yet counts against the limit too.
+	mock.ExpectQuery(regexp.QuoteMeta(`Balance < 0 AND Due_Date > ? ORDER BY Due_Date, Transaction_ID FOR UPDATE`)).
+		WithArgs(accountIdInt, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"Transaction_ID", "Account_ID", "OperationType_ID", "Amount", "Balance", "Currency"}).
+			AddRow(3, accountIdInt, 2, "-5.00", "-5.00", "USD"))
+	// A pending authorization holds 5.00 more.
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT SUM(Amount) FROM Authorizations WHERE Account_ID=? AND Status=? AND Expires_At > ?`)).
+		WithArgs(accountIdInt, model.AuthorizationPending, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"SUM(Amount)"}).AddRow("-5.00"))
+	mock.ExpectRollback()
+
+	// When.
+	transaction, err := store.CreateDebit(context.Background(), *model.NewTransaction(nil, accountIdInt, 1, model.MustParseMoney("-20.01"), 0, nil))
+
+	// Then.
+	require.ErrorIs(t, err, model.ErrCreditLimitExceeded)
+	assert.EqualError(t, err, "credit limit exceeded: 20.01 is more than the available credit of 20.00")
+	assert.Nil(t, transaction)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListTransactions_Filters(t *testing.T) {
+	// Given.
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	store := &StoreImpl{db: sqlxDB}
+
+	from := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+	after := model.Cursor{EventDate: time.Date(2025, 10, 20, 0, 0, 0, 0, time.UTC), TransactionID: 7}
+	eventDate := time.Date(2025, 10, 10, 0, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"Transaction_ID", "Account_ID", "OperationType_ID", "Amount", "Balance", "EventDate"}).
+		AddRow(5, accountIdInt, 1, "-50.00", "-50.00", eventDate)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT Transaction_ID, Account_ID, OperationType_ID, Amount, Balance, EventDate, Original_Transaction_ID, Plan_ID, Due_Date, Transfer_ID, Currency, Original_Amount, Original_Currency, FX_Rate FROM Transactions WHERE Account_ID=? AND OperationType_ID=? AND EventDate >= ? AND EventDate < ? AND (EventDate < ? OR (EventDate = ? AND Transaction_ID < ?)) ORDER BY EventDate DESC, Transaction_ID DESC LIMIT ?`)).
+		WithArgs(accountIdInt, 1, from, to, after.EventDate, after.EventDate, 7, 10).
+		WillReturnRows(rows)
+
+	// When.
+	transactions, err := store.ListTransactions(context.Background(), accountIdInt, model.TransactionFilter{
+		OperationTypeID: model.IntToPtr(1),
+		From:            &from,
+		To:              &to,
+		Descending:      true,
+		After:           &after,
+		Limit:           10,
+	})
+
+	// Then.
+	require.NoError(t, err)
+	assert.Equal(t, model.Transactions{
+		*model.NewTransaction(model.IntToPtr(5), accountIdInt, 1, model.MustParseMoney("-50.00"), model.MustParseMoney("-50.00"), &eventDate),
+	}, transactions)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSettlePayment_Cancelled(t *testing.T) {
+	// Given.
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	store := &StoreImpl{db: sqlxDB}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// When.
+	transaction, err := store.SettlePayment(ctx, *model.NewTransaction(nil, accountIdInt, 4, model.MustParseMoney("60.00"), 0, nil))
+
+	// Then.
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, transaction)
+	require.NoError(t, mock.ExpectationsWereMet()) // No transaction was started.
+}
+
+// expectLockAccount expects the account to be locked, with no credit limit
+// and in the default currency.
+func expectLockAccount(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT Account_ID, Document_Number, Credit_Limit, Currency, Status FROM Accounts WHERE Account_ID=? FOR UPDATE`)).
+		WithArgs(accountIdInt).
+		WillReturnRows(sqlmock.NewRows([]string{"Account_ID", "Document_Number", "Credit_Limit", "Currency", "Status"}).
+			AddRow(accountIdInt, documentNumber, nil, "USD", "ACTIVE"))
+}
+
+// expectGetAccount expects the account to be read, with no credit limit
+// and in the default currency.
+func expectGetAccount(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT Account_ID, Document_Number, Credit_Limit, Currency, Status FROM Accounts WHERE Account_ID=?`)).
+		WithArgs(accountIdInt).
+		WillReturnRows(sqlmock.NewRows([]string{"Account_ID", "Document_Number", "Credit_Limit", "Currency", "Status"}).
+			AddRow(accountIdInt, documentNumber, nil, "USD", "ACTIVE"))
+}
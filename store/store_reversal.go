@@ -0,0 +1,121 @@
+package store
+
+import (
+	"account-transactions/model"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ReverseTransaction locks the account, then its payments and debts like
+// CreateDebit, and only then the original and the transactions it was
+// allocated to, so it cannot deadlock with them. The account lock also keeps concurrent reversals of the same
+// transaction from both reversing what is left of it.
+func (s *StoreImpl) ReverseTransaction(ctx context.Context, transactionId int, amount *model.Money) (*model.TransactionImpl, error) {
+	original, err := s.GetTransaction(ctx, transactionId)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // No-op once committed.
+
+	account, err := lockAccount(ctx, tx, original.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	credits, err := getCredits(ctx, tx, original.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	debts, err := getDebts(ctx, tx, original.AccountID, now)
+	if err != nil {
+		return nil, err
+	}
+	scheduled, err := getScheduled(ctx, tx, original.AccountID, now)
+	if err != nil {
+		return nil, err
+	}
+	// Read the original again now that nothing can change its balance.
+	original, err = getTransaction(ctx, tx, transactionId, forUpdate(tx, "SELECT Transaction_ID, Account_ID, OperationType_ID, Amount, Balance, EventDate, Original_Transaction_ID, Plan_ID, Due_Date, Transfer_ID, Currency, Original_Amount, Original_Currency, FX_Rate FROM Transactions WHERE Transaction_ID=?", "FOR UPDATE"))
+	if err != nil {
+		return nil, err
+	}
+	reversed, err := sumMoney(ctx, tx, "SELECT SUM(Amount) FROM Transactions WHERE Original_Transaction_ID=?", transactionId)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+
+	allocations, err := getAllocations(ctx, tx, transactionId)
+	if err != nil {
+		return nil, err
+	}
+	allocated, err := getAllocated(ctx, tx, transactionId, allocations)
+	if err != nil {
+		return nil, err
+	}
+
+	reversal, err := model.NewReversal(original, reversed, amount)
+	if err != nil {
+		return nil, err
+	}
+	if err := account.CheckPosting(reversal.Amount); err != nil {
+		return nil, err
+	}
+	transactions, allocations, err := s.SettlementPolicy.SettleReversal(original, reversal, allocations, allocated, debts, scheduled, credits)
+	if err != nil {
+		return nil, err
+	}
+	if err := updateNegativeTransactions(ctx, tx, transactions); err != nil {
+		return nil, err
+	}
+	result, err := createTransaction(ctx, tx, *reversal)
+	if err != nil {
+		return nil, err
+	}
+	if err := insertAllocations(ctx, tx, allocations.For(*result.TransactionID)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// getAllocations returns what is still allocated between the transaction
+// and others, netted by model.Allocations.Net.
+func getAllocations(ctx context.Context, q dbtx, transactionId int) (model.Allocations, error) {
+	var allocations model.Allocations
+	err := sqlx.SelectContext(ctx, q, &allocations, q.Rebind("SELECT Credit_Transaction_ID, Debit_Transaction_ID, Amount FROM Allocations WHERE Credit_Transaction_ID=? OR Debit_Transaction_ID=? ORDER BY Allocation_ID"), transactionId, transactionId)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	return allocations.Net(), nil
+}
+
+// getAllocated locks and returns the transactions on the other side of the
+// transaction's allocations.
+func getAllocated(ctx context.Context, q dbtx, transactionId int, allocations model.Allocations) (model.Transactions, error) {
+	if len(allocations) == 0 {
+		return nil, nil
+	}
+	ids := make([]int, len(allocations))
+	for i, allocation := range allocations {
+		ids[i] = allocation.CreditTransactionID
+		if ids[i] == transactionId {
+			ids[i] = allocation.DebitTransactionID
+		}
+	}
+	query, args, err := sqlx.In(forUpdate(q, "SELECT Transaction_ID, Account_ID, OperationType_ID, Amount, Balance, Currency FROM Transactions WHERE Transaction_ID IN (?) ORDER BY Transaction_ID", "FOR UPDATE"), ids)
+	if err != nil {
+		return nil, err
+	}
+	return queryTransactions(ctx, q, query, args...)
+}
@@ -0,0 +1,77 @@
+package store
+
+import (
+	"account-transactions/model"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+func (s *StoreImpl) GetBalance(ctx context.Context, accountId int) (*model.AccountBalance, error) {
+	account, err := s.GetAccount(ctx, accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	var transactions model.Transactions
+	if err := s.db.SelectContext(ctx, &transactions, s.db.Rebind("SELECT Transaction_ID, Account_ID, OperationType_ID, Amount, Balance, Due_Date FROM Transactions WHERE Account_ID=? AND Balance <> 0 ORDER BY EventDate, Transaction_ID"), accountId); err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	held, err := getHeld(ctx, s.db, accountId, now)
+	if err != nil {
+		return nil, err
+	}
+	return model.NewAccountBalance(account, transactions, held, now), nil
+}
+
+func (s *StoreImpl) GetStatement(ctx context.Context, accountId int, from *time.Time, to *time.Time) (*model.Statement, error) {
+	if _, err := s.GetAccount(ctx, accountId); err != nil {
+		return nil, err
+	}
+
+	var opening model.Money
+	if from != nil {
+		var err error
+		// EventDate is stored in UTC without a zone, so compare in UTC.
+		opening, err = sumMoney(ctx, s.db, "SELECT SUM(Amount) FROM Transactions WHERE Account_ID=? AND EventDate < ?", accountId, from.UTC())
+		if err != nil {
+			return nil, fmt.Errorf("query error: %w", err)
+		}
+	}
+
+	query := "SELECT Transaction_ID, Account_ID, OperationType_ID, Amount, Balance, EventDate, Original_Transaction_ID, Plan_ID, Due_Date, Transfer_ID, Currency, Original_Amount, Original_Currency, FX_Rate FROM Transactions WHERE Account_ID=?"
+	args := []any{accountId}
+	if from != nil {
+		query += " AND EventDate >= ?"
+		args = append(args, from.UTC())
+	}
+	if to != nil {
+		query += " AND EventDate < ?"
+		args = append(args, to.UTC())
+	}
+	query += " ORDER BY EventDate, Transaction_ID"
+
+	var transactions model.Transactions
+	if err := s.db.SelectContext(ctx, &transactions, s.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	return model.NewStatement(accountId, from, to, opening, transactions), nil
+}
+
+// sumMoney runs a query for the SUM of a money column. SQLite loses the
+// CENTS type of an aggregated column, so there the sum is read back as
+// plain integer cents.
+func sumMoney(ctx context.Context, q dbtx, query string, args ...any) (model.Money, error) {
+	row := q.QueryRowxContext(ctx, q.Rebind(query), args...)
+	if isSQLite(q) {
+		var cents sql.NullInt64
+		err := row.Scan(&cents)
+		return model.Money(cents.Int64), err
+	}
+
+	var sum model.Money // NULL, the sum of no rows, scans as zero.
+	err := row.Scan(&sum)
+	return sum, err
+}
@@ -0,0 +1,63 @@
+package store
+
+import (
+	"account-transactions/model"
+	"context"
+	"fmt"
+	"time"
+)
+
+// UpdateAccountStatus locks the account so no transaction is posted while
+// its balance is checked and its status changed.
+func (s *StoreImpl) UpdateAccountStatus(ctx context.Context, change model.AccountStatusChange) (*model.AccountStatusChange, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // No-op once committed.
+
+	account, err := lockAccount(ctx, tx, change.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	// DATETIME has second precision, so truncate to return what is stored.
+	now := time.Now().UTC().Truncate(time.Second)
+	var balance, held model.Money
+	if change.NewStatus == model.AccountClosed {
+		if balance, err = sumMoney(ctx, tx, "SELECT SUM(Amount) FROM Transactions WHERE Account_ID=?", change.AccountID); err != nil {
+			return nil, fmt.Errorf("query error: %w", err)
+		}
+		if held, err = getHeld(ctx, tx, change.AccountID, now); err != nil {
+			return nil, err
+		}
+	}
+	if err := account.CheckTransition(change.NewStatus, balance, held); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, tx.Rebind("UPDATE Accounts SET Status=? WHERE Account_ID=?"), change.NewStatus, change.AccountID); err != nil {
+		return nil, err
+	}
+
+	changeId, err := insert(ctx, tx, "INSERT INTO AccountStatusChanges(Account_ID, Old_Status, New_Status, Reason, Changed_At) VALUES( ?, ?, ?, ?, ? )", "Change_ID",
+		change.AccountID, account.Status, change.NewStatus, change.Reason, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	change.ChangeID = &changeId
+	change.OldStatus = account.Status
+	change.ChangedAt = &now
+	return &change, nil
+}
+
+func (s *StoreImpl) ListAccountStatusChanges(ctx context.Context, accountId int) ([]model.AccountStatusChange, error) {
+	changes := []model.AccountStatusChange{}
+	err := s.db.SelectContext(ctx, &changes, s.db.Rebind("SELECT Change_ID, Account_ID, Old_Status, New_Status, Reason, Changed_At FROM AccountStatusChanges WHERE Account_ID=? ORDER BY Change_ID"), accountId)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	return changes, nil
+}
@@ -0,0 +1,146 @@
+package store
+
+import (
+	"account-transactions/model"
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// CreateTransfer locks both accounts in ID order, so transfers between the
+// same accounts in opposite directions cannot deadlock, and only then the
+// source's payments and debts like CreateDebit and the destination's debts
+// like SettlePayment.
+func (s *StoreImpl) CreateTransfer(ctx context.Context, transfer model.Transfer) (*model.Transfer, error) {
+	if transfer.SourceAccountID == transfer.DestinationAccountID {
+		return nil, fmt.Errorf("%w: account %d", model.ErrSameAccount, transfer.SourceAccountID)
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // No-op once committed.
+
+	accounts := map[int]*model.AccountImpl{}
+	for _, accountId := range []int{min(transfer.SourceAccountID, transfer.DestinationAccountID), max(transfer.SourceAccountID, transfer.DestinationAccountID)} {
+		if accounts[accountId], err = lockAccount(ctx, tx, accountId); err != nil {
+			return nil, err
+		}
+	}
+
+	// DATETIME has second precision, so truncate to return what is stored.
+	now := time.Now().UTC().Truncate(time.Second)
+	debit, credit := *transfer.Debit, *transfer.Credit
+	for _, transaction := range []model.TransactionImpl{debit, credit} {
+		if err := accounts[transaction.AccountID].CheckPosting(transaction.Amount); err != nil {
+			return nil, err
+		}
+	}
+	if err := accounts[debit.AccountID].Book(&debit); err != nil {
+		return nil, err
+	}
+	// The credit is in the debit's currency unless it was converted.
+	if credit.Currency == "" {
+		credit.Currency = debit.Currency
+	}
+	if err := accounts[credit.AccountID].Book(&credit); err != nil {
+		return nil, err
+	}
+	credits, err := getCredits(ctx, tx, debit.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	owed, err := getOwed(ctx, tx, debit.AccountID, now)
+	if err != nil {
+		return nil, err
+	}
+	held, err := getHeld(ctx, tx, debit.AccountID, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := accounts[debit.AccountID].CheckDebit(owed, credits, held, debit.Amount); err != nil {
+		return nil, err
+	}
+
+	transferId, err := insert(ctx, tx, "INSERT INTO Transfers(Source_Account_ID, Destination_Account_ID, Amount, Created_At) VALUES( ?, ?, ?, ? )", "Transfer_ID",
+		transfer.SourceAccountID, transfer.DestinationAccountID, transfer.Amount, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// The debit is paid from the source's unapplied payments first.
+	credits, allocations, rest, err := model.ProcessPositivePayments(credits, debit.Amount, debit.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if err := updateNegativeTransactions(ctx, tx, credits); err != nil {
+		return nil, err
+	}
+	debit.Balance = rest
+	debit.TransferID = &transferId
+	if transfer.Debit, err = createTransaction(ctx, tx, debit); err != nil {
+		return nil, err
+	}
+	if err := insertAllocations(ctx, tx, allocations.For(*transfer.Debit.TransactionID)); err != nil {
+		return nil, err
+	}
+
+	// The credit settles the destination's debts like a payment.
+	debts, err := getDebts(ctx, tx, credit.AccountID, now)
+	if err != nil {
+		return nil, err
+	}
+	scheduled, err := getScheduled(ctx, tx, credit.AccountID, now)
+	if err != nil {
+		return nil, err
+	}
+	transactions, allocations, rest, err := model.ProcessNegativePayments(slices.Concat(s.SettlementPolicy.Order(debts), scheduled), credit.Amount, credit.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if err := updateNegativeTransactions(ctx, tx, transactions); err != nil {
+		return nil, err
+	}
+	credit.Balance = rest
+	credit.TransferID = &transferId
+	if transfer.Credit, err = createTransaction(ctx, tx, credit); err != nil {
+		return nil, err
+	}
+	if err := insertAllocations(ctx, tx, allocations.For(*transfer.Credit.TransactionID)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	transfer.TransferID = &transferId
+	transfer.CreatedAt = &now
+	return &transfer, nil
+}
+
+func (s *StoreImpl) GetTransfer(ctx context.Context, transferId int) (*model.Transfer, error) {
+	var transfer model.Transfer
+	err := s.db.GetContext(ctx, &transfer, s.db.Rebind("SELECT Transfer_ID, Source_Account_ID, Destination_Account_ID, Amount, Created_At FROM Transfers WHERE Transfer_ID=?"), transferId)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, fmt.Errorf("%w: no transfer with id %d", ErrTransferNotFound, transferId)
+	case err != nil:
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+
+	var transactions model.Transactions
+	if err := s.db.SelectContext(ctx, &transactions, s.db.Rebind("SELECT Transaction_ID, Account_ID, OperationType_ID, Amount, Balance, EventDate, Original_Transaction_ID, Plan_ID, Due_Date, Transfer_ID, Currency, Original_Amount, Original_Currency, FX_Rate FROM Transactions WHERE Transfer_ID=? ORDER BY Transaction_ID"), transferId); err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	for _, transaction := range transactions {
+		if transaction.Amount < 0 {
+			transfer.Debit = &transaction
+		} else {
+			transfer.Credit = &transaction
+		}
+	}
+	return &transfer, nil
+}
@@ -0,0 +1,1080 @@
+// Package storetest checks that a store.Store behaves like the others.
+//
+// A backend runs the suite from its tests with a factory for empty stores:
+//
+//	func TestConformance(t *testing.T) {
+//		storetest.Run(t, func(t *testing.T) store.Store {
+//			return store.NewMemory()
+//		})
+//	}
+package storetest
+
+import (
+	"account-transactions/model"
+	"account-transactions/store"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	documentNumber   = "20251027"
+	invalidAccountId = 999999
+)
+
+// Run runs every check as a subtest. newStore is called once per subtest
+// and must return a store with the seeded operation types and no accounts,
+// transactions or idempotency keys.
+func Run(t *testing.T, newStore func(t *testing.T) store.Store) {
+	ctx := context.Background()
+
+	t.Run("AccountRoundTrip", func(t *testing.T) {
+		// Given.
+		s := newStore(t)
+
+		// When.
+		created, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+		require.NoError(t, err)
+		got, err := s.GetAccount(ctx, *created.AccountID)
+
+		// Then.
+		require.NoError(t, err)
+		assert.Equal(t, created, got)
+		assert.Equal(t, documentNumber, got.DocumentNumber)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		// Given.
+		s := newStore(t)
+
+		// When.
+		_, accountErr := s.GetAccount(ctx, invalidAccountId)
+		_, operationErr := s.GetOperation(ctx, 99)
+		_, transactionErr := s.GetTransaction(ctx, invalidAccountId)
+		_, createErr := s.CreateTransaction(ctx, *model.NewTransaction(nil, invalidAccountId, 1, -100, -100, nil))
+		_, settleErr := s.SettlePayment(ctx, *model.NewTransaction(nil, invalidAccountId, 4, 100, 0, nil))
+
+		// Then.
+		require.ErrorIs(t, accountErr, store.ErrAccountNotFound)
+		require.ErrorIs(t, operationErr, store.ErrOperationNotFound)
+		require.ErrorIs(t, transactionErr, store.ErrTransactionNotFound)
+		require.Error(t, createErr)
+		require.Error(t, settleErr)
+	})
+
+	t.Run("OperationTypes", func(t *testing.T) {
+		// Given.
+		s := newStore(t)
+
+		// When.
+		purchase, err := s.GetOperation(ctx, model.OperationTypePurchase)
+		require.NoError(t, err)
+		payment, err := s.GetOperation(ctx, model.OperationTypePayment)
+		require.NoError(t, err)
+
+		// Then.
+		assert.Equal(t, "PURCHASE", purchase.Description)
+		assert.True(t, purchase.IsDebit())
+		assert.Equal(t, "PAYMENT", payment.Description)
+		assert.False(t, payment.IsDebit())
+	})
+
+	t.Run("TransactionRoundTrip", func(t *testing.T) {
+		// Given.
+		s := newStore(t)
+		account, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+		require.NoError(t, err)
+
+		// When.
+		created, err := s.CreateTransaction(ctx, *model.NewTransaction(nil, *account.AccountID, 1, model.MustParseMoney("-12.34"), model.MustParseMoney("-12.34"), nil))
+		require.NoError(t, err)
+		got, err := s.GetTransaction(ctx, *created.TransactionID)
+
+		// Then.
+		require.NoError(t, err)
+		require.NotNil(t, got.EventDate)
+		assert.True(t, created.EventDate.Equal(*got.EventDate))
+		got.EventDate = created.EventDate
+		assert.Equal(t, created, got)
+	})
+
+	t.Run("NegativeTransactionsOldestFirst", func(t *testing.T) {
+		// Given.
+		s := newStore(t)
+		account, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+		require.NoError(t, err)
+		accountId := *account.AccountID
+		var want []int
+		for _, amount := range []string{"-30.00", "-10.00", "-20.00"} {
+			created, err := s.CreateTransaction(ctx, *model.NewTransaction(nil, accountId, 1, model.MustParseMoney(amount), model.MustParseMoney(amount), nil))
+			require.NoError(t, err)
+			want = append(want, *created.TransactionID)
+		}
+		// Neither settled debts, other operation types nor other accounts
+		// are included.
+		_, err = s.CreateTransaction(ctx, *model.NewTransaction(nil, accountId, 1, model.MustParseMoney("-5.00"), 0, nil))
+		require.NoError(t, err)
+		_, err = s.CreateTransaction(ctx, *model.NewTransaction(nil, accountId, 3, model.MustParseMoney("-5.00"), model.MustParseMoney("-5.00"), nil))
+		require.NoError(t, err)
+		other, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+		require.NoError(t, err)
+		_, err = s.CreateTransaction(ctx, *model.NewTransaction(nil, *other.AccountID, 1, model.MustParseMoney("-5.00"), model.MustParseMoney("-5.00"), nil))
+		require.NoError(t, err)
+
+		// When.
+		transactions, err := s.GetNegativeTransactions(ctx, accountId, 1)
+
+		// Then.
+		require.NoError(t, err)
+		assert.Equal(t, want, transactionIDs(transactions))
+		assert.Equal(t, model.MustParseMoney("-30.00"), transactions[0].Balance)
+	})
+
+	t.Run("UpdateNegativeTransactions", func(t *testing.T) {
+		// Given.
+		s := newStore(t)
+		account, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+		require.NoError(t, err)
+		accountId := *account.AccountID
+		for range 2 {
+			_, err := s.CreateTransaction(ctx, *model.NewTransaction(nil, accountId, 1, model.MustParseMoney("-50.00"), model.MustParseMoney("-50.00"), nil))
+			require.NoError(t, err)
+		}
+		transactions, err := s.GetNegativeTransactions(ctx, accountId, 1)
+		require.NoError(t, err)
+		require.Len(t, transactions, 2)
+
+		// When.
+		transactions[0].Balance = 0
+		transactions[1].Balance = model.MustParseMoney("-0.01")
+		require.NoError(t, s.UpdateNegativeTransactions(ctx, transactions))
+
+		// Then.
+		remaining, err := s.GetNegativeTransactions(ctx, accountId, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []int{*transactions[1].TransactionID}, transactionIDs(remaining))
+		settled, err := s.GetTransaction(ctx, *transactions[0].TransactionID)
+		require.NoError(t, err)
+		assert.Equal(t, model.Money(0), settled.Balance)
+		assert.Equal(t, model.MustParseMoney("-50.00"), settled.Amount)
+		assert.Equal(t, model.MustParseMoney("-0.01"), remaining[0].Balance)
+	})
+
+	t.Run("SettlePayment", func(t *testing.T) {
+		// Given.
+		s := newStore(t)
+		account, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+		require.NoError(t, err)
+		accountId := *account.AccountID
+		first, err := s.CreateTransaction(ctx, *model.NewTransaction(nil, accountId, 1, model.MustParseMoney("-50.00"), model.MustParseMoney("-50.00"), nil))
+		require.NoError(t, err)
+		second, err := s.CreateTransaction(ctx, *model.NewTransaction(nil, accountId, 3, model.MustParseMoney("-30.00"), model.MustParseMoney("-30.00"), nil))
+		require.NoError(t, err)
+
+		// When.
+		payment, err := s.SettlePayment(ctx, *model.NewTransaction(nil, accountId, 4, model.MustParseMoney("60.00"), 0, nil))
+
+		// Then.
+		require.NoError(t, err)
+		assert.Equal(t, model.Money(0), payment.Balance)
+		got, err := s.GetTransaction(ctx, *first.TransactionID)
+		require.NoError(t, err)
+		assert.Equal(t, model.Money(0), got.Balance)
+		got, err = s.GetTransaction(ctx, *second.TransactionID)
+		require.NoError(t, err)
+		assert.Equal(t, model.MustParseMoney("-20.00"), got.Balance)
+	})
+
+	t.Run("ListTransactions", func(t *testing.T) {
+		// Given.
+		s := newStore(t)
+		account, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+		require.NoError(t, err)
+		accountId := *account.AccountID
+		var ids []int
+		for range 3 {
+			created, err := s.CreateTransaction(ctx, *model.NewTransaction(nil, accountId, 1, -100, -100, nil))
+			require.NoError(t, err)
+			ids = append(ids, *created.TransactionID)
+		}
+
+		// When.
+		page, err := s.ListTransactions(ctx, accountId, model.TransactionFilter{Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, page, 2)
+		next, err := s.ListTransactions(ctx, accountId, model.TransactionFilter{Limit: 2, After: model.CursorAfter(page[1])})
+		require.NoError(t, err)
+		descending, err := s.ListTransactions(ctx, accountId, model.TransactionFilter{Limit: 10, Descending: true})
+		require.NoError(t, err)
+
+		// Then.
+		require.Len(t, next, 1)
+		assert.Equal(t, ids, []int{*page[0].TransactionID, *page[1].TransactionID, *next[0].TransactionID})
+		require.Len(t, descending, 3)
+		assert.Equal(t, ids[2], *descending[0].TransactionID)
+	})
+
+	t.Run("ConcurrentInserts", func(t *testing.T) {
+		// Given.
+		s := newStore(t)
+		account, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+		require.NoError(t, err)
+		accountId := *account.AccountID
+
+		// When.
+		ids := make([]int, 50)
+		var wg sync.WaitGroup
+		for i := range ids {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				created, err := s.CreateTransaction(ctx, *model.NewTransaction(nil, accountId, 1, model.MustParseMoney("-1.00"), model.MustParseMoney("-1.00"), nil))
+				if assert.NoError(t, err) {
+					ids[i] = *created.TransactionID
+				}
+			}()
+		}
+		wg.Wait()
+
+		// Then.
+		transactions, err := s.GetNegativeTransactions(ctx, accountId, 1)
+		require.NoError(t, err)
+		assert.Len(t, transactions, len(ids))
+		assert.ElementsMatch(t, ids, transactionIDs(transactions))
+	})
+
+	t.Run("CreditLimit", func(t *testing.T) {
+		// Given.
+		s := newStore(t)
+		account, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+		require.NoError(t, err)
+		accountId := *account.AccountID
+
+		// When.
+		first, err := s.UpdateCreditLimit(ctx, model.CreditLimitChange{AccountID: accountId, NewLimit: model.MustParseMoney("100.00"), Reason: "opening"})
+		require.NoError(t, err)
+		second, err := s.UpdateCreditLimit(ctx, model.CreditLimitChange{AccountID: accountId, NewLimit: model.MustParseMoney("50.00"), Reason: "review"})
+		require.NoError(t, err)
+		_, unknownErr := s.UpdateCreditLimit(ctx, model.CreditLimitChange{AccountID: invalidAccountId, NewLimit: 100, Reason: "none"})
+
+		// Then.
+		require.ErrorIs(t, unknownErr, store.ErrAccountNotFound)
+		assert.Nil(t, first.OldLimit)
+		assert.Equal(t, model.MoneyToPtr(model.MustParseMoney("100.00")), second.OldLimit)
+		got, err := s.GetAccount(ctx, accountId)
+		require.NoError(t, err)
+		assert.Equal(t, model.MoneyToPtr(model.MustParseMoney("50.00")), got.CreditLimit)
+		history, err := s.ListCreditLimitChanges(ctx, accountId)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		assert.Equal(t, []int{*first.ChangeID, *second.ChangeID}, []int{*history[0].ChangeID, *history[1].ChangeID})
+		assert.Equal(t, "review", history[1].Reason)
+		assert.Equal(t, second.OldLimit, history[1].OldLimit)
+		assert.Equal(t, second.NewLimit, history[1].NewLimit)
+	})
+
+	t.Run("AccountStatus", func(t *testing.T) {
+		// Given.
+		s := newStore(t)
+		account, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+		require.NoError(t, err)
+		accountId := *account.AccountID
+		other, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+		require.NoError(t, err)
+		_, err = s.CreateDebit(ctx, *model.NewTransaction(nil, accountId, 1, model.MustParseMoney("-50.00"), 0, nil))
+		require.NoError(t, err)
+
+		// When.
+		blocked, err := s.UpdateAccountStatus(ctx, model.AccountStatusChange{AccountID: accountId, NewStatus: model.AccountBlocked, Reason: "fraud report"})
+		require.NoError(t, err)
+		_, blockedDebitErr := s.CreateDebit(ctx, *model.NewTransaction(nil, accountId, 1, model.MustParseMoney("-1.00"), 0, nil))
+		_, blockedTransferErr := s.CreateTransfer(ctx, *model.NewTransfer(model.TransferRequest{SourceAccountID: accountId, DestinationAccountID: *other.AccountID, Amount: model.MustParseMoney("1.00")}))
+		_, err = s.SettlePayment(ctx, *model.NewTransaction(nil, accountId, 4, model.MustParseMoney("20.00"), 0, nil))
+		require.NoError(t, err)
+		_, balanceErr := s.UpdateAccountStatus(ctx, model.AccountStatusChange{AccountID: accountId, NewStatus: model.AccountClosed, Reason: "customer request"})
+		_, err = s.SettlePayment(ctx, *model.NewTransaction(nil, accountId, 4, model.MustParseMoney("30.00"), 0, nil))
+		require.NoError(t, err)
+		closed, err := s.UpdateAccountStatus(ctx, model.AccountStatusChange{AccountID: accountId, NewStatus: model.AccountClosed, Reason: "customer request"})
+		require.NoError(t, err)
+		_, closedPaymentErr := s.SettlePayment(ctx, *model.NewTransaction(nil, accountId, 4, model.MustParseMoney("1.00"), 0, nil))
+		_, closedTransferErr := s.CreateTransfer(ctx, *model.NewTransfer(model.TransferRequest{SourceAccountID: *other.AccountID, DestinationAccountID: accountId, Amount: model.MustParseMoney("1.00")}))
+		_, reopenErr := s.UpdateAccountStatus(ctx, model.AccountStatusChange{AccountID: accountId, NewStatus: model.AccountActive, Reason: "mistake"})
+		_, unknownErr := s.UpdateAccountStatus(ctx, model.AccountStatusChange{AccountID: invalidAccountId, NewStatus: model.AccountBlocked, Reason: "none"})
+
+		// Then.
+		require.ErrorIs(t, blockedDebitErr, model.ErrAccountBlocked)
+		require.ErrorIs(t, blockedTransferErr, model.ErrAccountBlocked)
+		require.ErrorIs(t, balanceErr, model.ErrBalanceNotZero)
+		require.ErrorIs(t, closedPaymentErr, model.ErrAccountClosed)
+		require.ErrorIs(t, closedTransferErr, model.ErrAccountClosed)
+		require.ErrorIs(t, reopenErr, model.ErrInvalidStatusTransition)
+		require.ErrorIs(t, unknownErr, store.ErrAccountNotFound)
+		assert.Equal(t, model.AccountActive, blocked.OldStatus)
+		assert.Equal(t, model.AccountBlocked, closed.OldStatus)
+		got, err := s.GetAccount(ctx, accountId)
+		require.NoError(t, err)
+		assert.Equal(t, model.AccountClosed, got.Status)
+		history, err := s.ListAccountStatusChanges(ctx, accountId)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		assert.Equal(t, []int{*blocked.ChangeID, *closed.ChangeID}, []int{*history[0].ChangeID, *history[1].ChangeID})
+		assert.Equal(t, model.AccountBlocked, history[1].OldStatus)
+		assert.Equal(t, model.AccountClosed, history[1].NewStatus)
+		assert.Equal(t, "customer request", history[1].Reason)
+	})
+
+	t.Run("AccountStatusPendingAuthorizations", func(t *testing.T) {
+		// Given.
+		s := newStore(t)
+		account, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+		require.NoError(t, err)
+		accountId := *account.AccountID
+		later := time.Now().Add(time.Hour)
+		authorization, err := s.CreateAuthorization(ctx, model.AuthorizationImpl{AccountID: accountId, OperationTypeID: 1, Amount: model.MustParseMoney("-25.00"), ExpiresAt: &later})
+		require.NoError(t, err)
+
+		// When.
+		_, pendingErr := s.UpdateAccountStatus(ctx, model.AccountStatusChange{AccountID: accountId, NewStatus: model.AccountClosed, Reason: "customer request"})
+		_, err = s.VoidAuthorization(ctx, *authorization.AuthorizationID)
+		require.NoError(t, err)
+		closed, err := s.UpdateAccountStatus(ctx, model.AccountStatusChange{AccountID: accountId, NewStatus: model.AccountClosed, Reason: "customer request"})
+
+		// Then.
+		require.ErrorIs(t, pendingErr, model.ErrAuthorizationsPending)
+		require.NoError(t, err)
+		assert.Equal(t, model.AccountClosed, closed.NewStatus)
+	})
+
+	t.Run("CreateDebit", func(t *testing.T) {
+		// Given.
+		s := newStore(t)
+		account, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+		require.NoError(t, err)
+		accountId := *account.AccountID
+		unlimited, err := s.CreateDebit(ctx, *model.NewTransaction(nil, accountId, 1, model.MustParseMoney("-500.00"), 0, nil))
+		require.NoError(t, err)
+		_, err = s.SettlePayment(ctx, *model.NewTransaction(nil, accountId, 4, model.MustParseMoney("500.00"), 0, nil))
+		require.NoError(t, err)
+		_, err = s.UpdateCreditLimit(ctx, model.CreditLimitChange{AccountID: accountId, NewLimit: model.MustParseMoney("100.00"), Reason: "opening"})
+		require.NoError(t, err)
+
+		// When.
+		purchase, err := s.CreateDebit(ctx, *model.NewTransaction(nil, accountId, 1, model.MustParseMoney("-60.00"), 0, nil))
+		require.NoError(t, err)
+		_, exceededErr := s.CreateDebit(ctx, *model.NewTransaction(nil, accountId, 3, model.MustParseMoney("-40.01"), 0, nil))
+		_, err = s.SettlePayment(ctx, *model.NewTransaction(nil, accountId, 4, model.MustParseMoney("10.00"), 0, nil))
+		require.NoError(t, err)
+		withdrawal, err := s.CreateDebit(ctx, *model.NewTransaction(nil, accountId, 3, model.MustParseMoney("-50.00"), 0, nil))
+		_, unknownErr := s.CreateDebit(ctx, *model.NewTransaction(nil, invalidAccountId, 1, -100, 0, nil))
+
+		// Then.
+		assert.Equal(t, unlimited.Amount, unlimited.Balance)
+		assert.Equal(t, model.MustParseMoney("-60.00"), purchase.Balance)
+		require.ErrorIs(t, exceededErr, model.ErrCreditLimitExceeded)
+		// The payment restored enough credit for the withdrawal.
+		require.NoError(t, err)
+		assert.Equal(t, model.MustParseMoney("-50.00"), withdrawal.Balance)
+		require.ErrorIs(t, unknownErr, store.ErrAccountNotFound)
+	})
+
+	t.Run("OverpaymentCredit", func(t *testing.T) {
+		// Given.
+		s := newStore(t)
+		account, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+		require.NoError(t, err)
+		accountId := *account.AccountID
+		other, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+		require.NoError(t, err)
+		first, err := s.SettlePayment(ctx, *model.NewTransaction(nil, accountId, 4, model.MustParseMoney("40.00"), 0, nil))
+		require.NoError(t, err)
+		second, err := s.SettlePayment(ctx, *model.NewTransaction(nil, accountId, 4, model.MustParseMoney("30.00"), 0, nil))
+		require.NoError(t, err)
+		_, err = s.UpdateCreditLimit(ctx, model.CreditLimitChange{AccountID: accountId, NewLimit: model.MustParseMoney("20.00"), Reason: "opening"})
+		require.NoError(t, err)
+
+		// When.
+		covered, err := s.CreateDebit(ctx, *model.NewTransaction(nil, accountId, 1, model.MustParseMoney("-50.00"), 0, nil))
+		require.NoError(t, err)
+		// Only the 30.00 not covered by the payments counts against the limit.
+		_, exceededErr := s.CreateDebit(ctx, *model.NewTransaction(nil, accountId, 3, model.MustParseMoney("-40.01"), 0, nil))
+		partly, err := s.CreateDebit(ctx, *model.NewTransaction(nil, accountId, 3, model.MustParseMoney("-40.00"), 0, nil))
+		require.NoError(t, err)
+		otherDebit, err := s.CreateDebit(ctx, *model.NewTransaction(nil, *other.AccountID, 1, model.MustParseMoney("-5.00"), 0, nil))
+		require.NoError(t, err)
+
+		// Then.
+		assert.Equal(t, model.Money(0), covered.Balance)
+		require.ErrorIs(t, exceededErr, model.ErrCreditLimitExceeded)
+		assert.Equal(t, model.MustParseMoney("-20.00"), partly.Balance)
+		assert.Equal(t, model.MustParseMoney("-5.00"), otherDebit.Balance)
+		got, err := s.GetTransaction(ctx, *first.TransactionID)
+		require.NoError(t, err)
+		assert.Equal(t, model.Money(0), got.Balance)
+		got, err = s.GetTransaction(ctx, *second.TransactionID)
+		require.NoError(t, err)
+		assert.Equal(t, model.Money(0), got.Balance)
+		assert.Equal(t, model.MustParseMoney("30.00"), got.Amount)
+	})
+
+	t.Run("Balance", func(t *testing.T) {
+		// Given.
+		s := newStore(t)
+		account, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+		require.NoError(t, err)
+		accountId := *account.AccountID
+		for _, debit := range []struct {
+			operationTypeId int
+			amount          string
+		}{{1, "-30.00"}, {3, "-20.00"}, {1, "-12.50"}} {
+			_, err := s.CreateDebit(ctx, *model.NewTransaction(nil, accountId, debit.operationTypeId, model.MustParseMoney(debit.amount), 0, nil))
+			require.NoError(t, err)
+		}
+		_, err = s.SettlePayment(ctx, *model.NewTransaction(nil, accountId, 4, model.MustParseMoney("40.00"), 0, nil))
+		require.NoError(t, err)
+		_, err = s.UpdateCreditLimit(ctx, model.CreditLimitChange{AccountID: accountId, NewLimit: model.MustParseMoney("100.00"), Reason: "opening"})
+		require.NoError(t, err)
+		paid, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+		require.NoError(t, err)
+		_, err = s.SettlePayment(ctx, *model.NewTransaction(nil, *paid.AccountID, 4, model.MustParseMoney("7.25"), 0, nil))
+		require.NoError(t, err)
+
+		// When.
+		balance, err := s.GetBalance(ctx, accountId)
+		require.NoError(t, err)
+		paidBalance, err := s.GetBalance(ctx, *paid.AccountID)
+		require.NoError(t, err)
+		_, unknownErr := s.GetBalance(ctx, invalidAccountId)
+
+		// Then.
+		assert.Equal(t, &model.AccountBalance{
+			AccountID:   accountId,
+			Currency:    model.DefaultCurrency,
+			Outstanding: model.MustParseMoney("22.50"),
+			Debts: []model.OperationDebt{
+				{OperationTypeID: 1, Outstanding: model.MustParseMoney("12.50")},
+				{OperationTypeID: 3, Outstanding: model.MustParseMoney("10.00")},
+			},
+			CreditLimit:     model.MoneyToPtr(model.MustParseMoney("100.00")),
+			AvailableCredit: model.MoneyToPtr(model.MustParseMoney("77.50")),
+		}, balance)
+		assert.Equal(t, &model.AccountBalance{
+			AccountID:     *paid.AccountID,
+			Currency:      model.DefaultCurrency,
+			Debts:         []model.OperationDebt{},
+			PaymentCredit: model.MustParseMoney("7.25"),
+		}, paidBalance)
+		require.ErrorIs(t, unknownErr, store.ErrAccountNotFound)
+	})
+
+	t.Run("Statement", func(t *testing.T) {
+		// Given.
+		s := newStore(t)
+		account, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+		require.NoError(t, err)
+		accountId := *account.AccountID
+		var want []int
+		for _, amount := range []string{"-30.00", "-20.01"} {
+			created, err := s.CreateDebit(ctx, *model.NewTransaction(nil, accountId, 1, model.MustParseMoney(amount), 0, nil))
+			require.NoError(t, err)
+			want = append(want, *created.TransactionID)
+		}
+		payment, err := s.SettlePayment(ctx, *model.NewTransaction(nil, accountId, 4, model.MustParseMoney("60.00"), 0, nil))
+		require.NoError(t, err)
+		want = append(want, *payment.TransactionID)
+		other, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+		require.NoError(t, err)
+		_, err = s.CreateDebit(ctx, *model.NewTransaction(nil, *other.AccountID, 1, -100, 0, nil))
+		require.NoError(t, err)
+		past := time.Now().Add(-time.Hour)
+		future := time.Now().Add(time.Hour)
+
+		// When.
+		all, err := s.GetStatement(ctx, accountId, nil, nil)
+		require.NoError(t, err)
+		before, err := s.GetStatement(ctx, accountId, nil, &past)
+		require.NoError(t, err)
+		after, err := s.GetStatement(ctx, accountId, &future, nil)
+		require.NoError(t, err)
+		_, unknownErr := s.GetStatement(ctx, invalidAccountId, nil, nil)
+
+		// Then.
+		assert.Equal(t, want, transactionIDs(all.Transactions))
+		assert.Equal(t, model.Money(0), all.OpeningBalance)
+		assert.Equal(t, model.MustParseMoney("9.99"), all.ClosingBalance)
+		assert.Empty(t, before.Transactions)
+		assert.Equal(t, model.Money(0), before.ClosingBalance)
+		// Everything happened before the period, so it is all in the
+		// opening balance.
+		assert.Empty(t, after.Transactions)
+		assert.Equal(t, model.MustParseMoney("9.99"), after.OpeningBalance)
+		assert.Equal(t, model.MustParseMoney("9.99"), after.ClosingBalance)
+		require.ErrorIs(t, unknownErr, store.ErrAccountNotFound)
+	})
+
+	t.Run("Reversal", func(t *testing.T) {
+		// Given.
+		s := newStore(t)
+		account, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+		require.NoError(t, err)
+		accountId := *account.AccountID
+		paid, err := s.CreateDebit(ctx, *model.NewTransaction(nil, accountId, 1, model.MustParseMoney("-50.00"), 0, nil))
+		require.NoError(t, err)
+		open, err := s.CreateDebit(ctx, *model.NewTransaction(nil, accountId, 1, model.MustParseMoney("-40.00"), 0, nil))
+		require.NoError(t, err)
+		payment, err := s.SettlePayment(ctx, *model.NewTransaction(nil, accountId, 4, model.MustParseMoney("60.00"), 0, nil))
+		require.NoError(t, err)
+
+		// When.
+		// Cancels the 30.00 still open on the second purchase, and gives
+		// the payment back 5.00 of the 10.00 it paid of it.
+		partial, err := s.ReverseTransaction(ctx, *open.TransactionID, model.MoneyToPtr(model.MustParseMoney("35.00")))
+		require.NoError(t, err)
+		_, exceedsErr := s.ReverseTransaction(ctx, *open.TransactionID, model.MoneyToPtr(model.MustParseMoney("5.01")))
+		rest, err := s.ReverseTransaction(ctx, *open.TransactionID, nil)
+		require.NoError(t, err)
+		_, doubleErr := s.ReverseTransaction(ctx, *open.TransactionID, nil)
+		_, reversalErr := s.ReverseTransaction(ctx, *partial.TransactionID, nil)
+		// The first purchase was paid in full, so the payment gets it all
+		// back.
+		refund, err := s.ReverseTransaction(ctx, *paid.TransactionID, nil)
+		require.NoError(t, err)
+		_, unknownErr := s.ReverseTransaction(ctx, invalidAccountId, nil)
+
+		// Then.
+		assert.Equal(t, model.OperationTypeRefund, partial.OperationTypeID)
+		assert.Equal(t, model.MustParseMoney("35.00"), partial.Amount)
+		assert.Equal(t, open.TransactionID, partial.OriginalTransactionID)
+		assert.Equal(t, model.Money(0), partial.Balance)
+		require.ErrorIs(t, exceedsErr, model.ErrReversalExceedsAmount)
+		assert.Equal(t, model.MustParseMoney("5.00"), rest.Amount)
+		assert.Equal(t, model.Money(0), rest.Balance)
+		require.ErrorIs(t, doubleErr, model.ErrAlreadyReversed)
+		require.ErrorIs(t, reversalErr, model.ErrNotReversible)
+		assert.Equal(t, model.Money(0), refund.Balance)
+		require.ErrorIs(t, unknownErr, store.ErrTransactionNotFound)
+		got, err := s.GetTransaction(ctx, *open.TransactionID)
+		require.NoError(t, err)
+		assert.Equal(t, model.Money(0), got.Balance)
+		got, err = s.GetTransaction(ctx, *rest.TransactionID)
+		require.NoError(t, err)
+		assert.Equal(t, open.TransactionID, got.OriginalTransactionID)
+		got, err = s.GetTransaction(ctx, *payment.TransactionID)
+		require.NoError(t, err)
+		assert.Equal(t, model.MustParseMoney("60.00"), got.Balance)
+
+		// When.
+		// Both purchases were refunded, so the payment is all credit again.
+		paymentReversal, err := s.ReverseTransaction(ctx, *payment.TransactionID, nil)
+		require.NoError(t, err)
+
+		// Then.
+		assert.Equal(t, model.OperationTypePaymentReversal, paymentReversal.OperationTypeID)
+		assert.Equal(t, model.MustParseMoney("-60.00"), paymentReversal.Amount)
+		assert.Equal(t, model.Money(0), paymentReversal.Balance)
+		balance, err := s.GetBalance(ctx, accountId)
+		require.NoError(t, err)
+		assert.Equal(t, model.Money(0), balance.Outstanding)
+		assert.Equal(t, model.Money(0), balance.PaymentCredit)
+	})
+
+	t.Run("PaymentReversalReopensDebts", func(t *testing.T) {
+		// Given.
+		s := newStore(t)
+		account, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+		require.NoError(t, err)
+		accountId := *account.AccountID
+		purchase, err := s.CreateDebit(ctx, *model.NewTransaction(nil, accountId, 1, model.MustParseMoney("-80.00"), 0, nil))
+		require.NoError(t, err)
+		payment, err := s.SettlePayment(ctx, *model.NewTransaction(nil, accountId, 4, model.MustParseMoney("100.00"), 0, nil))
+		require.NoError(t, err)
+
+		// When.
+		partial, err := s.ReverseTransaction(ctx, *payment.TransactionID, model.MoneyToPtr(model.MustParseMoney("50.00")))
+		require.NoError(t, err)
+		// A later payment's credit pays for what the rest reopens.
+		later, err := s.SettlePayment(ctx, *model.NewTransaction(nil, accountId, 4, model.MustParseMoney("40.00"), 0, nil))
+		require.NoError(t, err)
+		rest, err := s.ReverseTransaction(ctx, *payment.TransactionID, nil)
+		require.NoError(t, err)
+
+		// Then.
+		// The first reversal cancels the 20.00 left of the payment and
+		// reopens 30.00 of the purchase, which the later payment settles.
+		// The rest reopens the other 50.00, of which the later payment's
+		// 10.00 credit pays some.
+		assert.Equal(t, model.Money(0), partial.Balance)
+		assert.Equal(t, model.Money(0), rest.Balance)
+		assert.Equal(t, model.MustParseMoney("-50.00"), rest.Amount)
+		got, err := s.GetTransaction(ctx, *purchase.TransactionID)
+		require.NoError(t, err)
+		assert.Equal(t, model.MustParseMoney("-40.00"), got.Balance)
+		got, err = s.GetTransaction(ctx, *later.TransactionID)
+		require.NoError(t, err)
+		assert.Equal(t, model.Money(0), got.Balance)
+		balance, err := s.GetBalance(ctx, accountId)
+		require.NoError(t, err)
+		assert.Equal(t, model.MustParseMoney("40.00"), balance.Outstanding)
+	})
+
+	t.Run("ConcurrentReversals", func(t *testing.T) {
+		// Given.
+		s := newStore(t)
+		account, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+		require.NoError(t, err)
+		purchase, err := s.CreateDebit(ctx, *model.NewTransaction(nil, *account.AccountID, 1, model.MustParseMoney("-10.00"), 0, nil))
+		require.NoError(t, err)
+
+		// When.
+		var mu sync.Mutex
+		var reversed, refused int
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := s.ReverseTransaction(ctx, *purchase.TransactionID, nil)
+				mu.Lock()
+				defer mu.Unlock()
+				if errors.Is(err, model.ErrAlreadyReversed) {
+					refused++
+				} else if assert.NoError(t, err) {
+					reversed++
+				}
+			}()
+		}
+		wg.Wait()
+
+		// Then.
+		assert.Equal(t, 1, reversed)
+		assert.Equal(t, 9, refused)
+	})
+
+	t.Run("ConcurrentDebits", func(t *testing.T) {
+		// Given.
+		s := newStore(t)
+		account, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+		require.NoError(t, err)
+		accountId := *account.AccountID
+		_, err = s.UpdateCreditLimit(ctx, model.CreditLimitChange{AccountID: accountId, NewLimit: model.MustParseMoney("10.00"), Reason: "opening"})
+		require.NoError(t, err)
+
+		// When.
+		var mu sync.Mutex
+		var created, rejected int
+		var wg sync.WaitGroup
+		for range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := s.CreateDebit(ctx, *model.NewTransaction(nil, accountId, 1, model.MustParseMoney("-1.00"), 0, nil))
+				mu.Lock()
+				defer mu.Unlock()
+				if errors.Is(err, model.ErrCreditLimitExceeded) {
+					rejected++
+				} else if assert.NoError(t, err) {
+					created++
+				}
+			}()
+		}
+		wg.Wait()
+
+		// Then.
+		// Exactly the limit is taken, however the debits interleave.
+		assert.Equal(t, 10, created)
+		assert.Equal(t, 10, rejected)
+	})
+
+	t.Run("Authorization", func(t *testing.T) {
+		// Given.
+		s := newStore(t)
+		account, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+		require.NoError(t, err)
+		accountId := *account.AccountID
+		_, err = s.UpdateCreditLimit(ctx, model.CreditLimitChange{AccountID: accountId, NewLimit: model.MustParseMoney("100.00"), Reason: "opening"})
+		require.NoError(t, err)
+		later, earlier := time.Now().Add(time.Hour), time.Now().Add(-time.Minute)
+		authorize := func(amount string, expiresAt time.Time) (*model.AuthorizationImpl, error) {
+			return s.CreateAuthorization(ctx, model.AuthorizationImpl{AccountID: accountId, OperationTypeID: 1, Amount: model.MustParseMoney(amount), ExpiresAt: &expiresAt})
+		}
+
+		// When.
+		held, err := authorize("-60.00", later)
+		require.NoError(t, err)
+		_, debitErr := s.CreateDebit(ctx, *model.NewTransaction(nil, accountId, 1, model.MustParseMoney("-40.01"), 0, nil))
+		_, holdErr := authorize("-40.01", later)
+		heldBalance, err := s.GetBalance(ctx, accountId)
+		require.NoError(t, err)
+		captured, err := s.CaptureAuthorization(ctx, *held.AuthorizationID, model.MoneyToPtr(model.MustParseMoney("45.00")))
+		require.NoError(t, err)
+		_, recaptureErr := s.CaptureAuthorization(ctx, *held.AuthorizationID, nil)
+		capturedBalance, err := s.GetBalance(ctx, accountId)
+		require.NoError(t, err)
+		voided, err := authorize("-55.00", later)
+		require.NoError(t, err)
+		voided, err = s.VoidAuthorization(ctx, *voided.AuthorizationID)
+		require.NoError(t, err)
+		_, revoidErr := s.VoidAuthorization(ctx, *voided.AuthorizationID)
+		lapsed, err := authorize("-10.00", earlier)
+		require.NoError(t, err)
+		_, lapsedErr := s.CaptureAuthorization(ctx, *lapsed.AuthorizationID, nil)
+		expired, err := s.ExpireAuthorizations(ctx)
+		require.NoError(t, err)
+		_, unknownErr := s.GetAuthorization(ctx, invalidAccountId)
+		_, unknownAccountErr := s.CreateAuthorization(ctx, model.AuthorizationImpl{AccountID: invalidAccountId, OperationTypeID: 1, Amount: -100, ExpiresAt: &later})
+
+		// Then.
+		assert.Equal(t, model.AuthorizationPending, held.Status)
+		assert.NotNil(t, held.CreatedAt)
+		require.ErrorIs(t, debitErr, model.ErrCreditLimitExceeded)
+		require.ErrorIs(t, holdErr, model.ErrCreditLimitExceeded)
+		assert.Equal(t, model.MustParseMoney("60.00"), heldBalance.Held)
+		assert.Equal(t, model.MustParseMoney("40.00"), *heldBalance.AvailableCredit)
+
+		assert.Equal(t, model.MustParseMoney("-45.00"), captured.Amount)
+		assert.Equal(t, model.MustParseMoney("-45.00"), captured.Balance)
+		require.ErrorIs(t, recaptureErr, model.ErrAuthorizationClosed)
+		got, err := s.GetAuthorization(ctx, *held.AuthorizationID)
+		require.NoError(t, err)
+		assert.Equal(t, model.AuthorizationCaptured, got.Status)
+		assert.Equal(t, captured.TransactionID, got.TransactionID)
+		assert.NotNil(t, got.ClosedAt)
+		// The rest of the partial capture was released.
+		assert.Equal(t, model.Money(0), capturedBalance.Held)
+		assert.Equal(t, model.MustParseMoney("55.00"), *capturedBalance.AvailableCredit)
+
+		assert.Equal(t, model.AuthorizationVoided, voided.Status)
+		require.ErrorIs(t, revoidErr, model.ErrAuthorizationClosed)
+		require.ErrorIs(t, lapsedErr, model.ErrAuthorizationClosed)
+		assert.Equal(t, 1, expired)
+		got, err = s.GetAuthorization(ctx, *lapsed.AuthorizationID)
+		require.NoError(t, err)
+		assert.Equal(t, model.AuthorizationExpired, got.Status)
+		require.ErrorIs(t, unknownErr, store.ErrAuthorizationNotFound)
+		require.ErrorIs(t, unknownAccountErr, store.ErrAccountNotFound)
+	})
+
+	t.Run("ConcurrentAuthorizations", func(t *testing.T) {
+		// Given.
+		s := newStore(t)
+		account, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+		require.NoError(t, err)
+		accountId := *account.AccountID
+		_, err = s.UpdateCreditLimit(ctx, model.CreditLimitChange{AccountID: accountId, NewLimit: model.MustParseMoney("10.00"), Reason: "opening"})
+		require.NoError(t, err)
+		expiresAt := time.Now().Add(time.Hour)
+
+		// When.
+		var mu sync.Mutex
+		var created, rejected int
+		var wg sync.WaitGroup
+		for i := range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				// Holds and debits share the limit.
+				var err error
+				if i%2 == 0 {
+					_, err = s.CreateAuthorization(ctx, model.AuthorizationImpl{AccountID: accountId, OperationTypeID: 1, Amount: model.MustParseMoney("-1.00"), ExpiresAt: &expiresAt})
+				} else {
+					_, err = s.CreateDebit(ctx, *model.NewTransaction(nil, accountId, 1, model.MustParseMoney("-1.00"), 0, nil))
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				if errors.Is(err, model.ErrCreditLimitExceeded) {
+					rejected++
+				} else if assert.NoError(t, err) {
+					created++
+				}
+			}()
+		}
+		wg.Wait()
+
+		// Then.
+		assert.Equal(t, 10, created)
+		assert.Equal(t, 10, rejected)
+	})
+
+	t.Run("InstallmentPlan", func(t *testing.T) {
+		// Given.
+		s := newStore(t)
+		account, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+		require.NoError(t, err)
+		accountId := *account.AccountID
+		_, err = s.UpdateCreditLimit(ctx, model.CreditLimitChange{AccountID: accountId, NewLimit: model.MustParseMoney("100.00"), Reason: "opening"})
+		require.NoError(t, err)
+		_, err = s.SettlePayment(ctx, *model.NewTransaction(nil, accountId, 4, model.MustParseMoney("10.00"), 0, nil))
+		require.NoError(t, err)
+		purchase := *model.NewTransaction(nil, accountId, model.OperationTypeInstallmentPurchase, model.MustParseMoney("-90.00"), 0, nil)
+
+		// When.
+		plan, err := s.CreateInstallmentPlan(ctx, purchase, 3)
+		require.NoError(t, err)
+		// The installments that are not due yet count against the limit.
+		_, exceededErr := s.CreateDebit(ctx, *model.NewTransaction(nil, accountId, 1, model.MustParseMoney("-20.01"), 0, nil))
+		balance, err := s.GetBalance(ctx, accountId)
+		require.NoError(t, err)
+		// The payment settles the due installment, then pays the next early.
+		payment, err := s.SettlePayment(ctx, *model.NewTransaction(nil, accountId, 4, model.MustParseMoney("50.00"), 0, nil))
+		require.NoError(t, err)
+		got, err := s.GetInstallmentPlan(ctx, *plan.PlanID)
+		require.NoError(t, err)
+		_, unknownErr := s.GetInstallmentPlan(ctx, invalidAccountId)
+		_, unknownAccountErr := s.CreateInstallmentPlan(ctx, *model.NewTransaction(nil, invalidAccountId, 2, -100, 0, nil), 2)
+
+		// Then.
+		require.NotNil(t, plan.PlanID)
+		assert.Equal(t, 3, plan.InstallmentCount)
+		require.Len(t, plan.Debits, 3)
+		// The earlier payment paid part of the first installment.
+		assert.Equal(t, []model.Money{model.MustParseMoney("-20.00"), model.MustParseMoney("-30.00"), model.MustParseMoney("-30.00")},
+			[]model.Money{plan.Debits[0].Balance, plan.Debits[1].Balance, plan.Debits[2].Balance})
+		assert.True(t, plan.Debits[1].DueDate.After(*plan.Debits[0].DueDate))
+		require.ErrorIs(t, exceededErr, model.ErrCreditLimitExceeded)
+		assert.Equal(t, model.MustParseMoney("20.00"), balance.Outstanding)
+		assert.Equal(t, model.MustParseMoney("60.00"), balance.Scheduled)
+		assert.Equal(t, model.MustParseMoney("20.00"), *balance.AvailableCredit)
+
+		assert.Equal(t, model.Money(0), payment.Balance)
+		assert.Equal(t, plan.PlanID, got.PlanID)
+		assert.Equal(t, model.MustParseMoney("-90.00"), got.Amount)
+		assert.Equal(t, transactionIDs(plan.Debits), transactionIDs(got.Debits))
+		assert.Equal(t, []model.Money{0, 0, model.MustParseMoney("-30.00")},
+			[]model.Money{got.Debits[0].Balance, got.Debits[1].Balance, got.Debits[2].Balance})
+		assert.Equal(t, plan.PlanID, got.Debits[2].PlanID)
+		require.ErrorIs(t, unknownErr, store.ErrInstallmentPlanNotFound)
+		require.ErrorIs(t, unknownAccountErr, store.ErrAccountNotFound)
+	})
+
+	t.Run("Transfer", func(t *testing.T) {
+		// Given.
+		s := newStore(t)
+		source, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+		require.NoError(t, err)
+		sourceId := *source.AccountID
+		destination, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+		require.NoError(t, err)
+		destinationId := *destination.AccountID
+		_, err = s.UpdateCreditLimit(ctx, model.CreditLimitChange{AccountID: sourceId, NewLimit: model.MustParseMoney("50.00"), Reason: "opening"})
+		require.NoError(t, err)
+		_, err = s.SettlePayment(ctx, *model.NewTransaction(nil, sourceId, 4, model.MustParseMoney("20.00"), 0, nil))
+		require.NoError(t, err)
+		debt, err := s.CreateDebit(ctx, *model.NewTransaction(nil, destinationId, 1, model.MustParseMoney("-30.00"), 0, nil))
+		require.NoError(t, err)
+		transfer := func(from, to int, amount string) (*model.Transfer, error) {
+			return s.CreateTransfer(ctx, *model.NewTransfer(model.TransferRequest{SourceAccountID: from, DestinationAccountID: to, Amount: model.MustParseMoney(amount)}))
+		}
+
+		// When.
+		// The source's payment covers 20.00 and the limit the rest.
+		_, exceededErr := transfer(sourceId, destinationId, "70.01")
+		created, err := transfer(sourceId, destinationId, "45.00")
+		require.NoError(t, err)
+		got, err := s.GetTransfer(ctx, *created.TransferID)
+		require.NoError(t, err)
+		_, sameErr := transfer(sourceId, sourceId, "1.00")
+		_, unknownAccountErr := transfer(sourceId, invalidAccountId, "1.00")
+		_, unknownErr := s.GetTransfer(ctx, invalidAccountId)
+
+		// Then.
+		require.ErrorIs(t, exceededErr, model.ErrCreditLimitExceeded)
+		require.NotNil(t, created.TransferID)
+		assert.Equal(t, model.MustParseMoney("45.00"), created.Amount)
+		assert.Equal(t, model.MustParseMoney("-45.00"), created.Debit.Amount)
+		assert.Equal(t, model.MustParseMoney("-25.00"), created.Debit.Balance)
+		assert.Equal(t, model.OperationTypeTransferOut, created.Debit.OperationTypeID)
+		assert.Equal(t, created.TransferID, created.Debit.TransferID)
+		// The credit settled the destination's debt and keeps the rest.
+		assert.Equal(t, model.MustParseMoney("45.00"), created.Credit.Amount)
+		assert.Equal(t, model.MustParseMoney("15.00"), created.Credit.Balance)
+		assert.Equal(t, model.OperationTypeTransferIn, created.Credit.OperationTypeID)
+		assert.Equal(t, created.TransferID, created.Credit.TransferID)
+		settled, err := s.GetTransaction(ctx, *debt.TransactionID)
+		require.NoError(t, err)
+		assert.Equal(t, model.Money(0), settled.Balance)
+
+		assert.Equal(t, sourceId, got.SourceAccountID)
+		assert.Equal(t, destinationId, got.DestinationAccountID)
+		assert.Equal(t, created.Debit.TransactionID, got.Debit.TransactionID)
+		assert.Equal(t, created.Credit.TransactionID, got.Credit.TransactionID)
+		require.ErrorIs(t, sameErr, model.ErrSameAccount)
+		require.ErrorIs(t, unknownAccountErr, store.ErrAccountNotFound)
+		require.ErrorIs(t, unknownErr, store.ErrTransferNotFound)
+	})
+
+	t.Run("ConcurrentTransfers", func(t *testing.T) {
+		// Given.
+		s := newStore(t)
+		first, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+		require.NoError(t, err)
+		second, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+		require.NoError(t, err)
+		accountIds := []int{*first.AccountID, *second.AccountID}
+
+		// When.
+		// Transfers both ways lock both accounts without deadlocking.
+		var wg sync.WaitGroup
+		for i := range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := s.CreateTransfer(ctx, *model.NewTransfer(model.TransferRequest{SourceAccountID: accountIds[i%2], DestinationAccountID: accountIds[1-i%2], Amount: model.MustParseMoney("1.00")}))
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		// Then.
+		// Both accounts sent what they received, so nothing is left open.
+		for _, accountId := range accountIds {
+			balance, err := s.GetBalance(ctx, accountId)
+			require.NoError(t, err)
+			assert.Equal(t, model.Money(0), balance.Outstanding)
+			assert.Equal(t, model.Money(0), balance.PaymentCredit)
+		}
+	})
+
+	t.Run("FXRates", func(t *testing.T) {
+		// Given.
+		s := newStore(t)
+		_, err := s.UpdateFXRates(ctx, []model.FXRate{
+			{Base: "USD", Quote: "EUR", Rate: model.MustParseRate("0.9")},
+			{Base: "EUR", Quote: "USD", Rate: model.MustParseRate("1.1")},
+		})
+		require.NoError(t, err)
+
+		// When.
+		updated, err := s.UpdateFXRates(ctx, []model.FXRate{{Base: "EUR", Quote: "USD", Rate: model.MustParseRate("1.0825")}})
+		require.NoError(t, err)
+		got, err := s.GetFXRate(ctx, "EUR", "USD")
+		require.NoError(t, err)
+		list, err := s.ListFXRates(ctx)
+		require.NoError(t, err)
+		_, missingErr := s.GetFXRate(ctx, "EUR", "GBP")
+
+		// Then.
+		require.Len(t, updated, 1)
+		require.NotNil(t, updated[0].UpdatedAt)
+		assert.Equal(t, model.MustParseRate("1.0825"), got.Rate)
+		assert.Equal(t, updated[0].UpdatedAt.Unix(), got.UpdatedAt.Unix())
+		require.Len(t, list, 2)
+		assert.Equal(t, []model.Currency{"EUR", "USD"}, []model.Currency{list[0].Base, list[1].Base})
+		assert.Equal(t, model.MustParseRate("1.0825"), list[0].Rate)
+		require.ErrorIs(t, missingErr, store.ErrFXRateNotFound)
+	})
+
+	t.Run("Currencies", func(t *testing.T) {
+		// Given.
+		s := newStore(t)
+		account, err := s.CreateAccount(ctx, documentNumber, "EUR")
+		require.NoError(t, err)
+		accountId := *account.AccountID
+		other, err := s.CreateAccount(ctx, documentNumber, model.DefaultCurrency)
+		require.NoError(t, err)
+		otherId := *other.AccountID
+		foreign := model.NewTransaction(nil, accountId, 1, model.MustParseMoney("-10.00"), 0, nil)
+		foreign.Currency = "GBP"
+		require.NoError(t, foreign.Convert(model.FXRate{Base: "GBP", Quote: "EUR", Rate: model.MustParseRate("1.16666667")}))
+
+		// When.
+		debit, err := s.CreateDebit(ctx, *model.NewTransaction(nil, accountId, 1, model.MustParseMoney("-20.00"), 0, nil))
+		require.NoError(t, err)
+		converted, err := s.CreateDebit(ctx, *foreign)
+		require.NoError(t, err)
+		got, err := s.GetTransaction(ctx, *converted.TransactionID)
+		require.NoError(t, err)
+		_, mismatchErr := s.SettlePayment(ctx, model.TransactionImpl{AccountID: accountId, OperationTypeID: 4, Amount: model.MustParseMoney("5.00"), Currency: model.DefaultCurrency})
+		// A transfer's credit must be converted into the destination's currency.
+		_, unconvertedErr := s.CreateTransfer(ctx, *model.NewTransfer(model.TransferRequest{SourceAccountID: otherId, DestinationAccountID: accountId, Amount: model.MustParseMoney("5.00")}))
+		transfer := model.NewTransfer(model.TransferRequest{SourceAccountID: otherId, DestinationAccountID: accountId, Amount: model.MustParseMoney("5.00")})
+		transfer.Credit.Currency = model.DefaultCurrency
+		require.NoError(t, transfer.Credit.Convert(model.FXRate{Base: "USD", Quote: "EUR", Rate: model.MustParseRate("0.9")}))
+		transferred, err := s.CreateTransfer(ctx, *transfer)
+		require.NoError(t, err)
+		balance, err := s.GetBalance(ctx, accountId)
+		require.NoError(t, err)
+
+		// Then.
+		assert.Equal(t, model.Currency("EUR"), debit.Currency)
+		assert.Nil(t, debit.OriginalAmount)
+		assert.Equal(t, model.Currency("EUR"), got.Currency)
+		assert.Equal(t, model.MustParseMoney("-11.67"), got.Amount)
+		assert.Equal(t, model.MustParseMoney("-10.00"), *got.OriginalAmount)
+		assert.Equal(t, model.Currency("GBP"), *got.OriginalCurrency)
+		assert.Equal(t, model.MustParseRate("1.16666667"), *got.FXRate)
+		require.ErrorIs(t, mismatchErr, model.ErrCurrencyMismatch)
+		require.ErrorIs(t, unconvertedErr, model.ErrCurrencyMismatch)
+		assert.Equal(t, model.Currency("USD"), transferred.Debit.Currency)
+		assert.Equal(t, model.MustParseMoney("-5.00"), transferred.Debit.Amount)
+		assert.Equal(t, model.Currency("EUR"), transferred.Credit.Currency)
+		assert.Equal(t, model.MustParseMoney("4.50"), transferred.Credit.Amount)
+		assert.Equal(t, &model.AccountBalance{
+			AccountID:   accountId,
+			Currency:    "EUR",
+			Outstanding: model.MustParseMoney("27.17"),
+			Debts:       []model.OperationDebt{{OperationTypeID: 1, Outstanding: model.MustParseMoney("27.17")}},
+		}, balance)
+	})
+
+	t.Run("Idempotency", func(t *testing.T) {
+		// Given.
+		s := newStore(t)
+		now := time.Now().UTC().Truncate(time.Second)
+
+		// When.
+		reserved, err := s.ReserveIdempotencyKey(ctx, "key-1", "hash", now, now.Add(-time.Hour))
+		require.NoError(t, err)
+		inProgress, err := s.ReserveIdempotencyKey(ctx, "key-1", "hash", now, now.Add(-time.Hour))
+		require.NoError(t, err)
+		require.NoError(t, s.CompleteIdempotencyKey(ctx, "key-1", 201, "application/json", []byte(`{}`)))
+		completed, err := s.ReserveIdempotencyKey(ctx, "key-1", "hash", now, now.Add(-time.Hour))
+		require.NoError(t, err)
+		require.NoError(t, s.ReleaseIdempotencyKey(ctx, "key-1"))
+		released, err := s.ReserveIdempotencyKey(ctx, "key-1", "hash", now, now.Add(-time.Hour))
+		require.NoError(t, err)
+
+		// Then.
+		assert.Nil(t, reserved)
+		require.NotNil(t, inProgress)
+		assert.True(t, inProgress.InProgress())
+		require.NotNil(t, completed)
+		assert.Equal(t, 201, completed.StatusCode)
+		assert.Equal(t, []byte(`{}`), completed.Body)
+		assert.Nil(t, released)
+	})
+
+	t.Run("IdempotencyPurge", func(t *testing.T) {
+		// Given.
+		s := newStore(t)
+		now := time.Now().UTC().Truncate(time.Second)
+		_, err := s.ReserveIdempotencyKey(ctx, "old", "hash", now.Add(-2*time.Hour), now.Add(-3*time.Hour))
+		require.NoError(t, err)
+		_, err = s.ReserveIdempotencyKey(ctx, "new", "hash", now, now.Add(-time.Hour))
+		require.NoError(t, err)
+
+		// When.
+		purged, err := s.PurgeIdempotencyKeys(ctx, now.Add(-time.Hour))
+		require.NoError(t, err)
+
+		// Then.
+		assert.Equal(t, 1, purged)
+		old, err := s.ReserveIdempotencyKey(ctx, "old", "other", now, now.Add(-3*time.Hour))
+		require.NoError(t, err)
+		assert.Nil(t, old, "the expired key should have been purged")
+		kept, err := s.ReserveIdempotencyKey(ctx, "new", "hash", now, now.Add(-3*time.Hour))
+		require.NoError(t, err)
+		assert.NotNil(t, kept)
+	})
+}
+
+func transactionIDs(transactions model.Transactions) []int {
+	ids := []int{}
+	for _, transaction := range transactions {
+		ids = append(ids, *transaction.TransactionID)
+	}
+	return ids
+}